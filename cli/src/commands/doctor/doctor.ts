@@ -131,7 +131,7 @@ export const doctorCmd = new Command('doctor')
         const probeExists = existsSync(goProbe) || existsSync(goProbe + '.exe');
         
         if (probeExists) {
-          const probeResult = execSync(`"${goProbe}" --json --dns-only`, { 
+          const probeResult = execSync(`"${goProbe}" --format json --dns-only`, { 
             stdio: 'pipe', 
             timeout: 15000,
             env: { ...process.env, AWS_REGION: region }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/aws/smithy-go"
+)
+
+// awsErrorFixes are fixes for common Bedrock and STS error codes, for probes
+// with no more specific advice of their own. The SDK's messages for these
+// rarely say what to change.
+var awsErrorFixes = map[string]string{
+	"AccessDeniedException":         "This principal is not allowed to make the call; allow the action named in the error in its IAM policy, and check for an SCP, permissions boundary, or VPC endpoint policy denying it",
+	"ThrottlingException":           "Requests are being throttled; retry later, spread load with a cross-region inference profile, or request a quota increase in the Service Quotas console",
+	"ValidationException":           "AWS rejected the request parameters; check the model ID, region, and any IDs passed on the command line against the error message",
+	"ExpiredTokenException":         expiredCredentialsFix,
+	"ExpiredToken":                  expiredCredentialsFix,
+	"UnrecognizedClientException":   "AWS does not recognize the access key; check that it has not been deleted or rotated, belongs to this partition, and that the region is enabled for the account",
+	"ServiceQuotaExceededException": "A Bedrock service quota is used up; check usage in the Service Quotas console and request an increase, or free up the resources counting toward it",
+}
+
+// awsErrorFix returns the fix for the AWS error code in err's chain, or
+// fallback when err carries no code awsErrorFixes knows
+func awsErrorFix(err error, fallback string) string {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return fallback
+	}
+	if fix, ok := awsErrorFixes[apiErr.ErrorCode()]; ok {
+		return fix
+	}
+	return fallback
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestAWSErrorFix(t *testing.T) {
+	const fallback = "Check credentials and network access to Bedrock"
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&smithy.GenericAPIError{Code: "UnrecognizedClientException", Message: "The security token included in the request is invalid."}, "does not recognize the access key"},
+		{fmt.Errorf("operation error Bedrock: GetGuardrail, %w", &smithy.GenericAPIError{Code: "ThrottlingException"}), "throttled"},
+		{&smithy.GenericAPIError{Code: "ExpiredTokenException"}, expiredCredentialsFix},
+		{&smithy.GenericAPIError{Code: "InternalServerException"}, fallback},
+		{errors.New("connection reset"), fallback},
+	}
+	for _, tt := range tests {
+		if got := awsErrorFix(tt.err, fallback); !strings.Contains(got, tt.want) {
+			t.Errorf("awsErrorFix(%v) = %q, want it to contain %q", tt.err, got, tt.want)
+		}
+	}
+
+	// A probe's own advice for a code still wins over the generic fix
+	result := guardrailErrorResult("Guardrail - gr1", "gr1", "us-east-1", &smithy.GenericAPIError{Code: "AccessDeniedException"})
+	if result.Fix != "Allow bedrock:GetGuardrail on the guardrail for this principal" {
+		t.Errorf("guardrailErrorResult() fix = %q, want the GetGuardrail permission", result.Fix)
+	}
+	result = loggingErrorResult("Invocation Logging", "us-east-1", &smithy.GenericAPIError{Code: "ServiceQuotaExceededException"})
+	if result.Fix != awsErrorFixes["ServiceQuotaExceededException"] {
+		t.Errorf("loggingErrorResult() fix = %q, want the quota fix", result.Fix)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Severity order used to tell regressions from improvements
+var statusRank = map[string]int{"pass": 0, "warn": 1, "fail": 2}
+
+// loadBaseline reads a report previously written with --format json or --output
+func loadBaseline(path string) (*ProbeOutput, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("baseline %s does not exist", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline %s: %w", path, err)
+	}
+
+	var baseline ProbeOutput
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+// normalizeBaseline keeps only what --baseline compares, so a saved baseline
+// doesn't change between runs with the same outcome: messages carry latencies
+// and addresses, and the summary carries timings
+func normalizeBaseline(results []CheckResult) ProbeOutput {
+	checks := make([]CheckResult, 0, len(results))
+	for _, result := range results {
+		checks = append(checks, CheckResult{
+			Name:     result.Name,
+			Status:   result.Status,
+			Category: result.Category,
+			Region:   result.Region,
+		})
+	}
+	slices.SortFunc(checks, func(a, b CheckResult) int { return strings.Compare(a.Name, b.Name) })
+	return ProbeOutput{Checks: checks}
+}
+
+// saveBaseline writes results to path in the form --baseline reads
+func saveBaseline(path string, results []CheckResult) error {
+	data, err := json.MarshalIndent(normalizeBaseline(results), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline %s: %w", path, err)
+	}
+	return nil
+}
+
+// diffBaseline keeps only the checks whose status differs from the baseline.
+// The exit code reflects regressions alone, so a check that recovered or went
+// from fail to warn never makes the run fail.
+func diffBaseline(baseline *ProbeOutput, results []CheckResult) ([]CheckResult, int) {
+	previous := make(map[string]string, len(baseline.Checks))
+	for _, check := range baseline.Checks {
+		previous[check.Name] = check.Status
+	}
+
+	changed := []CheckResult{}
+	var regressions []CheckResult
+	for _, result := range results {
+		was, ok := previous[result.Name]
+		if !ok || was == result.Status {
+			continue
+		}
+
+		if statusRank[result.Status] > statusRank[was] {
+			regressions = append(regressions, result)
+		}
+		result.Message = fmt.Sprintf("Was %s, now %s: %s", was, result.Status, result.Message)
+		changed = append(changed, result)
+	}
+	return changed, exitCode(regressions)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffBaseline(t *testing.T) {
+	baseline := &ProbeOutput{Checks: []CheckResult{
+		{Name: "DNS - Bedrock Runtime", Status: "pass"},
+		{Name: "TCP - Bedrock Runtime", Status: "fail"},
+		{Name: "TLS - Bedrock Runtime", Status: "pass"},
+		{Name: "Proxy - Bedrock Runtime", Status: "fail"},
+	}}
+
+	tests := []struct {
+		name    string
+		results []CheckResult
+		changed []string
+		code    int
+	}{
+		{
+			name: "unchanged",
+			results: []CheckResult{
+				{Name: "DNS - Bedrock Runtime", Status: "pass"},
+				{Name: "TCP - Bedrock Runtime", Status: "fail"},
+			},
+			code: exitPass,
+		},
+		{
+			name: "improvements only",
+			results: []CheckResult{
+				{Name: "TCP - Bedrock Runtime", Status: "pass"},
+				{Name: "Proxy - Bedrock Runtime", Status: "warn"},
+			},
+			changed: []string{"TCP - Bedrock Runtime", "Proxy - Bedrock Runtime"},
+			code:    exitPass,
+		},
+		{
+			name: "regression to warn",
+			results: []CheckResult{
+				{Name: "DNS - Bedrock Runtime", Status: "warn"},
+				{Name: "TCP - Bedrock Runtime", Status: "pass"},
+			},
+			changed: []string{"DNS - Bedrock Runtime", "TCP - Bedrock Runtime"},
+			code:    exitWarn,
+		},
+		{
+			name: "regression to fail",
+			results: []CheckResult{
+				{Name: "DNS - Bedrock Runtime", Status: "warn"},
+				{Name: "TLS - Bedrock Runtime", Status: "fail"},
+			},
+			changed: []string{"DNS - Bedrock Runtime", "TLS - Bedrock Runtime"},
+			code:    exitFail,
+		},
+		{
+			name:    "new check is not drift",
+			results: []CheckResult{{Name: "Clock - NTP Skew", Status: "fail"}},
+			code:    exitPass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, code := diffBaseline(baseline, tt.results)
+			var names []string
+			for _, result := range changed {
+				names = append(names, result.Name)
+			}
+			if len(names) != len(tt.changed) {
+				t.Fatalf("changed = %v, want %v", names, tt.changed)
+			}
+			for i := range names {
+				if names[i] != tt.changed[i] {
+					t.Fatalf("changed = %v, want %v", names, tt.changed)
+				}
+			}
+			if code != tt.code {
+				t.Errorf("exit code = %d, want %d", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestSaveBaseline(t *testing.T) {
+	dir := t.TempDir()
+	first := []CheckResult{
+		{Name: "TCP - bedrock-runtime", Status: "pass", Message: "Connected (41ms)", Category: "network", LatencyMs: 41},
+		{Name: "DNS - bedrock-runtime", Status: "pass", Message: "Resolved to 52.94.0.1", Addresses: []string{"52.94.0.1"}},
+	}
+	second := []CheckResult{
+		{Name: "DNS - bedrock-runtime", Status: "pass", Message: "Resolved to 52.94.0.7", Addresses: []string{"52.94.0.7"}},
+		{Name: "TCP - bedrock-runtime", Status: "pass", Message: "Connected (97ms)", Category: "network", LatencyMs: 97},
+	}
+	var saved []string
+	for i, results := range [][]CheckResult{first, second} {
+		path := filepath.Join(dir, fmt.Sprintf("baseline-%d.json", i))
+		if err := saveBaseline(path, results); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved = append(saved, string(data))
+	}
+	if saved[0] != saved[1] {
+		t.Errorf("baselines of runs with the same statuses differ:\n%s\n%s", saved[0], saved[1])
+	}
+
+	baseline, err := loadBaseline(filepath.Join(dir, "baseline-0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, code := diffBaseline(baseline, second); len(changed) != 0 || code != exitPass {
+		t.Errorf("diff against a saved baseline = %v, %d; want no changes", changed, code)
+	}
+}
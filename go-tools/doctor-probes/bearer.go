@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const bearerTokenEnv = "AWS_BEARER_TOKEN_BEDROCK"
+
+// Warn when the token expires within this window
+const bearerExpiryWarn = time.Hour
+
+// Prefix of short-term Bedrock API keys, which wrap a presigned URL
+const shortTermKeyPrefix = "bedrock-api-key-"
+
+// bearerTokenExpiry extracts the expiry from a JWT exp claim or a short-term
+// Bedrock API key. Signatures are not verified; ok is false when the token
+// carries no expiry, as with long-term API keys.
+func bearerTokenExpiry(token string) (expiry time.Time, ok bool, err error) {
+	if rest, found := strings.CutPrefix(token, shortTermKeyPrefix); found {
+		decoded, err := base64.StdEncoding.DecodeString(rest)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("short-term API key is not valid base64 (truncated?)")
+		}
+		_, rawQuery, _ := strings.Cut(string(decoded), "?")
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("short-term API key does not contain a presigned URL")
+		}
+		signed, err := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("short-term API key has no valid X-Amz-Date")
+		}
+		seconds, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("short-term API key has no valid X-Amz-Expires")
+		}
+		return signed.Add(time.Duration(seconds) * time.Second), true, nil
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false, nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("JWT payload is not valid base64url (truncated?)")
+	}
+	var claims struct {
+		Exp *int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, false, fmt.Errorf("JWT payload is not valid JSON (truncated?)")
+	}
+	if claims.Exp == nil {
+		return time.Time{}, false, nil
+	}
+	return time.Unix(*claims.Exp, 0), true, nil
+}
+
+// runBearerTokenChecks validates AWS_BEARER_TOKEN_BEDROCK locally and, with
+// --verify-token, by sending it to Bedrock. The token value is never logged or
+// included in results.
+func runBearerTokenChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	name := "Bearer Token - " + bearerTokenEnv
+	token := strings.TrimSpace(os.Getenv(bearerTokenEnv))
+	if token == "" {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s is set but empty", bearerTokenEnv),
+			Fix:     fmt.Sprintf("Generate an API key in the Bedrock console and export %s=..., or unset it to use SigV4 credentials", bearerTokenEnv),
+		})
+		return results
+	}
+
+	expiry, hasExpiry, err := bearerTokenExpiry(token)
+	switch {
+	case err != nil:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s is malformed: %v", bearerTokenEnv, err),
+			Fix:     fmt.Sprintf("Re-copy the full API key into %s", bearerTokenEnv),
+		})
+		return results
+	case hasExpiry && time.Until(expiry) <= 0:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s expired at %s", bearerTokenEnv, expiry.UTC().Format(time.RFC3339)),
+			Fix:     fmt.Sprintf("Generate a new API key and update %s", bearerTokenEnv),
+		})
+		return results
+	case hasExpiry && time.Until(expiry) < bearerExpiryWarn:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("%s expires in %s", bearerTokenEnv, time.Until(expiry).Round(time.Minute)),
+			Fix:     fmt.Sprintf("Generate a new API key before it expires and update %s", bearerTokenEnv),
+		})
+	case hasExpiry:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("%s is well-formed and valid until %s", bearerTokenEnv, expiry.UTC().Format(time.RFC3339)),
+		})
+	default:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("%s is set (%d characters, no embedded expiry)", bearerTokenEnv, len(token)),
+		})
+	}
+
+	if cfg.VerifyToken {
+		results = append(results, verifyBearerToken(ctx, cfg, region, token))
+	}
+	return results
+}
+
+// verifyBearerToken sends an InvokeModel request with an empty body, which
+// Bedrock rejects with a 400 only after accepting the token
+func verifyBearerToken(ctx context.Context, cfg *Config, region, token string) CheckResult {
+	name := "Bearer Token - Accepted"
+	modelID := cfg.CheckModel
+	if modelID == "" {
+		modelID = cfg.smokeModel()
+	}
+	endpoint := fmt.Sprintf("https://%s/model/%s/invoke", cfg.endpointAddr("bedrock-runtime", region), url.PathEscape(modelID))
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return CheckResult{Name: name, Status: "fail", Message: fmt.Sprintf("Failed to build request for %s: %v", endpoint, err)}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	logger.Debug("verifying bearer token", "url", endpoint)
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	durationMs := time.Since(start).Milliseconds()
+	if err != nil {
+		return CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("Request to Bedrock in %s failed: %v", region, err),
+			Fix:        "Check proxy settings and that outbound HTTPS to AWS is allowed",
+			DurationMs: durationMs,
+		}
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest, resp.StatusCode < 300:
+		return CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("Bedrock in %s accepted the token", region),
+			DurationMs: durationMs,
+		}
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("Bedrock in %s rejected the token (%s)", region, resp.Status),
+			Fix:        fmt.Sprintf("Check the API key was created in this account, hasn't been revoked, and has access to %s", modelID),
+			DurationMs: durationMs,
+		}
+	default:
+		return CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("Could not confirm the token: Bedrock in %s returned %s", region, resp.Status),
+			Fix:        "Retry later",
+			DurationMs: durationMs,
+		}
+	}
+}
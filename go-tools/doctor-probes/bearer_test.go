@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBearerTokenExpiry(t *testing.T) {
+	jwt := func(payload string) string {
+		return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+	}
+	shortTerm := func(query string) string {
+		return shortTermKeyPrefix + base64.StdEncoding.EncodeToString([]byte("bedrock.amazonaws.com/?Action=CallWithBearerToken&"+query))
+	}
+	exp := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		token   string
+		want    time.Time
+		wantOK  bool
+		wantErr bool
+	}{
+		{"jwt with exp", jwt(fmt.Sprintf(`{"sub":"x","exp":%d}`, exp.Unix())), exp, true, false},
+		{"jwt without exp", jwt(`{"sub":"x"}`), time.Time{}, false, false},
+		{"truncated jwt", "eyJhbGciOiJIUzI1NiJ9.eyJle!.c2ln", time.Time{}, false, true},
+		{"short-term key", shortTerm("X-Amz-Date=20300102T000405Z&X-Amz-Expires=10800"), exp, true, false},
+		{"short-term key without date", shortTerm("X-Amz-Expires=10800"), time.Time{}, false, true},
+		{"truncated short-term key", shortTermKeyPrefix + "YmVkcm9ja", time.Time{}, false, true},
+		{"long-term key", "ABSKQmVkcm9ja0FQSUtleS1hYmNkZWZnaGlqa2xtbm9w", time.Time{}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := bearerTokenExpiry(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bearerTokenExpiry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("bearerTokenExpiry() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
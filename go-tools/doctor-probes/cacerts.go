@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"runtime"
+)
+
+const caCertificatesFix = "Install the CA bundle (apt-get install ca-certificates, apk add ca-certificates, or yum install ca-certificates); distroless images need a base with certificates"
+
+// runTrustStoreChecks reports the roots Go will verify AWS certificates
+// against, and the AWS SDK's own bundle when AWS_CA_BUNDLE replaces them
+func runTrustStoreChecks() []CheckResult {
+	results := []CheckResult{checkSystemTrustStore()}
+	if bundle := os.Getenv("AWS_CA_BUNDLE"); bundle != "" {
+		results = append(results, checkCABundle("CA Certificates - AWS_CA_BUNDLE", bundle))
+	}
+	return results
+}
+
+func checkSystemTrustStore() CheckResult {
+	name := "CA Certificates - System"
+	source, override := "the system trust store", ""
+	for _, env := range []string{"SSL_CERT_FILE", "SSL_CERT_DIR"} {
+		if value := os.Getenv(env); value != "" {
+			source, override = env+"="+value, env
+			break
+		}
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Could not load %s: %v", source, err),
+			Fix:     caCertificatesFix,
+		}
+	}
+
+	// macOS and Windows verify through the OS, so the pool can't list its roots
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("Using the %s platform verifier and its trust store", runtime.GOOS),
+		}
+	}
+
+	// Subjects is only incomplete for the platform verifiers handled above
+	roots := len(pool.Subjects())
+	if roots == 0 {
+		fix := caCertificatesFix
+		if override != "" {
+			fix = fmt.Sprintf("Point %s at PEM certificates, or unset it to use the system trust store", override)
+		}
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s has no trusted root certificates, so every TLS handshake will fail", source),
+			Fix:     fix,
+		}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("Loaded %d trusted roots from %s", roots, source),
+	}
+}
+
+// checkCABundle parses a PEM bundle the way the AWS SDK does for AWS_CA_BUNDLE
+func checkCABundle(name, path string) CheckResult {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Custom CA bundle %s cannot be read: %v", path, err),
+			Fix:     "Fix the AWS_CA_BUNDLE path, or unset it to use the system trust store",
+		}
+	}
+
+	certs := 0
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs++
+		}
+	}
+	if certs == 0 {
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Custom CA bundle %s contains no PEM certificates; the AWS SDK will reject it", path),
+			Fix:     "Export the CA certificates in PEM format into AWS_CA_BUNDLE, or unset it",
+		}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("AWS SDK calls use the custom CA bundle %s (%d certificates) instead of the system trust store", path, certs),
+	}
+}
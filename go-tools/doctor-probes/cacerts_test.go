@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.pem")
+	if err := os.WriteFile(valid, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, path, want string
+	}{
+		{"valid bundle", valid, "pass"},
+		{"no certificates", empty, "fail"},
+		{"missing file", filepath.Join(dir, "missing.pem"), "fail"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := checkCABundle("CA Certificates - Test", tt.path); result.Status != tt.want {
+				t.Errorf("status = %s (%s), want %s", result.Status, result.Message, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Answers 204 with an empty body; captive portals substitute a login page or redirect
+const defaultCaptivePortalURL = "http://connectivitycheck.gstatic.com/generate_204"
+
+const captivePortalFix = "Open a browser and sign in to the Wi-Fi or guest network, then re-run the checks"
+
+// runCaptivePortalChecks fetches a generate_204 URL over plain HTTP, which is
+// what captive portals intercept while DNS and TCP to AWS still appear to work
+func runCaptivePortalChecks(ctx context.Context, cfg *Config) []CheckResult {
+	name := "Captive Portal - Connectivity Check"
+	url := cfg.CaptivePortalURL
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to build request for %s: %v", url, err),
+			Fix:     "Check captive_portal_url in the config file",
+		}}
+	}
+
+	// A portal's redirect is the evidence, so never follow it
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	logger.Debug("checking for a captive portal", "url", url)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		// Not reaching the check URL says nothing about a portal, so leave the verdict to the other probes
+		return []CheckResult{{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("GET %s failed, so a captive portal could not be ruled out: %v", url, err),
+			Fix:        "Ignore this if outbound HTTP is blocked on purpose; otherwise check proxy settings",
+			ErrorKind:  newProbeError("", err).Kind,
+			DurationMs: time.Since(start).Milliseconds(),
+		}}
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	durationMs := time.Since(start).Milliseconds()
+	logger.Debug("captive portal check finished", "url", url, "status", resp.Status, "body_bytes", len(body))
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent && len(body) == 0:
+		return []CheckResult{{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("GET %s returned 204 with no body; no captive portal detected", url),
+			DurationMs: durationMs,
+		}}
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return []CheckResult{{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("GET %s was redirected to %s; the network is holding traffic behind a captive portal", url, resp.Header.Get("Location")),
+			Fix:        captivePortalFix,
+			DurationMs: durationMs,
+		}}
+	}
+	return []CheckResult{{
+		Name:       name,
+		Status:     "fail",
+		Message:    fmt.Sprintf("GET %s returned %s with %d bytes instead of an empty 204; a captive portal is likely answering for the internet", url, resp.Status, len(body)),
+		Fix:        captivePortalFix,
+		DurationMs: durationMs,
+	}}
+}
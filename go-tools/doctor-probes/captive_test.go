@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCaptivePortalChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{"empty 204", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}, "pass"},
+		{"login page", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>Please sign in</html>"))
+		}, "fail"},
+		{"redirect", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "http://portal.example/login", http.StatusFound)
+		}, "fail"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			cfg := newConfig()
+			cfg.CaptivePortalURL = server.URL + "/generate_204"
+			results := runCaptivePortalChecks(context.Background(), cfg)
+			if len(results) != 1 || results[0].Status != tt.want {
+				t.Fatalf("results = %+v, want one %s", results, tt.want)
+			}
+			if tt.want == "fail" && results[0].Fix != captivePortalFix {
+				t.Errorf("fix = %q, want the sign-in advice", results[0].Fix)
+			}
+		})
+	}
+}
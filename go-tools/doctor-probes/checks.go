@@ -0,0 +1,743 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Check describes one check category. Everything that lists, filters, runs, or
+// explains checks derives from checkRegistry.
+type Check struct {
+	// Config file and --only/--skip name
+	Category string
+	// Reported as each result's category: network, auth, model, quota, local, config or plugins
+	Group string
+	// Display name used as the prefix of each result
+	Name string
+	// Cross-cutting subsets selected with --tag, e.g. streaming or offline
+	Tags []string
+	// What the check probes
+	Description string
+	// Network access or permissions the check needs
+	Requires string
+	// What a failure usually means
+	Failure string
+	// Flag that enables an opt-in check; empty for checks that run by default
+	Flag string
+	// Config file setting an opt-in check can't run without, and whether it is
+	// set. --tag passes over the check while it is unset, and --only rejects it.
+	Setting    string
+	Configured func(cfg *Config) bool
+	// Makes billed calls, so only its own flag or --only selects it, never --tag
+	Billable bool
+	// Runs by default when no categories are selected
+	Default bool
+	// How results relate to the probed regions
+	Scope RegionScope
+	// Results time a network round trip, so --max-latency applies to them
+	Latency bool
+	// Runs the check for one region; global checks get the first region
+	Run func(ctx context.Context, cfg *Config, region string) []CheckResult
+}
+
+// RegionScope says whether a check's outcome depends on the region it probes
+type RegionScope int
+
+const (
+	// Results depend on the region and are always reported per region
+	ScopeRegional RegionScope = iota
+	// Runs per region, but depends mostly on client state such as environment
+	// variables, so results that come out identical in every region are merged
+	ScopeClient
+	// Not region-specific, so it runs once per invocation
+	ScopeGlobal
+)
+
+// sdkCheck adapts an AWS SDK-backed check so it is bounded by the probe timeout
+func sdkCheck(check func(ctx context.Context, cfg *Config, region string) []CheckResult) func(context.Context, *Config, string) []CheckResult {
+	return func(ctx context.Context, cfg *Config, region string) []CheckResult {
+		return runSDKCheck(ctx, cfg, func(ctx context.Context) []CheckResult {
+			return check(ctx, cfg, region)
+		})
+	}
+}
+
+var checkRegistry = []Check{
+	{
+		Category:    "region",
+		Group:       "config",
+		Name:        "Region",
+		Tags:        []string{"offline"},
+		Description: "Checks that Bedrock is offered in each region, using a list built into the binary",
+		Requires:    "Nothing",
+		Failure:     "The region is valid but has no Bedrock endpoints",
+		Default:     true,
+		Run:         runBedrockRegionChecks,
+	},
+	{
+		Category:    "dns",
+		Group:       "network",
+		Name:        "DNS",
+		Tags:        []string{"streaming"},
+		Description: "Resolves the hostnames of the --services endpoints (Bedrock Runtime and the control plane by default) for each region, and reports how many resolved",
+		Requires:    "A working resolver (system or --resolver)",
+		Failure:     "The resolver is unreachable, blocks AWS names, or a private hosted zone is missing records",
+		Default:     true,
+		Latency:     true,
+		Run:         runDNSChecks,
+	},
+	{
+		Category:    "tcp",
+		Group:       "network",
+		Name:        "TCP",
+		Tags:        []string{"streaming"},
+		Description: "Opens a TCP connection on port 443 to each --services endpoint (Bedrock Runtime and the control plane by default)",
+		Requires:    "Outbound TCP 443",
+		Failure:     "A firewall, security group, or NACL drops traffic to AWS",
+		Default:     true,
+		Latency:     true,
+		Run:         runTCPChecks,
+	},
+	{
+		Category:    "tls",
+		Group:       "network",
+		Name:        "TLS",
+		Tags:        []string{"streaming"},
+		Description: "Completes a TLS handshake with the Bedrock Runtime endpoint and verifies the certificate chain",
+		Requires:    "Outbound TCP 443",
+		Failure:     "A proxy or middlebox is intercepting TLS, or the system CA bundle is out of date",
+		Default:     true,
+		Latency:     true,
+		Run:         runTLSChecks,
+	},
+	{
+		Category:    "proxy",
+		Group:       "network",
+		Name:        "Proxy",
+		Tags:        []string{"streaming", "offline"},
+		Description: "Reports whether HTTPS_PROXY/NO_PROXY route Bedrock traffic through a proxy and whether the proxy is reachable",
+		Requires:    "Access to the configured proxy, if any",
+		Failure:     "The proxy is down or refuses CONNECT to AWS",
+		Default:     true,
+		Scope:       ScopeClient,
+		Run:         runProxyChecks,
+	},
+	{
+		Category:    "ipranges",
+		Group:       "network",
+		Name:        "IP Ranges",
+		Description: "Checks resolved addresses against the published AWS ip-ranges.json",
+		Requires:    "HTTPS access to ip-ranges.amazonaws.com (cached between runs)",
+		Failure:     "DNS is being answered by something other than AWS, e.g. a captive portal or DNS hijack",
+		Flag:        "--ip-ranges",
+		Run:         runIPRangeChecks,
+	},
+	{
+		Category:    "creds",
+		Group:       "auth",
+		Name:        "Credentials",
+		Tags:        []string{"aws-api"},
+		Description: "Calls STS GetCallerIdentity with the default credential chain",
+		Requires:    "Outbound HTTPS to STS and valid AWS credentials",
+		Failure:     "No credentials were found, they have expired, or STS is blocked",
+		Flag:        "--creds",
+		Scope:       ScopeGlobal,
+		Run:         sdkCheck(runCredentialChecks),
+	},
+	{
+		Category:    "credsources",
+		Group:       "auth",
+		Name:        "Credential Sources",
+		Tags:        []string{"offline"},
+		Description: "Lists the credential sources present (environment, profile, SSO, container, instance role) and which one the SDK will use",
+		Requires:    "Read access to ~/.aws/config and ~/.aws/credentials",
+		Failure:     "More than one source is present, so precedence may pick credentials other than the ones intended",
+		Flag:        "--credential-sources",
+		Scope:       ScopeGlobal,
+		Run: func(ctx context.Context, cfg *Config, _ string) []CheckResult {
+			return runCredentialSourceChecks(ctx, cfg)
+		},
+	},
+	{
+		Category:    "smoke",
+		Group:       "model",
+		Name:        "Smoke Test",
+		Tags:        []string{"aws-api", "streaming"},
+		Description: "Invokes a small model with a one-token request",
+		Requires:    "Credentials with bedrock:InvokeModel and access to the smoke-test model",
+		Failure:     "Model access is not enabled or IAM denies InvokeModel",
+		Flag:        "--smoke-test",
+		Billable:    true,
+		Run:         sdkCheck(runSmokeTest),
+	},
+	{
+		Category:    "smokestream",
+		Group:       "model",
+		Name:        "Streaming Smoke Test",
+		Tags:        []string{"aws-api", "streaming"},
+		Description: "Invokes a small model through InvokeModelWithResponseStream and times the first chunk",
+		Requires:    "Credentials with bedrock:InvokeModelWithResponseStream and access to the smoke-test model",
+		Failure:     "The stream is denied, never delivers a chunk, or is slow to start because a proxy buffers it",
+		Flag:        "--smoke-stream",
+		Billable:    true,
+		Run:         sdkCheck(runSmokeStreamTest),
+	},
+	{
+		Category:    "clock",
+		Group:       "local",
+		Name:        "Clock",
+		Description: "Measures local clock skew against an NTP server",
+		Requires:    "Outbound UDP 123",
+		Failure:     "The clock is far enough off that SigV4 signatures will be rejected",
+		Flag:        "--clock",
+		Scope:       ScopeGlobal,
+		Run: func(ctx context.Context, cfg *Config, _ string) []CheckResult {
+			return runClockChecks(ctx, cfg)
+		},
+	},
+	{
+		Category:    "rdns",
+		Group:       "network",
+		Name:        "Reverse DNS",
+		Description: "Looks up PTR records of resolved addresses for hints of a different region",
+		Requires:    "A resolver that answers PTR queries",
+		Failure:     "Only warns; traffic may be routed to an unexpected region",
+		Flag:        "--reverse-dns",
+		Run:         runReverseDNSChecks,
+	},
+	{
+		Category:    "profile",
+		Group:       "config",
+		Name:        "AWS Config",
+		Tags:        []string{"offline"},
+		Description: "Reports the active shared config profile and whether it sets a region",
+		Requires:    "Read access to ~/.aws/config",
+		Failure:     "AWS_PROFILE names a profile that does not exist",
+		Flag:        "--aws-config",
+		Scope:       ScopeGlobal,
+		Run: sdkCheck(func(ctx context.Context, cfg *Config, _ string) []CheckResult {
+			return runProfileChecks(ctx, cfg.Profile)
+		}),
+	},
+	{
+		Category:    "sdkendpoint",
+		Group:       "config",
+		Name:        "SDK Endpoint",
+		Tags:        []string{"offline"},
+		Description: "Resolves the endpoints the AWS SDK will call and compares them with the hosts the network checks probe",
+		Requires:    "Read access to ~/.aws/config",
+		Failure:     "AWS_ENDPOINT_URL_*, a profile's endpoint_url, or FIPS/dual-stack settings send SDK calls somewhere else",
+		Flag:        "--sdk-endpoint",
+		Run:         sdkCheck(runSDKEndpointChecks),
+	},
+	{
+		Category:    "sdkenv",
+		Group:       "config",
+		Name:        "SDK Env",
+		Tags:        []string{"offline"},
+		Description: "Reports AWS SDK toggles such as AWS_EC2_METADATA_DISABLED and AWS_STS_REGIONAL_ENDPOINTS and warns on risky values",
+		Requires:    "Nothing",
+		Failure:     "A toggle has a value the SDKs reject, or disables something this host relies on",
+		Flag:        "--sdk-env",
+		Scope:       ScopeGlobal,
+		Run: func(_ context.Context, _ *Config, _ string) []CheckResult {
+			return runSDKEnvChecks(currentEnvironment())
+		},
+	},
+	{
+		Category:    "imds",
+		Group:       "auth",
+		Name:        "Instance Role",
+		Tags:        []string{"compute"},
+		Description: "Reports the EC2 instance profile or ECS task role and whether IMDSv2 is enforced",
+		Requires:    "Access to instance or container metadata",
+		Failure:     "The instance or task has no role attached",
+		Flag:        "--instance-role",
+		Scope:       ScopeGlobal,
+		Run: sdkCheck(func(ctx context.Context, _ *Config, _ string) []CheckResult {
+			return runInstanceRoleChecks(ctx)
+		}),
+	},
+	{
+		Category:    "lambda",
+		Group:       "network",
+		Name:        "Lambda",
+		Tags:        []string{"compute"},
+		Description: "Inside a Lambda function, reports its configuration and checks it can resolve Bedrock Runtime",
+		Requires:    "Nothing beyond DNS",
+		Failure:     "A VPC-attached function has no NAT gateway or Bedrock interface endpoint",
+		Flag:        "$AWS_LAMBDA_FUNCTION_NAME",
+		Scope:       ScopeGlobal,
+		Run:         runLambdaChecks,
+	},
+	{
+		Category:    "plugins",
+		Group:       "plugins",
+		Name:        "Plugins",
+		Description: "Runs each executable in the plugin directory as an external check",
+		Requires:    "Whatever the plugins need",
+		Failure:     "A plugin reported a failure, crashed, or printed invalid output",
+		Flag:        "--plugin-dir",
+		Setting:     "plugin_dir",
+		Configured:  func(cfg *Config) bool { return cfg.PluginDir != "" },
+		Scope:       ScopeGlobal,
+		Run:         runPluginChecks,
+	},
+	{
+		Category:    "http",
+		Group:       "network",
+		Name:        "HTTPS",
+		Description: "Times a full unauthenticated HTTPS request to the Bedrock Runtime endpoint",
+		Requires:    "Outbound TCP 443",
+		Failure:     "The endpoint or an intermediate proxy is returning server errors or is slow",
+		Flag:        "--http-latency",
+		Latency:     true,
+		Run:         runHTTPChecks,
+	},
+	{
+		Category:    "model",
+		Group:       "model",
+		Name:        "Model Access",
+		Tags:        []string{"aws-api"},
+		Description: "Verifies a model ID is enabled for the account without generating tokens",
+		Requires:    "Credentials with bedrock:GetFoundationModel and bedrock:InvokeModel",
+		Failure:     "Model access has not been granted in the Bedrock console or IAM denies it",
+		Flag:        "--check-model",
+		Setting:     "check_model",
+		Configured:  func(cfg *Config) bool { return cfg.CheckModel != "" },
+		Run:         sdkCheck(runModelAccessChecks),
+	},
+	{
+		Category:    "modelid",
+		Group:       "model",
+		Name:        "Model ID",
+		Tags:        []string{"aws-api"},
+		Description: "Validates the Claude Code model ID against the region's foundation models and checks it is enabled",
+		Requires:    "Credentials with bedrock:ListFoundationModels and bedrock:InvokeModel",
+		Failure:     "ANTHROPIC_MODEL is mistyped, not offered in the region, or not enabled for the account",
+		Flag:        "--validate-model",
+		Run:         sdkCheck(runModelIDChecks),
+	},
+	{
+		Category:    "http2",
+		Group:       "network",
+		Name:        "HTTP/2",
+		Tags:        []string{"streaming"},
+		Description: "Offers h2 over ALPN to the Bedrock Runtime endpoint, through any proxy, and expects HTTP/2 back",
+		Requires:    "Outbound HTTPS to Bedrock Runtime",
+		Failure:     "A proxy or TLS-inspecting firewall downgrades to HTTP/1.1, which breaks response streaming",
+		Flag:        "--http2",
+		Run:         runHTTP2Checks,
+	},
+	{
+		Category:    "mtu",
+		Group:       "network",
+		Name:        "MTU",
+		Tags:        []string{"streaming"},
+		Description: "Sends progressively larger requests over TLS to find the largest that completes",
+		Requires:    "Outbound TCP 443",
+		Failure:     "Path MTU discovery is broken, usually by a VPN or tunnel dropping ICMP",
+		Flag:        "--mtu",
+		Run:         runMTUChecks,
+	},
+	{
+		Category:    "upload",
+		Group:       "network",
+		Name:        "Upload Throughput",
+		Tags:        []string{"streaming"},
+		Description: "Uploads a 256 KiB payload to Bedrock Runtime over HTTPS and measures the throughput",
+		Requires:    "Outbound TCP 443",
+		Failure:     "Only warns: the connection works but stalls or crawls on larger transfers, as with asymmetric routing or broken window scaling",
+		Flag:        "--upload-throughput",
+		Run:         runThroughputChecks,
+	},
+	{
+		Category:    "logs",
+		Group:       "network",
+		Name:        "CloudWatch Logs",
+		Description: "Resolves and connects to the regional CloudWatch Logs endpoint that BCCE telemetry is sent to",
+		Requires:    "Outbound DNS and TCP 443 to logs.<region>.amazonaws.com",
+		Failure:     "An egress firewall or missing VPC endpoint allows Bedrock but not CloudWatch Logs, so logs are silently lost",
+		Flag:        "--logs",
+		Latency:     true,
+		Run:         runLogsChecks,
+	},
+	{
+		Category:    "marketplace",
+		Group:       "network",
+		Name:        "Marketplace Metering",
+		Description: "Resolves and connects to the regional AWS Marketplace metering endpoint that third-party Bedrock models depend on",
+		Requires:    "Outbound DNS and TCP 443 to metering.marketplace.<region>.amazonaws.com",
+		Failure:     "Egress allows Bedrock but not Marketplace metering, so inference on Marketplace models fails opaquely",
+		Flag:        "--marketplace",
+		Latency:     true,
+		Run:         runMarketplaceChecks,
+	},
+	{
+		Category:    "crossregion",
+		Group:       "network",
+		Name:        "Cross-Region",
+		Description: "Resolves and connects to Bedrock Runtime in every region an --inference-profile ID can route to",
+		Requires:    "Outbound DNS and TCP 443 to bedrock-runtime in each backing region",
+		Failure:     "Only warns: Bedrock routes profile requests between regions itself, but clients that fail over need the other endpoints",
+		Flag:        "--inference-profile",
+		Setting:     "inference_profile",
+		Configured:  func(cfg *Config) bool { return cfg.InferenceProfile != "" },
+		Scope:       ScopeGlobal,
+		Latency:     true,
+		Run:         runCrossRegionChecks,
+	},
+	{
+		Category:    "keepalive",
+		Group:       "network",
+		Name:        "Keep-Alive",
+		Description: "Sends two requests to Bedrock Runtime on one client and checks the second reuses the pooled connection",
+		Requires:    "Outbound HTTPS to Bedrock Runtime",
+		Failure:     "A proxy closes connections after each request, so long-running processes pay a new handshake per call and see latency spikes",
+		Flag:        "--keepalive",
+		Run:         runKeepAliveChecks,
+	},
+	{
+		Category:    "idle",
+		Group:       "network",
+		Name:        "Idle Connection",
+		Tags:        []string{"streaming"},
+		Description: "Holds a connection to Bedrock Runtime idle for --idle-probe and checks it is neither reset nor silently dropped",
+		Requires:    "Outbound HTTPS to Bedrock Runtime for the whole idle period",
+		Failure:     "A proxy, NAT gateway or load balancer drops idle connections sooner than streaming responses need",
+		Flag:        "--idle-probe",
+		Setting:     "idle_probe",
+		Configured:  func(cfg *Config) bool { return cfg.IdleProbe > 0 },
+		Run:         runIdleChecks,
+	},
+	{
+		Category:    "env",
+		Group:       "config",
+		Name:        "Env",
+		Tags:        []string{"offline"},
+		Description: "Verifies each environment variable named by --require is set and non-empty",
+		Requires:    "Nothing",
+		Failure:     "The deployment's preflight contract is not met",
+		Flag:        "--require",
+		Setting:     "require",
+		Configured:  func(cfg *Config) bool { return len(cfg.Require) > 0 },
+		Scope:       ScopeGlobal,
+		Run: func(_ context.Context, cfg *Config, _ string) []CheckResult {
+			return runEnvChecks(cfg)
+		},
+	},
+	{
+		Category:    "quotas",
+		Group:       "quota",
+		Name:        "Quotas",
+		Tags:        []string{"aws-api"},
+		Description: "Reads the Bedrock requests-per-minute quotas for a model family from Service Quotas",
+		Requires:    "Credentials with servicequotas:ListServiceQuotas and servicequotas:ListAWSDefaultServiceQuotas",
+		Failure:     "Only warns when a quota is still at the AWS default; Service Quotas could not be reached otherwise",
+		Flag:        "--quotas",
+		Setting:     "quotas",
+		Configured:  func(cfg *Config) bool { return cfg.Quotas != "" },
+		Run:         sdkCheck(runQuotaChecks),
+	},
+	{
+		Category:    "guardrail",
+		Group:       "model",
+		Name:        "Guardrail",
+		Tags:        []string{"aws-api"},
+		Description: "Gets the --guardrail-id guardrail and checks that it exists and is READY",
+		Requires:    "Credentials with bedrock:GetGuardrail",
+		Failure:     "The guardrail is missing, failed, or still changing, so requests that apply it are blocked",
+		Flag:        "--guardrail-id",
+		Setting:     "guardrail_id",
+		Configured:  func(cfg *Config) bool { return cfg.GuardrailID != "" },
+		Run:         sdkCheck(runGuardrailChecks),
+	},
+	{
+		Category:    "invocationlogging",
+		Group:       "model",
+		Name:        "Invocation Logging",
+		Tags:        []string{"aws-api"},
+		Description: "Reads the model invocation logging configuration and checks its S3 bucket exists in the region and its CloudWatch settings are complete",
+		Requires:    "Credentials with bedrock:GetModelInvocationLoggingConfiguration, and outbound HTTPS to s3.<region>.amazonaws.com",
+		Failure:     "The logging destination is missing or misconfigured, so invocations fail or their logs are lost",
+		Flag:        "--invocation-logging",
+		Run:         sdkCheck(runLoggingChecks),
+	},
+	{
+		Category:    "bearer",
+		Group:       "auth",
+		Name:        "Bearer Token",
+		Description: "Checks AWS_BEARER_TOKEN_BEDROCK is well-formed and not expired, and with --verify-token that Bedrock accepts it",
+		Requires:    "Nothing, or outbound HTTPS to Bedrock Runtime with --verify-token",
+		Failure:     "The API key is truncated, expired, or revoked",
+		Flag:        "$" + bearerTokenEnv,
+		Scope:       ScopeGlobal,
+		Run:         runBearerTokenChecks,
+	},
+	{
+		Category:    "dnstransport",
+		Group:       "network",
+		Name:        "DNS Transport",
+		Description: "Resolves the Bedrock Runtime hostname over UDP/53 and TCP/53 separately",
+		Requires:    "Outbound port 53 to the resolver",
+		Failure:     "An egress firewall filters one or both DNS transports",
+		Flag:        "--dns-transport",
+		Run:         runDNSTransportChecks,
+	},
+	{
+		Category:    "dnsttl",
+		Group:       "network",
+		Name:        "DNS TTL",
+		Description: "Queries the resolver directly for the Bedrock Runtime hostname and reports the TTL of each A and CNAME record",
+		Requires:    "Outbound port 53 to the resolver",
+		Failure:     "Only warns: a CNAME onto a VPC endpoint is cached long enough to outlive the endpoint",
+		Flag:        "--dns-ttl",
+		Run:         runDNSTTLChecks,
+	},
+	{
+		Category:    "resolvconf",
+		Group:       "network",
+		Name:        "Search Domains",
+		Tags:        []string{"offline"},
+		Description: "Reads /etc/resolv.conf and warns when its search list or ndots would delay every Bedrock lookup",
+		Requires:    "Read access to /etc/resolv.conf; skipped where it does not exist",
+		Failure:     "Failed lookups of the hostname under each search domain slow down or time out resolution",
+		Flag:        "--resolv-conf",
+		Scope:       ScopeGlobal,
+		Run:         runResolvConfChecks,
+	},
+	{
+		Category:    "resolvers",
+		Group:       "network",
+		Name:        "Resolver Comparison",
+		Description: "Resolves the Bedrock Runtime hostname through the system resolver, 8.8.8.8 and 1.1.1.1 at once and compares the answers",
+		Requires:    "Outbound UDP 53 to the public resolvers for a full comparison",
+		Failure:     "Only warns: the local or VPC DNS setup fails where public resolvers succeed",
+		Flag:        "--compare-resolvers",
+		Run:         runResolverComparison,
+	},
+	{
+		Category:    "vpcdns",
+		Group:       "network",
+		Name:        "VPC DNS",
+		Tags:        []string{"compute"},
+		Description: "On EC2, checks that the Bedrock Runtime hostname is resolved by the VPC's Route 53 Resolver rather than a public one",
+		Requires:    "Access to instance metadata and the VPC resolver",
+		Failure:     "A public or custom resolver bypasses the VPC's PrivateLink endpoint or private hosted zone",
+		Flag:        "--vpc-dns",
+		Run:         runVPCDNSChecks,
+	},
+	{
+		Category:    "local",
+		Group:       "local",
+		Name:        "Local Storage",
+		Tags:        []string{"offline"},
+		Description: "Writes a file to the temp and working directories and reports their free space",
+		Requires:    "Nothing beyond the local filesystem",
+		Failure:     "A directory is read-only or nearly full",
+		Flag:        "--local",
+		Scope:       ScopeGlobal,
+		Run: func(_ context.Context, cfg *Config, _ string) []CheckResult {
+			return runLocalChecks(cfg)
+		},
+	},
+	{
+		Category:    "openfiles",
+		Group:       "local",
+		Name:        "Open Files",
+		Tags:        []string{"offline"},
+		Description: "Reads the soft and hard open file limits (ulimit -n) and warns when the soft one is below --min-open-files",
+		Requires:    "Nothing; skipped on Windows, which has no such limit",
+		Failure:     "Concurrent Bedrock streams will hit \"too many open files\" partway through a run",
+		Flag:        "--open-files",
+		Scope:       ScopeGlobal,
+		Run: func(_ context.Context, cfg *Config, _ string) []CheckResult {
+			return []CheckResult{runOpenFilesCheck(cfg)}
+		},
+	},
+	{
+		Category:    "workdir",
+		Group:       "local",
+		Name:        "Workdir",
+		Tags:        []string{"offline"},
+		Description: "Checks that the --workdir output directory exists, is writable and has --min-free space",
+		Requires:    "Nothing beyond the local filesystem",
+		Failure:     "The directory BCCE writes transcripts and artifacts to is missing, unwritable or nearly full",
+		Flag:        "--workdir",
+		Setting:     "workdir",
+		Configured:  func(cfg *Config) bool { return cfg.Workdir != "" },
+		Scope:       ScopeGlobal,
+		Run: func(_ context.Context, cfg *Config, _ string) []CheckResult {
+			return runWorkdirCheck(cfg)
+		},
+	},
+	{
+		Category:    "cli",
+		Group:       "local",
+		Name:        "CLI",
+		Tags:        []string{"offline"},
+		Description: "Checks that each --require-cli binary is on PATH and its --version meets the minimum",
+		Requires:    "Permission to run the listed binaries",
+		Failure:     "A CLI BCCE depends on is missing or older than required",
+		Flag:        "--require-cli",
+		Setting:     "require_cli",
+		Configured:  func(cfg *Config) bool { return len(cfg.RequireCLI) > 0 },
+		Scope:       ScopeGlobal,
+		Run: func(ctx context.Context, cfg *Config, _ string) []CheckResult {
+			return runCLIChecks(ctx, cfg)
+		},
+	},
+	{
+		Category:    "hosts",
+		Group:       "network",
+		Name:        "Hosts",
+		Description: "Resolves and connects to each host:port given with --hosts, such as a gateway or logging sink",
+		Requires:    "Outbound DNS and TCP to the listed hosts",
+		Failure:     "A listed host does not resolve or its port is unreachable",
+		Flag:        "--hosts",
+		Setting:     "hosts",
+		Configured:  func(cfg *Config) bool { return len(cfg.Hosts) > 0 },
+		Scope:       ScopeGlobal,
+		Latency:     true,
+		Run: func(ctx context.Context, cfg *Config, _ string) []CheckResult {
+			return runHostChecks(ctx, cfg)
+		},
+	},
+	{
+		Category:    "captive",
+		Group:       "network",
+		Name:        "Captive Portal",
+		Description: "Fetches a generate_204 URL over plain HTTP and expects an empty 204 back",
+		Requires:    "Outbound HTTP to the check URL",
+		Failure:     "A hotel, airport or guest network login page is answering instead of the internet",
+		Flag:        "--captive-portal",
+		Scope:       ScopeGlobal,
+		Run: func(ctx context.Context, cfg *Config, _ string) []CheckResult {
+			return runCaptivePortalChecks(ctx, cfg)
+		},
+	},
+	{
+		Category:    "cacerts",
+		Group:       "network",
+		Name:        "CA Certificates",
+		Tags:        []string{"offline"},
+		Description: "Counts the trusted root certificates and validates AWS_CA_BUNDLE when it is set",
+		Requires:    "Read access to the system CA bundle",
+		Failure:     "The container or host has no CA certificates installed, or the custom bundle is unusable",
+		Flag:        "--ca-certs",
+		Scope:       ScopeGlobal,
+		Run: func(context.Context, *Config, string) []CheckResult {
+			return runTrustStoreChecks()
+		},
+	},
+	{
+		Category:    "sso",
+		Group:       "auth",
+		Name:        "SSO Session",
+		Tags:        []string{"offline"},
+		Description: "Checks the cached IAM Identity Center token of an SSO profile for expiry",
+		Requires:    "Read access to ~/.aws/config and ~/.aws/sso/cache",
+		Failure:     "The SSO session has expired or 'aws sso login' was never run for the profile",
+		Flag:        "--sso",
+		Scope:       ScopeGlobal,
+		Run: sdkCheck(func(ctx context.Context, cfg *Config, _ string) []CheckResult {
+			return runSSOChecks(ctx, cfg)
+		}),
+	},
+}
+
+// Check categories that can be enabled in the config file
+var checkCategories = registryCategories(func(Check) bool { return true })
+
+var defaultChecks = registryCategories(func(check Check) bool { return check.Default })
+
+// Every tag used in the registry, sorted
+var checkTags = func() []string {
+	var tags []string
+	for _, check := range checkRegistry {
+		tags = append(tags, check.Tags...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}()
+
+// taggedChecks returns the categories bearing any of tags, in registry order
+func taggedChecks(tags []string) ([]string, error) {
+	for _, tag := range tags {
+		if !slices.Contains(checkTags, strings.ToLower(tag)) {
+			return nil, fmt.Errorf("unknown --tag %q (available: %s)", tag, strings.Join(checkTags, ", "))
+		}
+	}
+	return registryCategories(func(check Check) bool {
+		return slices.ContainsFunc(check.Tags, func(tag string) bool {
+			return slices.ContainsFunc(tags, func(want string) bool { return strings.EqualFold(tag, want) })
+		})
+	}), nil
+}
+
+// Checks that are not region-specific and only run once per invocation
+var globalChecks = registryCategories(func(check Check) bool { return check.Scope == ScopeGlobal })
+
+// Display names used as the prefix of each category's check results
+var checkLabels = func() map[string]string {
+	labels := make(map[string]string, len(checkRegistry))
+	for _, check := range checkRegistry {
+		labels[check.Category] = check.Name
+	}
+	return labels
+}()
+
+func registryCategories(keep func(Check) bool) []string {
+	var categories []string
+	for _, check := range checkRegistry {
+		if keep(check) {
+			categories = append(categories, check.Category)
+		}
+	}
+	return categories
+}
+
+// configured reports whether the setting the check needs, if any, is set
+func (check Check) configured(cfg *Config) bool {
+	return check.Configured == nil || check.Configured(cfg)
+}
+
+// lookupCheck returns the registry entry for a category
+func lookupCheck(category string) (Check, bool) {
+	for _, check := range checkRegistry {
+		if check.Category == category {
+			return check, true
+		}
+	}
+	return Check{}, false
+}
+
+// writeExplanation describes the checks matching pattern, or every check for "all"
+func writeExplanation(w io.Writer, pattern string) error {
+	categories := checkCategories
+	if !strings.EqualFold(pattern, "all") {
+		categories = matchingChecks(pattern)
+	}
+	if len(categories) == 0 {
+		return fmt.Errorf("no check matches %q (available: %s)", pattern, strings.Join(checkCategories, ", "))
+	}
+
+	for i, category := range categories {
+		check, _ := lookupCheck(category)
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		enable := "runs by default"
+		if check.Flag != "" {
+			enable = "enable with " + check.Flag
+		}
+		fmt.Fprintf(w, "%s (%s, %s)\n", check.Name, check.Category, enable)
+		fmt.Fprintf(w, "  Checks:   %s\n", check.Description)
+		fmt.Fprintf(w, "  Requires: %s\n", check.Requires)
+		fmt.Fprintf(w, "  Failing:  %s\n", check.Failure)
+	}
+	return nil
+}
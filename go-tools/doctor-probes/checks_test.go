@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCheckRegistry(t *testing.T) {
+	seen := map[string]bool{}
+	for _, check := range checkRegistry {
+		if seen[check.Category] {
+			t.Errorf("category %q registered twice", check.Category)
+		}
+		seen[check.Category] = true
+
+		if check.Run == nil {
+			t.Errorf("%s has no Run function", check.Category)
+		}
+		if check.Name == "" || check.Description == "" || check.Requires == "" || check.Failure == "" {
+			t.Errorf("%s is missing explain metadata", check.Category)
+		}
+		if check.Default == (check.Flag != "") {
+			t.Errorf("%s: default checks have no enable flag and opt-in checks need one", check.Category)
+		}
+	}
+}
+
+// DNS results through the registry must match calling the probe directly
+func TestRunJobDNS(t *testing.T) {
+	cfg := newConfig()
+	cfg.Services = serviceFamilies
+	cfg.Endpoints = map[string]string{}
+	for _, service := range bedrockServices {
+		cfg.Endpoints[service.Prefix] = "localhost"
+	}
+
+	ctx := context.Background()
+	got := runJob(ctx, cfg, probeJob{region: "us-east-1", check: "dns"})
+	want := runDNSChecks(ctx, cfg, "us-east-1")
+	for i := range got {
+		got[i].DurationMs = 0
+	}
+	for i := range want {
+		want[i].DurationMs = 0
+		// runJob stamps the registry group as the category
+		want[i].Category = "network"
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("runJob(dns) = %+v, want %+v", got, want)
+	}
+
+	if len(got) != len(bedrockServices)+1 {
+		t.Fatalf("got %d DNS results, want %d and a rollup", len(got), len(bedrockServices))
+	}
+	if rollup := got[len(got)-1]; rollup.Name != dnsRollupName || rollup.Status != "pass" || rollup.Message != "6 of 6 Bedrock endpoints resolved" {
+		t.Errorf("rollup = %+v, want all 6 endpoints resolved", rollup)
+	}
+	for i, result := range got[:len(bedrockServices)] {
+		if wantName := "DNS - " + bedrockServices[i].Name + " (localhost)"; result.Name != wantName {
+			t.Errorf("result %d name = %q, want %q", i, result.Name, wantName)
+		}
+		if result.Status != "pass" {
+			t.Errorf("%s status = %s (%s), want pass", result.Name, result.Status, result.Message)
+		}
+	}
+}
+
+func TestDNSRollup(t *testing.T) {
+	resolved := CheckResult{Name: "DNS - Bedrock Runtime", Status: "pass", Addresses: []string{"10.0.0.5"}}
+	agent := CheckResult{Name: "DNS - Bedrock Agent", Status: "warn"}
+	control := CheckResult{Name: "DNS - Bedrock Control Plane", Status: "fail"}
+	tests := []struct {
+		results []CheckResult
+		status  string
+		message string
+	}{
+		{[]CheckResult{resolved, resolved}, "pass", "2 of 2 Bedrock endpoints resolved"},
+		{[]CheckResult{resolved, control, agent}, "warn", "1 of 3 Bedrock endpoints resolved; not resolved: Bedrock Control Plane, Bedrock Agent"},
+		{[]CheckResult{control, agent}, "fail", "0 of 2 Bedrock endpoints resolved"},
+	}
+	for _, tt := range tests {
+		if got := dnsRollup(tt.results); got.Status != tt.status || got.Message != tt.message {
+			t.Errorf("dnsRollup() = %s %q, want %s %q", got.Status, got.Message, tt.status, tt.message)
+		}
+	}
+}
+
+func TestRunJobUnknownCategory(t *testing.T) {
+	if results := runJob(context.Background(), newConfig(), probeJob{check: "bogus"}); results != nil {
+		t.Errorf("runJob(bogus) = %v, want nil", results)
+	}
+}
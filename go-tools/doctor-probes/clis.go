@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The first dotted number in --version output, e.g. 2.15.0 in "aws-cli/2.15.0 Python/3.11.6"
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// How to install CLIs BCCE is known to depend on
+var cliInstallHints = map[string]string{
+	"claude": "npm install -g @anthropic-ai/claude-code",
+	"aws":    "see https://docs.aws.amazon.com/cli/latest/userguide/getting-started-install.html",
+}
+
+// requiredCLI is a --require-cli entry: a binary name and an optional minimum version
+type requiredCLI struct {
+	Name       string
+	MinVersion string
+}
+
+// parseRequiredCLI accepts "name" or "name>=version"
+func parseRequiredCLI(value string) (requiredCLI, error) {
+	name, minVersion, hasVersion := strings.Cut(value, ">=")
+	cli := requiredCLI{Name: strings.TrimSpace(name), MinVersion: strings.TrimSpace(minVersion)}
+	if cli.Name == "" {
+		return cli, fmt.Errorf("missing binary name")
+	}
+	if strings.ContainsAny(cli.Name, " /\\") {
+		return cli, fmt.Errorf("%q is not a binary name", cli.Name)
+	}
+	if hasVersion && versionPattern.FindString(cli.MinVersion) != cli.MinVersion {
+		return cli, fmt.Errorf("minimum version %q is not a dotted version such as 2.15.0", cli.MinVersion)
+	}
+	return cli, nil
+}
+
+// compareVersions compares dotted versions numerically, treating missing components as 0
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// runCLIChecks checks each --require-cli binary concurrently
+func runCLIChecks(ctx context.Context, cfg *Config) []CheckResult {
+	results := make([]CheckResult, len(cfg.RequireCLI))
+	var wg sync.WaitGroup
+	for i, entry := range cfg.RequireCLI {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Entries were validated when the flag or config was read
+			cli, _ := parseRequiredCLI(entry)
+			results[i] = checkCLI(ctx, cli, cfg.Timeout)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// checkCLI finds cli on PATH and compares its --version output to the minimum
+func checkCLI(ctx context.Context, cli requiredCLI, timeout time.Duration) CheckResult {
+	name := "CLI - " + cli.Name
+	install := "Install " + cli.Name + " and make sure it is on PATH"
+	if hint, ok := cliInstallHints[cli.Name]; ok {
+		install += " (" + hint + ")"
+	}
+
+	path, err := exec.LookPath(cli.Name)
+	if err != nil {
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s was not found on PATH", cli.Name),
+			Fix:     install,
+		}
+	}
+	if cli.MinVersion == "" {
+		return CheckResult{Name: name, Status: "pass", Message: fmt.Sprintf("Found %s at %s", cli.Name, path)}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	durationMs := time.Since(start).Milliseconds()
+	logger.Debug("ran version command", "cli", cli.Name, "path", path, "output", strings.TrimSpace(string(output)), "error", err)
+
+	found := versionPattern.FindString(string(output))
+	if found == "" {
+		message := fmt.Sprintf("Could not read a version from '%s --version'", path)
+		if err != nil {
+			message = fmt.Sprintf("'%s --version' failed: %v", path, err)
+		}
+		return CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    message + fmt.Sprintf("; %s or later is required", cli.MinVersion),
+			Fix:        fmt.Sprintf("Run '%s --version' by hand and upgrade if it is older than %s", cli.Name, cli.MinVersion),
+			DurationMs: durationMs,
+		}
+	}
+	if compareVersions(found, cli.MinVersion) < 0 {
+		return CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("%s %s at %s is older than the required %s", cli.Name, found, path, cli.MinVersion),
+			Fix:        fmt.Sprintf("Upgrade %s to %s or later", cli.Name, cli.MinVersion),
+			DurationMs: durationMs,
+		}
+	}
+	return CheckResult{
+		Name:       name,
+		Status:     "pass",
+		Message:    fmt.Sprintf("%s %s at %s (requires %s or later)", cli.Name, found, path, cli.MinVersion),
+		DurationMs: durationMs,
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRequiredCLI(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    requiredCLI
+		wantErr bool
+	}{
+		{"claude", requiredCLI{Name: "claude"}, false},
+		{"aws>=2.15", requiredCLI{Name: "aws", MinVersion: "2.15"}, false},
+		{" aws >= 2.15.0 ", requiredCLI{Name: "aws", MinVersion: "2.15.0"}, false},
+		{">=1.0", requiredCLI{}, true},
+		{"bin/aws", requiredCLI{}, true},
+		{"aws>=latest", requiredCLI{}, true},
+		{"aws>=2", requiredCLI{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseRequiredCLI(tt.value)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("parseRequiredCLI(%q) = %+v, %v; want %+v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.15.0", "2.15", 0},
+		{"2.9.1", "2.15.0", -1},
+		{"1.0.10", "1.0.9", 1},
+		{"10.0", "9.99.99", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCLI(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake CLIs are shell scripts")
+	}
+	dir := t.TempDir()
+	for name, output := range map[string]string{
+		"claude": "1.0.3 (Claude Code)",
+		"aws":    "aws-cli/2.9.1 Python/3.11.6 Linux/6.1 exe/x86_64",
+		"noisy":  "no version here",
+		"jq":     "jq-1.7.1",
+	} {
+		script := "#!/bin/sh\necho '" + output + "'\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+
+	tests := []struct {
+		cli     requiredCLI
+		status  string
+		message string
+	}{
+		{requiredCLI{Name: "claude", MinVersion: "1.0.0"}, "pass", "claude 1.0.3"},
+		{requiredCLI{Name: "aws", MinVersion: "2.15"}, "fail", "aws 2.9.1"},
+		{requiredCLI{Name: "noisy", MinVersion: "1.0"}, "warn", "Could not read a version"},
+		{requiredCLI{Name: "jq"}, "pass", "Found jq"},
+		{requiredCLI{Name: "missing", MinVersion: "1.0"}, "fail", "not found on PATH"},
+	}
+	for _, tt := range tests {
+		got := checkCLI(context.Background(), tt.cli, 5*time.Second)
+		if got.Status != tt.status || !strings.Contains(got.Message, tt.message) {
+			t.Errorf("checkCLI(%+v) = %s %q, want %s containing %q", tt.cli, got.Status, got.Message, tt.status, tt.message)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultNTPServer = "time.aws.com"
+
+	clockSkewWarn = 2 * time.Minute
+	clockSkewFail = 5 * time.Minute
+)
+
+// Seconds between the NTP epoch (1900) and the Unix epoch (1970)
+const ntpEpochOffset = 2208988800
+
+func ntpTime(b []byte) time.Time {
+	seconds := binary.BigEndian.Uint32(b[0:4])
+	fraction := binary.BigEndian.Uint32(b[4:8])
+	nanos := (int64(fraction) * 1e9) >> 32
+	return time.Unix(int64(seconds)-ntpEpochOffset, nanos)
+}
+
+// queryClockOffset performs a single SNTP exchange and returns the local clock's offset from the server
+func queryClockOffset(ctx context.Context, server string, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	address := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		address = net.JoinHostPort(server, "123")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// LI=0, VN=3, Mode=3 (client)
+	request := make([]byte, 48)
+	request[0] = 0x1B
+
+	sent := time.Now()
+	if _, err := conn.Write(request); err != nil {
+		return 0, err
+	}
+	response := make([]byte, 48)
+	if _, err := conn.Read(response); err != nil {
+		return 0, err
+	}
+	received := time.Now()
+
+	serverReceive := ntpTime(response[32:40])
+	serverTransmit := ntpTime(response[40:48])
+	if serverTransmit.Unix() <= 0 {
+		return 0, fmt.Errorf("invalid response from %s", address)
+	}
+
+	return (serverReceive.Sub(sent) + serverTransmit.Sub(received)) / 2, nil
+}
+
+// hostClockFix returns how to fix skew where the clock cannot be corrected
+// from inside, or "" where it can. Containers share the host kernel's clock
+// and lack CAP_SYS_TIME, so running ntpd or chrony in them does nothing.
+func hostClockFix(env *Environment, root string) string {
+	if !env.Container {
+		return ""
+	}
+	host := "the host"
+	switch env.Platform {
+	case "eks":
+		host = "the EKS node"
+	case "ecs":
+		host = "the ECS container instance (on Fargate, AWS manages the clock; restart the task)"
+	}
+	fix := fmt.Sprintf("This container uses %s's clock and cannot set it; enable time sync on %s (chrony with the Amazon Time Sync Service, 169.254.169.123), or restart Docker Desktop's VM if it drifted after sleep", host, host)
+	if !hasRTC(root) {
+		fix += "; there is no /dev/rtc here, so the clock is whatever the host provides"
+	}
+	return fix
+}
+
+// hasRTC reports whether a hardware clock device is visible under root
+func hasRTC(root string) bool {
+	return fileExists(filepath.Join(root, "dev/rtc")) || fileExists(filepath.Join(root, "dev/rtc0"))
+}
+
+func runClockChecks(ctx context.Context, cfg *Config) []CheckResult {
+	var results []CheckResult
+
+	server := cfg.NTPServer
+	logger.Debug("querying NTP server", "server", server)
+	start := time.Now()
+	offset, err := queryClockOffset(ctx, server, cfg.Timeout)
+	durationMs := time.Since(start).Milliseconds()
+	hostFix := hostClockFix(currentEnvironment(), "/")
+	if err != nil {
+		fix := "Allow outbound UDP 123 or set --ntp-server to a reachable time server"
+		if hostFix != "" {
+			// Without NTP egress the container can neither measure nor fix skew
+			fix += "; if SigV4 requests fail with signature or expired-request errors, check the clock on the host, since this container cannot correct its own"
+		}
+		results = append(results, CheckResult{
+			Name:       "Clock - NTP Skew",
+			Status:     "warn",
+			Message:    fmt.Sprintf("Could not query NTP server %s: %v", server, err),
+			Fix:        fix,
+			DurationMs: durationMs,
+		})
+		return results
+	}
+
+	skew := offset.Abs().Round(time.Millisecond)
+	switch {
+	case skew > clockSkewFail:
+		results = append(results, CheckResult{
+			Name:       "Clock - NTP Skew",
+			Status:     "fail",
+			Message:    fmt.Sprintf("Local clock is off by %s from %s; SigV4 requests will be rejected", skew, server),
+			Fix:        cmp.Or(hostFix, "Sync the system clock (e.g. enable NTP with 'timedatectl set-ntp true' or chrony)"),
+			DurationMs: durationMs,
+			reason:     reasonClockSkew,
+		})
+	case skew > clockSkewWarn:
+		results = append(results, CheckResult{
+			Name:       "Clock - NTP Skew",
+			Status:     "warn",
+			Message:    fmt.Sprintf("Local clock is off by %s from %s", skew, server),
+			Fix:        cmp.Or(hostFix, "Sync the system clock before skew exceeds the 5 minute SigV4 limit"),
+			DurationMs: durationMs,
+			reason:     reasonClockSkew,
+		})
+	default:
+		results = append(results, CheckResult{
+			Name:       "Clock - NTP Skew",
+			Status:     "pass",
+			Message:    fmt.Sprintf("Local clock is within %s of %s", skew, server),
+			DurationMs: durationMs,
+		})
+	}
+
+	return results
+}
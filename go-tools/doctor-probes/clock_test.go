@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHostClockFix(t *testing.T) {
+	root := t.TempDir()
+	if fix := hostClockFix(&Environment{Container: false}, root); fix != "" {
+		t.Errorf("outside a container got %q, want the default fix", fix)
+	}
+
+	fix := hostClockFix(&Environment{Container: true, Platform: "eks"}, root)
+	if !strings.Contains(fix, "the EKS node") || !strings.Contains(fix, "no /dev/rtc") {
+		t.Errorf("EKS container without /dev/rtc got %q", fix)
+	}
+
+	os.MkdirAll(filepath.Join(root, "dev"), 0o755)
+	os.WriteFile(filepath.Join(root, "dev/rtc0"), nil, 0o644)
+	fix = hostClockFix(&Environment{Container: true}, root)
+	if !strings.Contains(fix, "the host") || strings.Contains(fix, "/dev/rtc") {
+		t.Errorf("container with /dev/rtc0 got %q", fix)
+	}
+}
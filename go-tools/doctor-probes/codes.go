@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// errorCode is a stable identifier for one kind of failure, reported in
+// CheckResult.Code so runbooks and support tickets can refer to it.
+//
+// Codes are part of the tool's interface: never renumber or reuse one. Add new
+// codes at the end of a check's list, and leave a retired code in place with
+// a description saying it is no longer reported.
+type errorCode struct {
+	Code string
+	// Check is the registry category whose results the code applies to
+	Check       string
+	Description string
+
+	// A result matches when its reason or error kind equals the one set here
+	reason failureReason
+	kind   string
+}
+
+// Codes set directly on results that don't come from a registry check
+const (
+	codeNetworkOffline = "BCCE-NETWORK-001"
+	codeNoRegion       = "BCCE-CONFIG-001"
+	codeInvalidRegion  = "BCCE-CONFIG-002"
+)
+
+// errorCodes lists the specific codes; within a check the first match wins.
+// Every check also has a catch-all -000 code, see checkCatchAllCode.
+var errorCodes = []errorCode{
+	{Code: codeNetworkOffline, Check: "network", Description: "No route to the internet, so network checks were skipped"},
+	{Code: codeNoRegion, Check: "config", Description: "No AWS region could be determined from flags, environment or profile"},
+	{Code: codeInvalidRegion, Check: "config", Description: "A configured region is not a valid AWS region name"},
+	{Code: "BCCE-REGION-001", Check: "region", Description: "Bedrock is not available in the region", reason: reasonRegion},
+	{Code: "BCCE-DNS-001", Check: "dns", Description: "The DNS lookup timed out", kind: ErrorKindTimeout},
+	{Code: "BCCE-DNS-002", Check: "dns", Description: "The hostname did not resolve", kind: ErrorKindDNS},
+	{Code: "BCCE-TCP-001", Check: "tcp", Description: "TCP 443 to a VPC interface endpoint timed out; its security group likely blocks this client", reason: reasonVPCEndpoint},
+	{Code: "BCCE-TCP-002", Check: "tcp", Description: "TCP connect timed out; packets are dropped by a firewall, security group or NACL", kind: ErrorKindTimeout},
+	{Code: "BCCE-TCP-003", Check: "tcp", Description: "TCP connect was refused, reset or had no route", kind: ErrorKindTCP},
+	{Code: "BCCE-TCP-004", Check: "tcp", Description: "The endpoint hostname did not resolve", kind: ErrorKindDNS},
+	{Code: "BCCE-TLS-001", Check: "tls", Description: "The certificate is not trusted, usually because a proxy intercepts TLS", reason: reasonCABundle},
+	{Code: "BCCE-TLS-002", Check: "tls", Description: "The TLS handshake timed out", kind: ErrorKindTimeout},
+	{Code: "BCCE-TLS-003", Check: "tls", Description: "The TLS handshake failed", kind: ErrorKindTLS},
+	{Code: "BCCE-PROXY-001", Check: "proxy", Description: "CONNECT through the configured proxy to Bedrock failed", reason: reasonProxy},
+	{Code: "BCCE-CREDS-001", Check: "creds", Description: "No valid AWS credentials were found, or they were rejected", reason: reasonCredentials},
+	{Code: "BCCE-IMDS-001", Check: "imds", Description: "The IMDSv2 token request failed", reason: reasonIMDSv2},
+	{Code: "BCCE-MODEL-001", Check: "model", Description: "Access to the model has not been granted for the account", reason: reasonModelAccess},
+	{Code: "BCCE-MODEL-002", Check: "model", Description: "The model is not offered in the region", reason: reasonRegion},
+	{Code: "BCCE-MODEL-003", Check: "model", Description: "IAM denies access to the model", kind: ErrorKindAuth},
+	{Code: "BCCE-MODELID-001", Check: "modelid", Description: "Access to the configured model has not been granted", reason: reasonModelAccess},
+	{Code: "BCCE-MODELID-002", Check: "modelid", Description: "The model must be invoked through a cross-region inference profile", reason: reasonInferenceProfile},
+	{Code: "BCCE-MODELID-003", Check: "modelid", Description: "The configured model is not offered in the region", reason: reasonRegion},
+	{Code: "BCCE-MODELID-004", Check: "modelid", Description: "IAM denies listing or reading the model", kind: ErrorKindAuth},
+	{Code: "BCCE-SMOKE-001", Check: "smoke", Description: "The smoke test was denied for lack of model access", reason: reasonModelAccess},
+	{Code: "BCCE-SMOKE-002", Check: "smoke", Description: "The smoke test was throttled by a Bedrock quota", reason: reasonQuota},
+	{Code: "BCCE-SMOKE-003", Check: "smoke", Description: "The smoke test model is not offered in the region", reason: reasonRegion},
+	{Code: "BCCE-SMOKESTREAM-001", Check: "smokestream", Description: "The streaming smoke test was denied for lack of model access", reason: reasonModelAccess},
+	{Code: "BCCE-SMOKESTREAM-002", Check: "smokestream", Description: "The streaming smoke test was throttled by a Bedrock quota", reason: reasonQuota},
+	{Code: "BCCE-SMOKESTREAM-003", Check: "smokestream", Description: "The streaming smoke test model is not offered in the region", reason: reasonRegion},
+	{Code: "BCCE-SMOKESTREAM-004", Check: "smokestream", Description: "The response stream opened but no chunk arrived before the timeout", kind: ErrorKindTimeout},
+	{Code: "BCCE-QUOTAS-001", Check: "quotas", Description: "A Bedrock quota is still at the AWS default and likely too low", reason: reasonQuota},
+	{Code: "BCCE-CLOCK-001", Check: "clock", Description: "The local clock is skewed enough to break request signing", reason: reasonClockSkew},
+	{Code: "BCCE-IPRANGES-001", Check: "ipranges", Description: "The endpoint resolves to addresses outside the published AWS ranges", reason: reasonOutsideAWSRanges},
+	{Code: "BCCE-LAMBDA-001", Check: "lambda", Description: "The Lambda function cannot resolve Bedrock, likely a VPC without a route to it", reason: reasonVPCEndpoint},
+	{Code: "BCCE-VPCDNS-001", Check: "vpcdns", Description: "The VPC could not be determined from instance metadata", reason: reasonVPCUnknown},
+	{Code: "BCCE-GUARDRAIL-001", Check: "guardrail", Description: "IAM denies reading the guardrail", kind: ErrorKindAuth},
+	{Code: "BCCE-INVOCATIONLOGGING-001", Check: "invocationlogging", Description: "IAM denies reading the model invocation logging configuration", kind: ErrorKindAuth},
+	{Code: "BCCE-SSO-001", Check: "sso", Description: "The IAM Identity Center session is missing, expired or invalid", kind: ErrorKindAuth},
+}
+
+// checkCatchAllCode is the code for a failure of check that no specific code covers
+func checkCatchAllCode(check string) string {
+	return fmt.Sprintf("BCCE-%s-000", strings.ToUpper(check))
+}
+
+// allErrorCodes returns errorCodes plus each check's catch-all, grouped by
+// check in registry order
+func allErrorCodes() []errorCode {
+	var codes []errorCode
+	addCheck := func(check, name string) {
+		codes = append(codes, errorCode{Code: checkCatchAllCode(check), Check: check, Description: fmt.Sprintf("%s failed for a reason without a specific code", name)})
+		for _, code := range errorCodes {
+			if code.Check == check {
+				codes = append(codes, code)
+			}
+		}
+	}
+	addCheck("network", "Network detection")
+	addCheck("config", "Configuration")
+	for _, check := range checkRegistry {
+		addCheck(check.Category, check.Name)
+	}
+	return codes
+}
+
+// assignCode sets the code for a result that didn't pass, unless the check
+// already chose one. Results from registry checks are matched on their check;
+// others on their category.
+func assignCode(result *CheckResult) {
+	if result.Status == "pass" || result.Code != "" {
+		return
+	}
+	check := result.check
+	if check == "" {
+		check = result.Category
+	}
+	if check == "" {
+		return
+	}
+	for _, code := range errorCodes {
+		if code.Check != check {
+			continue
+		}
+		if (code.reason != reasonNone && code.reason == result.reason) || (code.kind != "" && code.kind == result.ErrorKind) {
+			result.Code = code.Code
+			return
+		}
+	}
+	result.Code = checkCatchAllCode(check)
+}
+
+// writeCodes prints every code and what it means, for the codes command
+func writeCodes(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tCHECK\tDESCRIPTION")
+	for _, code := range allErrorCodes() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", code.Code, code.Check, code.Description)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// Runbooks link to codes, so the golden file may only ever gain lines; an
+// -update that changes or drops one breaks them
+func TestCodesGolden(t *testing.T) {
+	var buf bytes.Buffer
+	writeCodes(&buf)
+	assertGolden(t, "codes.golden", buf.Bytes())
+}
+
+func TestErrorCodesWellFormed(t *testing.T) {
+	pattern := regexp.MustCompile(`^BCCE-[A-Z0-9]+-\d{3}$`)
+	checks := append([]string{"network", "config"}, checkCategories...)
+	seen := map[string]bool{}
+	for _, code := range allErrorCodes() {
+		if !pattern.MatchString(code.Code) {
+			t.Errorf("code %s does not look like BCCE-CHECK-NNN", code.Code)
+		}
+		if seen[code.Code] {
+			t.Errorf("code %s is defined twice", code.Code)
+		}
+		seen[code.Code] = true
+		if !slices.Contains(checks, code.Check) {
+			t.Errorf("code %s is for unknown check %q", code.Code, code.Check)
+		}
+		if code.Description == "" {
+			t.Errorf("code %s has no description", code.Code)
+		}
+	}
+}
+
+// Each specific code stands for one failure mode: exactly one code matches a
+// result of that mode, and reasons borrowed from other failures don't
+func TestErrorCodeFailureModes(t *testing.T) {
+	for _, code := range errorCodes {
+		if code.reason == reasonNone && code.kind == "" {
+			continue
+		}
+		result := CheckResult{Status: "fail", check: code.Check, reason: code.reason, ErrorKind: code.kind}
+		var matches []string
+		for _, other := range errorCodes {
+			if other.Check == code.Check && ((other.reason != reasonNone && other.reason == result.reason) || (other.kind != "" && other.kind == result.ErrorKind)) {
+				matches = append(matches, other.Code)
+			}
+		}
+		if len(matches) != 1 {
+			t.Errorf("the failure mode of %s matches %v, want only %s", code.Code, matches, code.Code)
+		}
+		if assignCode(&result); result.Code != code.Code {
+			t.Errorf("assignCode() for the failure mode of %s = %s", code.Code, result.Code)
+		}
+	}
+
+	tests := []struct {
+		check  string
+		reason failureReason
+		want   string
+	}{
+		{"vpcdns", reasonVPCUnknown, "BCCE-VPCDNS-001"},
+		{"vpcdns", reasonIMDSv2, "BCCE-VPCDNS-000"},
+		{"vpcdns", reasonVPCEndpoint, "BCCE-VPCDNS-000"},
+		{"ipranges", reasonOutsideAWSRanges, "BCCE-IPRANGES-001"},
+		{"ipranges", reasonVPCEndpoint, "BCCE-IPRANGES-000"},
+		{"imds", reasonVPCUnknown, "BCCE-IMDS-000"},
+		{"tcp", reasonOutsideAWSRanges, "BCCE-TCP-000"},
+	}
+	for _, tt := range tests {
+		result := CheckResult{Status: "warn", check: tt.check, reason: tt.reason}
+		if assignCode(&result); result.Code != tt.want {
+			t.Errorf("assignCode(%s, reason %d) = %s, want %s", tt.check, tt.reason, result.Code, tt.want)
+		}
+	}
+}
+
+func TestAssignCode(t *testing.T) {
+	tests := []struct {
+		result CheckResult
+		want   string
+	}{
+		{CheckResult{Status: "pass", check: "dns"}, ""},
+		{CheckResult{Status: "fail", check: "dns", ErrorKind: ErrorKindDNS}, "BCCE-DNS-002"},
+		{CheckResult{Status: "warn", check: "dns"}, "BCCE-DNS-000"},
+		// The reason is more specific than the error kind
+		{CheckResult{Status: "fail", check: "tcp", ErrorKind: ErrorKindTimeout, reason: reasonVPCEndpoint}, "BCCE-TCP-001"},
+		{CheckResult{Status: "fail", check: "tcp", ErrorKind: ErrorKindTimeout}, "BCCE-TCP-002"},
+		{CheckResult{Status: "fail", check: "tls", ErrorKind: ErrorKindTLS, reason: reasonCABundle}, "BCCE-TLS-001"},
+		// Codes only match their own check
+		{CheckResult{Status: "fail", check: "webhook", ErrorKind: ErrorKindDNS}, "BCCE-WEBHOOK-000"},
+		{CheckResult{Status: "fail", Category: "config"}, "BCCE-CONFIG-000"},
+		{CheckResult{Status: "fail", Category: "config", Code: codeNoRegion}, codeNoRegion},
+		{CheckResult{Status: "fail"}, ""},
+	}
+	for _, tt := range tests {
+		result := tt.result
+		assignCode(&result)
+		if result.Code != tt.want {
+			t.Errorf("assignCode(%+v) = %q, want %q", tt.result, result.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// Collector gathers results from checks running concurrently. Results come
+// back in job order whatever order they were added in, so reports don't
+// depend on which check happened to finish first.
+type Collector struct {
+	mu      sync.Mutex
+	jobs    []probeJob
+	order   map[jobKey]int
+	results []collected
+}
+
+// jobKey identifies the job that produced a result; runChecks sets both
+// fields on every result it collects
+type jobKey struct{ check, region string }
+
+type collected struct {
+	// job indexes jobs, or is len(jobs) for a result no job produced
+	job, seq int
+	result   CheckResult
+}
+
+func newCollector(jobs []probeJob) *Collector {
+	// jobs may be shuffled for --startup-jitter, so keep them by index
+	c := &Collector{jobs: make([]probeJob, len(jobs)), order: make(map[jobKey]int, len(jobs))}
+	for _, job := range jobs {
+		c.jobs[job.index] = job
+		c.order[jobKey{job.check, job.region}] = job.index
+	}
+	return c
+}
+
+// Add records a result; it is safe to call from multiple goroutines
+func (c *Collector) Add(result CheckResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	job, ok := c.order[jobKey{result.check, result.Region}]
+	if !ok {
+		job = len(c.jobs)
+	}
+	c.results = append(c.results, collected{job: job, seq: len(c.results), result: result})
+}
+
+// Results returns a copy of the results sorted by job, keeping the order
+// each job reported them in
+func (c *Collector) Results() []CheckResult {
+	var results []CheckResult
+	for _, entry := range c.sorted() {
+		results = append(results, entry.result)
+	}
+	return results
+}
+
+// byJob groups the sorted results under the job that produced them, for
+// merging client-scoped checks across regions
+func (c *Collector) byJob() []jobResult {
+	var grouped []jobResult
+	for _, entry := range c.sorted() {
+		if n := len(grouped); n > 0 && grouped[n-1].job.index == entry.job {
+			grouped[n-1].results = append(grouped[n-1].results, entry.result)
+			continue
+		}
+		job := probeJob{index: entry.job, region: entry.result.Region, check: entry.result.check}
+		if entry.job < len(c.jobs) {
+			job = c.jobs[entry.job]
+		}
+		grouped = append(grouped, jobResult{job: job, results: []CheckResult{entry.result}})
+	}
+	return grouped
+}
+
+// missing returns the jobs that have no results yet, in job order
+func (c *Collector) missing() []probeJob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	done := make([]bool, len(c.jobs))
+	for _, entry := range c.results {
+		if entry.job < len(c.jobs) {
+			done[entry.job] = true
+		}
+	}
+	var jobs []probeJob
+	for i, job := range c.jobs {
+		if !done[i] {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+func (c *Collector) sorted() []collected {
+	c.mu.Lock()
+	entries := slices.Clone(c.results)
+	c.mu.Unlock()
+	slices.SortFunc(entries, func(a, b collected) int {
+		return cmp.Or(cmp.Compare(a.job, b.job), cmp.Compare(a.seq, b.seq))
+	})
+	return entries
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCollectorConcurrentAdd(t *testing.T) {
+	cfg := newConfig()
+	cfg.Regions = []string{"us-east-1", "us-west-2"}
+	cfg.Checks = []string{"dns", "tcp", "tls"}
+	jobs := buildJobs(cfg)
+	const perJob = 50
+
+	collector := newCollector(jobs)
+	var wg sync.WaitGroup
+	// Add from every job in reverse order, with each job's results on one goroutine
+	for i := len(jobs) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range perJob {
+				collector.Add(CheckResult{Name: fmt.Sprint(n), Region: jobs[i].region, check: jobs[i].check})
+			}
+		}()
+	}
+	// Reading while adding must not race either
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		collector.Results()
+	}()
+	wg.Wait()
+
+	results := collector.Results()
+	if len(results) != len(jobs)*perJob {
+		t.Fatalf("got %d results, want %d", len(results), len(jobs)*perJob)
+	}
+	for i, result := range results {
+		job := jobs[i/perJob]
+		if result.check != job.check || result.Region != job.region || result.Name != fmt.Sprint(i%perJob) {
+			t.Fatalf("result %d = %s/%s #%s; want %s/%s #%d", i, result.Region, result.check, result.Name, job.region, job.check, i%perJob)
+		}
+	}
+
+	grouped := collector.byJob()
+	if len(grouped) != len(jobs) {
+		t.Fatalf("byJob returned %d jobs, want %d", len(grouped), len(jobs))
+	}
+	for i, group := range grouped {
+		if group.job != jobs[i] || len(group.results) != perJob {
+			t.Errorf("group %d = %+v with %d results; want %+v with %d", i, group.job, len(group.results), jobs[i], perJob)
+		}
+	}
+}
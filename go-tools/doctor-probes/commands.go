@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func main() {
+	args := os.Args[1:]
+	command := "doctor"
+	if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "doctor":
+		runDoctor(args)
+	case "version":
+		writeVersion(os.Stdout)
+	case "list-checks":
+		writeCheckList(os.Stdout)
+	case "list-regions":
+		runListRegions(args)
+	case "history":
+		runHistory(args)
+	case "schema":
+		writeSchema(os.Stdout)
+	case "codes":
+		writeCodes(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q (available: doctor, version, list-checks, list-regions, history, schema, codes)\n", command)
+		os.Exit(exitConfigError)
+	}
+}
+
+// parseFlags parses a command's flags. The flag package prints the error and
+// usage; a bad flag then exits with exitConfigError rather than its own 2,
+// which would read as a warning.
+func parseFlags(flags *flag.FlagSet, args []string) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(exitPass)
+		}
+		os.Exit(exitConfigError)
+	}
+}
+
+// writeCheckList prints every check category and how it is enabled
+func writeCheckList(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tNAME\tDEFAULT\tENABLE WITH\tTAGS")
+	for _, check := range checkRegistry {
+		enabled := "no"
+		if check.Default {
+			enabled = "yes"
+		}
+		enable := check.Flag
+		if enable == "" {
+			enable = "-"
+		}
+		tags := strings.Join(check.Tags, ",")
+		if tags == "" {
+			tags = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", check.Category, check.Name, enabled, enable, tags)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nTags for --tag: %s\n", strings.Join(checkTags, ", "))
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Regions compared by --compare-regions when --regions is not given
+var compareCandidateRegions = []string{
+	"us-east-1", "us-east-2", "us-west-2",
+	"ca-central-1", "sa-east-1",
+	"eu-central-1", "eu-west-1", "eu-west-2", "eu-west-3",
+	"ap-northeast-1", "ap-northeast-2", "ap-south-1", "ap-southeast-1", "ap-southeast-2",
+}
+
+type regionLatency struct {
+	region string
+	addr   string
+	tcp    time.Duration
+	total  time.Duration
+	err    error
+}
+
+// measureRegionLatency times the TCP connect and the TLS handshake on top of it
+func measureRegionLatency(ctx context.Context, cfg *Config, region string) regionLatency {
+	host := cfg.endpointHost("bedrock-runtime", region)
+	latency := regionLatency{region: region, addr: cfg.endpointAddr("bedrock-runtime", region)}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeoutFor("tls"))
+	defer cancel()
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", latency.addr)
+	if err != nil {
+		latency.err = err
+		return latency
+	}
+	defer conn.Close()
+	latency.tcp = time.Since(start)
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		latency.err = err
+		return latency
+	}
+	latency.total = time.Since(start)
+	logger.Debug("measured region latency", "region", region, "tcp", latency.tcp, "total", latency.total)
+	return latency
+}
+
+// runRegionComparison probes every region concurrently and reports them
+// fastest first; only unreachable regions fail
+func runRegionComparison(ctx context.Context, cfg *Config, regions []string) []CheckResult {
+	latencies := make([]regionLatency, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			latencies[i] = measureRegionLatency(ctx, cfg, region)
+		}()
+	}
+	wg.Wait()
+
+	// Reachable regions first, each group ordered by handshake latency
+	sort.SliceStable(latencies, func(i, j int) bool {
+		if (latencies[i].err == nil) != (latencies[j].err == nil) {
+			return latencies[i].err == nil
+		}
+		return latencies[i].total < latencies[j].total
+	})
+
+	results := make([]CheckResult, 0, len(latencies))
+	for i, latency := range latencies {
+		name := "Latency - " + latency.region
+		if latency.err != nil {
+			results = append(results, CheckResult{
+				Name:     name,
+				Status:   "fail",
+				Message:  fmt.Sprintf("Could not reach %s: %v", latency.addr, latency.err),
+				Fix:      "Check that Bedrock is available in this region and that outbound TCP 443 is allowed",
+				Category: "network",
+				Region:   latency.region,
+			})
+			continue
+		}
+
+		message := fmt.Sprintf("%s: TCP connect %dms, TLS handshake %dms", latency.addr, latency.tcp.Milliseconds(), latency.total.Milliseconds())
+		if i == 0 {
+			message += " (fastest)"
+		}
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    message,
+			Category:   "network",
+			Region:     latency.region,
+			DurationMs: latency.total.Milliseconds(),
+			LatencyMs:  latency.total.Milliseconds(),
+		})
+	}
+	return results
+}
@@ -0,0 +1,580 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the effective probe settings after merging the config file and flags
+type Config struct {
+	Regions                 []string                 `yaml:"regions,omitempty"`
+	Checks                  []string                 `yaml:"checks,omitempty"`
+	Timeout                 time.Duration            `yaml:"timeout,omitempty"`
+	Timeouts                map[string]time.Duration `yaml:"timeouts,omitempty"`
+	ConnectTimeout          time.Duration            `yaml:"connect_timeout,omitempty"`
+	Deadline                time.Duration            `yaml:"deadline,omitempty"`
+	WarnLatency             time.Duration            `yaml:"warn_latency,omitempty"`
+	HTTPLatencyWarn         time.Duration            `yaml:"http_latency_warn,omitempty"`
+	MaxLatency              time.Duration            `yaml:"max_latency,omitempty"`
+	Resolver                string                   `yaml:"resolver,omitempty"`
+	Retries                 int                      `yaml:"retries"`
+	RetryDelay              time.Duration            `yaml:"retry_delay,omitempty"`
+	Concurrency             int                      `yaml:"concurrency,omitempty"`
+	Samples                 int                      `yaml:"samples,omitempty"`
+	NoAgent                 bool                     `yaml:"no_agent,omitempty"`
+	Services                []string                 `yaml:"services,omitempty"`
+	FailFast                bool                     `yaml:"fail_fast,omitempty"`
+	RequireRegions          int                      `yaml:"require_regions,omitempty"`
+	Strict                  bool                     `yaml:"strict,omitempty"`
+	WarningsAsErrors        bool                     `yaml:"warnings_as_errors,omitempty"`
+	ExitPass                int                      `yaml:"exit_pass"`
+	ExitWarn                int                      `yaml:"exit_warn"`
+	ExitFail                int                      `yaml:"exit_fail"`
+	IPRangesTTL             time.Duration            `yaml:"ip_ranges_ttl,omitempty"`
+	CacheTTL                time.Duration            `yaml:"cache_ttl,omitempty"`
+	StartupJitter           time.Duration            `yaml:"startup_jitter,omitempty"`
+	AssumeOffline           bool                     `yaml:"assume_offline,omitempty"`
+	RegionFromInstance      bool                     `yaml:"region_from_instance,omitempty"`
+	IdleProbe               time.Duration            `yaml:"idle_probe,omitempty"`
+	SmokeModel              string                   `yaml:"smoke_model,omitempty"`
+	SmokeStreamWarn         time.Duration            `yaml:"smoke_stream_warn,omitempty"`
+	CheckModel              string                   `yaml:"check_model,omitempty"`
+	InferenceProfile        string                   `yaml:"inference_profile,omitempty"`
+	GuardrailID             string                   `yaml:"guardrail_id,omitempty"`
+	GuardrailVersion        string                   `yaml:"guardrail_version,omitempty"`
+	Model                   string                   `yaml:"model,omitempty"`
+	Profile                 string                   `yaml:"profile,omitempty"`
+	NoRedact                bool                     `yaml:"no_redact,omitempty"`
+	NoOverall               bool                     `yaml:"no_overall,omitempty"`
+	IncludeFixURLs          bool                     `yaml:"include_fix_urls,omitempty"`
+	ProbeOrdering           string                   `yaml:"probe_ordering,omitempty"`
+	Lang                    string                   `yaml:"lang,omitempty"`
+	Severity                map[string]string        `yaml:"severity,omitempty"`
+	Fixes                   map[string]string        `yaml:"fixes,omitempty"`
+	NTPServer               string                   `yaml:"ntp_server,omitempty"`
+	PluginDir               string                   `yaml:"plugin_dir,omitempty"`
+	Require                 []string                 `yaml:"require,omitempty"`
+	Quotas                  string                   `yaml:"quotas,omitempty"`
+	TLSInterceptionPatterns []string                 `yaml:"tls_interception_patterns,omitempty"`
+	VerifyToken             bool                     `yaml:"verify_token,omitempty"`
+	MinFree                 byteSize                 `yaml:"min_free,omitempty"`
+	MinOpenFiles            int                      `yaml:"min_open_files,omitempty"`
+	Workdir                 string                   `yaml:"workdir,omitempty"`
+	Hosts                   []string                 `yaml:"hosts,omitempty"`
+	RequireCLI              []string                 `yaml:"require_cli,omitempty"`
+	CaptivePortalURL        string                   `yaml:"captive_portal_url,omitempty"`
+	SourceIP                string                   `yaml:"source_ip,omitempty"`
+	EndpointURL             string                   `yaml:"endpoint_url,omitempty"`
+	HistoryDir              string                   `yaml:"history_dir,omitempty"`
+	HistoryRetention        time.Duration            `yaml:"history_retention,omitempty"`
+	Webhook                 string                   `yaml:"webhook,omitempty"`
+	WebhookTimeout          time.Duration            `yaml:"webhook_timeout,omitempty"`
+	WebhookHeaders          []string                 `yaml:"webhook_headers,omitempty"`
+	OTelEndpoint            string                   `yaml:"otel_endpoint,omitempty"`
+	SOCKS5                  string                   `yaml:"socks5,omitempty"`
+	SOCKS5RemoteDNS         bool                     `yaml:"socks5_remote_dns,omitempty"`
+	FIPS                    bool                     `yaml:"fips,omitempty"`
+	Endpoints               map[string]string        `yaml:"endpoints,omitempty"`
+}
+
+// newConfig returns a Config populated with defaults for settings that have them
+func newConfig() *Config {
+	return &Config{
+		Timeout:    defaultTimeout,
+		Retries:    defaultRetries,
+		RetryDelay: defaultRetryDelay,
+
+		ExitPass: exitPass,
+		ExitWarn: exitWarn,
+		ExitFail: exitFail,
+
+		WebhookTimeout:   defaultWebhookTimeout,
+		HistoryRetention: defaultHistoryRetention,
+
+		IPRangesTTL:  defaultIPRangesTTL,
+		NTPServer:    defaultNTPServer,
+		MinFree:      defaultMinFree,
+		MinOpenFiles: defaultMinOpenFiles,
+
+		SmokeStreamWarn: defaultSmokeStreamWarn,
+
+		CaptivePortalURL: defaultCaptivePortalURL,
+	}
+}
+
+func loadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("config file %s does not exist", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return parseConfig(data, path)
+}
+
+// stdinConfigName stands in for the file name in --config-stdin errors
+const stdinConfigName = "<stdin>"
+
+// loadConfigStdin reads a --config-stdin config from r. It takes the same keys
+// as a config file but must be JSON, so a driver's encoding bug is reported
+// as such rather than parsed as YAML.
+func loadConfigStdin(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config from stdin: %w", err)
+	}
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("--config-stdin expects a JSON object: %w", err)
+	}
+	return parseConfig(data, stdinConfigName)
+}
+
+// parseConfig decodes and validates a config read from path
+func parseConfig(data []byte, path string) (*Config, error) {
+	cfg := newConfig()
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	for _, check := range cfg.Checks {
+		if !slices.Contains(checkCategories, check) {
+			return nil, fmt.Errorf("config file %s: unknown check %q (valid: %s)", path, check, strings.Join(checkCategories, ", "))
+		}
+	}
+	if cfg.Timeout < 0 {
+		return nil, fmt.Errorf("config file %s: timeout must be greater than zero", path)
+	}
+	for check, timeout := range cfg.Timeouts {
+		if !slices.Contains(checkCategories, check) {
+			return nil, fmt.Errorf("config file %s: unknown check %q in timeouts (valid: %s)", path, check, strings.Join(checkCategories, ", "))
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("config file %s: timeout for %s must be greater than zero", path, check)
+		}
+	}
+	if cfg.ConnectTimeout < 0 {
+		return nil, fmt.Errorf("config file %s: connect_timeout must be greater than zero", path)
+	}
+	if cfg.Deadline < 0 {
+		return nil, fmt.Errorf("config file %s: deadline must be greater than zero", path)
+	}
+	if cfg.Concurrency < 0 {
+		return nil, fmt.Errorf("config file %s: concurrency must be greater than zero", path)
+	}
+	if cfg.EndpointURL != "" {
+		if _, _, err := parseEndpoint(cfg.EndpointURL); err != nil {
+			return nil, fmt.Errorf("config file %s: invalid endpoint_url %q: %v", path, cfg.EndpointURL, err)
+		}
+	}
+	for service, endpoint := range cfg.Endpoints {
+		if _, _, err := parseEndpoint(endpoint); err != nil {
+			return nil, fmt.Errorf("config file %s: invalid endpoint for %s %q: %v", path, service, endpoint, err)
+		}
+	}
+	if cfg.Resolver != "" {
+		server, err := normalizeResolver(cfg.Resolver)
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: invalid resolver %q: %v", path, cfg.Resolver, err)
+		}
+		cfg.Resolver = server
+	}
+	if cfg.Retries < 0 {
+		return nil, fmt.Errorf("config file %s: retries must not be negative", path)
+	}
+	if cfg.RetryDelay < 0 {
+		return nil, fmt.Errorf("config file %s: retry_delay must not be negative", path)
+	}
+	if cfg.CacheTTL < 0 {
+		return nil, fmt.Errorf("config file %s: cache_ttl must not be negative", path)
+	}
+	if cfg.StartupJitter < 0 {
+		return nil, fmt.Errorf("config file %s: startup_jitter must not be negative", path)
+	}
+	for key, code := range map[string]int{"exit_pass": cfg.ExitPass, "exit_warn": cfg.ExitWarn, "exit_fail": cfg.ExitFail} {
+		if err := validExitCode(code); err != nil {
+			return nil, fmt.Errorf("config file %s: %s %v", path, key, err)
+		}
+	}
+	if cfg.GuardrailVersion != "" && cfg.GuardrailID == "" {
+		return nil, fmt.Errorf("config file %s: guardrail_version requires guardrail_id", path)
+	}
+	for pattern, status := range cfg.Severity {
+		if err := validSeverity(pattern, status); err != nil {
+			return nil, fmt.Errorf("config file %s: %v", path, err)
+		}
+	}
+	for pattern, fix := range cfg.Fixes {
+		if err := validFixOverride(pattern, fix); err != nil {
+			return nil, fmt.Errorf("config file %s: %v", path, err)
+		}
+	}
+	if cfg.ProbeOrdering != "" && !slices.Contains(probeOrderings, cfg.ProbeOrdering) {
+		return nil, fmt.Errorf("config file %s: unknown probe_ordering %q (valid: %s)", path, cfg.ProbeOrdering, strings.Join(probeOrderings, ", "))
+	}
+	if cfg.Lang != "" && !slices.Contains(languages(), cfg.Lang) {
+		return nil, fmt.Errorf("config file %s: unknown lang %q (valid: %s)", path, cfg.Lang, strings.Join(languages(), ", "))
+	}
+	if cfg.Webhook != "" {
+		if err := validWebhookURL(cfg.Webhook); err != nil {
+			return nil, fmt.Errorf("config file %s: webhook %v", path, err)
+		}
+	}
+	if cfg.OTelEndpoint != "" {
+		if err := validWebhookURL(cfg.OTelEndpoint); err != nil {
+			return nil, fmt.Errorf("config file %s: otel_endpoint %v", path, err)
+		}
+	}
+	if cfg.SOCKS5 != "" {
+		if _, _, err := parseSOCKS5(cfg.SOCKS5); err != nil {
+			return nil, fmt.Errorf("config file %s: socks5 %v", path, err)
+		}
+	}
+	if cfg.SOCKS5RemoteDNS && cfg.SOCKS5 == "" {
+		return nil, fmt.Errorf("config file %s: socks5_remote_dns requires socks5", path)
+	}
+	if cfg.HistoryRetention <= 0 {
+		return nil, fmt.Errorf("config file %s: history_retention must be greater than zero", path)
+	}
+	if cfg.WebhookTimeout <= 0 {
+		return nil, fmt.Errorf("config file %s: webhook_timeout must be greater than zero", path)
+	}
+	for _, header := range cfg.WebhookHeaders {
+		if _, _, err := parseHeader(header); err != nil {
+			return nil, fmt.Errorf("config file %s: webhook_headers: %v", path, err)
+		}
+	}
+	if cfg.MinOpenFiles <= 0 {
+		return nil, fmt.Errorf("config file %s: min_open_files must be greater than zero", path)
+	}
+	if cfg.IdleProbe < 0 {
+		return nil, fmt.Errorf("config file %s: idle_probe must not be negative", path)
+	}
+	if cfg.IPRangesTTL < 0 {
+		return nil, fmt.Errorf("config file %s: ip_ranges_ttl must not be negative", path)
+	}
+	if cfg.PluginDir != "" {
+		if info, err := os.Stat(cfg.PluginDir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("config file %s: plugin_dir %s is not a directory", path, cfg.PluginDir)
+		}
+	}
+	if cfg.HTTPLatencyWarn < 0 {
+		return nil, fmt.Errorf("config file %s: http_latency_warn must not be negative", path)
+	}
+	if len(cfg.Services) > 0 {
+		services, err := parseServices(strings.Join(cfg.Services, ","))
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: services: %v", path, err)
+		}
+		cfg.Services = services
+	}
+	if cfg.RequireRegions < 0 {
+		return nil, fmt.Errorf("config file %s: require_regions must not be negative", path)
+	}
+	if cfg.Samples < 0 {
+		return nil, fmt.Errorf("config file %s: samples must not be negative", path)
+	}
+	if cfg.SmokeStreamWarn < 0 {
+		return nil, fmt.Errorf("config file %s: smoke_stream_warn must not be negative", path)
+	}
+	if cfg.WarnLatency < 0 {
+		return nil, fmt.Errorf("config file %s: warn_latency must not be negative", path)
+	}
+	if cfg.MaxLatency < 0 {
+		return nil, fmt.Errorf("config file %s: max_latency must not be negative", path)
+	}
+	if cfg.SourceIP != "" && net.ParseIP(cfg.SourceIP) == nil {
+		return nil, fmt.Errorf("config file %s: source_ip %q is not an IP address", path, cfg.SourceIP)
+	}
+	for _, host := range cfg.Hosts {
+		if err := parseHostPort(host); err != nil {
+			return nil, fmt.Errorf("config file %s: hosts entry %q: %v", path, host, err)
+		}
+	}
+	for _, entry := range cfg.RequireCLI {
+		if _, err := parseRequiredCLI(entry); err != nil {
+			return nil, fmt.Errorf("config file %s: require_cli entry %q: %v", path, entry, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// timeoutFor returns the per-category timeout, falling back to the global one
+func (c *Config) timeoutFor(check string) time.Duration {
+	if timeout, ok := c.Timeouts[check]; ok {
+		return timeout
+	}
+	return c.Timeout
+}
+
+// parseTimeouts parses --timeout, which is either a single duration or a list
+// like "5s,dns=3s,smoke=30s" where bare durations set the global default
+func parseTimeouts(value string) (time.Duration, map[string]time.Duration, error) {
+	var global time.Duration
+	perCheck := map[string]time.Duration{}
+	for _, entry := range splitList(value) {
+		check, raw, scoped := strings.Cut(entry, "=")
+		if !scoped {
+			raw = entry
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err == nil && timeout <= 0 {
+			err = fmt.Errorf("must be greater than zero")
+		}
+		if err != nil {
+			return 0, nil, fmt.Errorf("%q: %v", entry, err)
+		}
+
+		if !scoped {
+			global = timeout
+			continue
+		}
+		check = strings.TrimSpace(check)
+		if !slices.Contains(checkCategories, check) {
+			return 0, nil, fmt.Errorf("unknown check %q (valid: %s)", check, strings.Join(checkCategories, ", "))
+		}
+		perCheck[check] = timeout
+	}
+	return global, perCheck, nil
+}
+
+func (c *Config) enabled(check string) bool {
+	return slices.Contains(c.Checks, check)
+}
+
+// setEnabled adds or removes a check category so an explicit flag wins over the config file
+func (c *Config) setEnabled(check string, on bool) {
+	if !on {
+		c.Checks = slices.DeleteFunc(c.Checks, func(name string) bool { return name == check })
+	} else if !c.enabled(check) {
+		c.Checks = append(c.Checks, check)
+	}
+}
+
+// parseEndpoint accepts a bare hostname or a URL and returns its host and port
+func parseEndpoint(endpoint string) (host, port string, err error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Hostname() == "" {
+		return "", "", fmt.Errorf("missing hostname")
+	}
+	port = u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return u.Hostname(), port, nil
+}
+
+// endpointOverride returns the user-supplied host and port for a service, if any
+func (c *Config) endpointOverride(service string) (host, port string, ok bool) {
+	endpoint := c.Endpoints[service]
+	if service == "bedrock-runtime" && c.EndpointURL != "" {
+		endpoint = c.EndpointURL
+	}
+	if endpoint == "" {
+		return "", "", false
+	}
+	host, port, err := parseEndpoint(endpoint)
+	return host, port, err == nil
+}
+
+// endpointHost returns the configured override for a service, or its regional (optionally FIPS) hostname
+func (c *Config) endpointHost(service, region string) string {
+	if host, _, ok := c.endpointOverride(service); ok {
+		return host
+	}
+	if c.FIPS {
+		return fmt.Sprintf("%s-fips.%s.amazonaws.com", service, region)
+	}
+	return fmt.Sprintf("%s.%s.amazonaws.com", service, region)
+}
+
+// endpointAddr returns the host:port to dial for a service
+func (c *Config) endpointAddr(service, region string) string {
+	if host, port, ok := c.endpointOverride(service); ok {
+		return net.JoinHostPort(host, port)
+	}
+	return net.JoinHostPort(c.endpointHost(service, region), "443")
+}
+
+// endpointLabel appends the custom host to a check label so overridden endpoints are unambiguous
+func (c *Config) endpointLabel(label, service string) string {
+	if host, _, ok := c.endpointOverride(service); ok {
+		return fmt.Sprintf("%s (%s)", label, host)
+	}
+	return label
+}
+
+// normalizeResolver validates a DNS server address, defaulting the port to 53
+func normalizeResolver(server string) (string, error) {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server, nil
+	}
+	if net.ParseIP(strings.Trim(server, "[]")) == nil && strings.Contains(server, ":") {
+		return "", fmt.Errorf("expected host:port")
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), "53"), nil
+}
+
+// dialer returns the dialer for TCP and TLS probes, bound to source_ip when
+// set. connect_timeout bounds only the TCP connect; the probe's context still
+// bounds the whole probe.
+func (c *Config) dialer() *net.Dialer {
+	dialer := &net.Dialer{Timeout: c.ConnectTimeout}
+	if ip := net.ParseIP(c.SourceIP); ip != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	return dialer
+}
+
+// addressTimeout bounds one address's dial in the TCP probe: connect_timeout
+// when set, else the per-address cap, and never more than the probe timeout
+func (c *Config) addressTimeout() time.Duration {
+	if c.ConnectTimeout > 0 {
+		return min(c.Timeout, c.ConnectTimeout)
+	}
+	return min(c.Timeout, perAddressTimeout)
+}
+
+// resolver returns the DNS resolver to use, honoring a custom server override
+func (c *Config) resolver() *net.Resolver {
+	if c.Resolver == "" {
+		return net.DefaultResolver
+	}
+
+	server := c.Resolver
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+func (c *Config) resolverName() string {
+	if c.Resolver == "" {
+		return "system"
+	}
+	return c.Resolver
+}
+
+// matchingChecks returns the categories a pattern selects. An exact or glob
+// match against a category or its label wins; substring matching is only a
+// fallback so that "dns" does not also pick up "reverse dns".
+func matchingChecks(pattern string) []string {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	candidates := func(check string) []string {
+		return []string{check, strings.ToLower(checkLabels[check])}
+	}
+
+	var matched []string
+	for _, check := range checkCategories {
+		for _, candidate := range candidates(check) {
+			if ok, _ := path.Match(pattern, candidate); ok {
+				matched = append(matched, check)
+				break
+			}
+		}
+	}
+	if len(matched) > 0 {
+		return matched
+	}
+
+	for _, check := range checkCategories {
+		for _, candidate := range candidates(check) {
+			if strings.Contains(candidate, pattern) {
+				matched = append(matched, check)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// filterChecks applies --tag, --only and --skip. --tag and --only select from
+// every known category so opt-in checks can be requested directly; an --only
+// pattern that matches nothing or an unknown tag is an error. Tags pass over
+// checks that are missing their setting, and billed checks not enabled with
+// their own flag; naming an unconfigured check in --only is an error, while a
+// glob passes over it.
+func filterChecks(cfg *Config, only, skip, tags []string) ([]string, error) {
+	checks := cfg.Checks
+	var tagged []string
+	if len(tags) > 0 {
+		var err error
+		if tagged, err = taggedChecks(tags); err != nil {
+			return nil, err
+		}
+		tagged = slices.DeleteFunc(tagged, func(category string) bool {
+			check, _ := lookupCheck(category)
+			if !check.configured(cfg) || (check.Billable && !cfg.enabled(category)) {
+				logger.Debug("tag does not select check", "check", category, "setting", check.Setting, "billable", check.Billable)
+				return true
+			}
+			return false
+		})
+		checks = tagged
+	}
+	if len(only) > 0 {
+		var selected []string
+		for _, pattern := range only {
+			matched := matchingChecks(pattern)
+			if len(matched) == 0 {
+				return nil, fmt.Errorf("--only pattern %q matches no checks (available: %s)", pattern, strings.Join(checkCategories, ", "))
+			}
+			for _, check := range matched {
+				// With --tag, --only narrows the tagged checks instead of adding to them
+				if len(tags) > 0 && !slices.Contains(tagged, check) {
+					continue
+				}
+				if entry, _ := lookupCheck(check); !entry.configured(cfg) {
+					if strings.ContainsAny(pattern, "*?[") {
+						continue
+					}
+					return nil, fmt.Errorf("--only %q selects the %s check, which needs %s (or %s in the config file)", pattern, check, entry.Flag, entry.Setting)
+				}
+				if !slices.Contains(selected, check) {
+					selected = append(selected, check)
+				}
+			}
+		}
+		checks = selected
+	}
+
+	var skipped []string
+	for _, pattern := range skip {
+		skipped = append(skipped, matchingChecks(pattern)...)
+	}
+
+	var filtered []string
+	for _, check := range checks {
+		if !slices.Contains(skipped, check) {
+			filtered = append(filtered, check)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		checks  []string
+		only    []string
+		skip    []string
+		want    []string
+		wantErr bool
+	}{
+		{"no filters", defaultChecks, nil, nil, defaultChecks, false},
+		{"only exact category", defaultChecks, []string{"dns"}, nil, []string{"dns"}, false},
+		{"only is case-insensitive", defaultChecks, []string{"DNS"}, nil, []string{"dns"}, false},
+		{"only label", defaultChecks, []string{"Reverse DNS"}, nil, []string{"rdns"}, false},
+		{"only glob", defaultChecks, []string{"t*"}, nil, []string{"tcp", "tls"}, false},
+		{"only enables opt-in", defaultChecks, []string{"creds"}, nil, []string{"creds"}, false},
+		{"only substring fallback", defaultChecks, []string{"smoke"}, nil, []string{"smoke"}, false},
+		{"only substring of label", defaultChecks, []string{"range"}, nil, []string{"ipranges"}, false},
+		{"only unknown", defaultChecks, []string{"bogus"}, nil, nil, true},
+		{"skip exact", defaultChecks, nil, []string{"tls"}, []string{"region", "dns", "tcp", "proxy"}, false},
+		{"skip dns keeps rdns", []string{"dns", "rdns"}, nil, []string{"dns"}, []string{"rdns"}, false},
+		{"skip unknown is ignored", defaultChecks, nil, []string{"bogus"}, defaultChecks, false},
+		{"only and skip", defaultChecks, []string{"t*"}, []string{"tcp"}, []string{"tls"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			cfg.Checks = tt.checks
+			got, err := filterChecks(cfg, tt.only, tt.skip, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("filterChecks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("filterChecks(%v, %v, %v) = %v, want %v", tt.checks, tt.only, tt.skip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterChecksByTag(t *testing.T) {
+	cfg := newConfig()
+	cfg.IdleProbe = time.Minute
+	got, err := filterChecks(cfg, nil, []string{"mtu"}, []string{"streaming"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(got, "idle") || slices.Contains(got, "mtu") || slices.Contains(got, "region") {
+		t.Errorf("--tag streaming --skip mtu = %v, want tagged opt-in checks without mtu or untagged defaults", got)
+	}
+	if got, _ := filterChecks(newConfig(), []string{"t*"}, nil, []string{"Offline"}); len(got) != 0 {
+		t.Errorf("--tag offline --only 't*' = %v, want nothing since no TCP/TLS check is offline", got)
+	}
+	if got, _ := filterChecks(newConfig(), []string{"d*"}, nil, []string{"streaming"}); !slices.Equal(got, []string{"dns"}) {
+		t.Errorf("--tag streaming --only 'd*' = %v, want [dns]", got)
+	}
+	if _, err := filterChecks(newConfig(), nil, nil, []string{"bogus"}); err == nil {
+		t.Error("an unknown tag must be an error")
+	}
+}
+
+func TestFilterChecksNeedsSetting(t *testing.T) {
+	// With the default config, tags must leave out checks that need a setting
+	// and checks that bill the account
+	for _, tag := range []string{"aws-api", "streaming"} {
+		got, err := filterChecks(newConfig(), nil, nil, []string{tag})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, category := range got {
+			if check, _ := lookupCheck(category); check.Setting != "" || check.Billable {
+				t.Errorf("--tag %s selected %s, which needs %q or is billed", tag, category, check.Setting)
+			}
+		}
+		if len(got) == 0 {
+			t.Errorf("--tag %s selected nothing", tag)
+		}
+	}
+
+	cfg := newConfig()
+	cfg.GuardrailID = "gr1"
+	cfg.setEnabled("smoke", true)
+	got, _ := filterChecks(cfg, nil, nil, []string{"aws-api"})
+	if !slices.Contains(got, "guardrail") || !slices.Contains(got, "smoke") || slices.Contains(got, "smokestream") {
+		t.Errorf("--tag aws-api --guardrail-id gr1 --smoke-test = %v, want guardrail and smoke but not smokestream", got)
+	}
+
+	for _, only := range []string{"guardrail", "model", "quotas", "idle", "Idle Connection"} {
+		if _, err := filterChecks(newConfig(), []string{only}, nil, nil); err == nil || !strings.Contains(err.Error(), "needs --") {
+			t.Errorf("--only %s without its setting = %v, want an error naming the flag", only, err)
+		}
+	}
+	if got, err := filterChecks(newConfig(), []string{"g*"}, nil, nil); err != nil || slices.Contains(got, "guardrail") {
+		t.Errorf("--only 'g*' = %v, %v, want the glob to pass over guardrail", got, err)
+	}
+	if got, err := filterChecks(cfg, []string{"guardrail"}, nil, nil); err != nil || !slices.Equal(got, []string{"guardrail"}) {
+		t.Errorf("--only guardrail --guardrail-id gr1 = %v, %v, want [guardrail]", got, err)
+	}
+}
+
+func TestParseTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		global   time.Duration
+		perCheck map[string]time.Duration
+		wantErr  bool
+	}{
+		{"global only", "5s", 5 * time.Second, map[string]time.Duration{}, false},
+		{"per check only", "dns=3s,smoke=30s", 0, map[string]time.Duration{"dns": 3 * time.Second, "smoke": 30 * time.Second}, false},
+		{"global and per check", "8s, tls=5s", 8 * time.Second, map[string]time.Duration{"tls": 5 * time.Second}, false},
+		{"unknown check", "bogus=3s", 0, nil, true},
+		{"invalid duration", "dns=fast", 0, nil, true},
+		{"zero", "dns=0s", 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			global, perCheck, err := parseTimeouts(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTimeouts(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if global != tt.global || !maps.Equal(perCheck, tt.perCheck) {
+				t.Errorf("parseTimeouts(%q) = %v, %v, want %v, %v", tt.value, global, perCheck, tt.global, tt.perCheck)
+			}
+		})
+	}
+}
+
+func TestConnectTimeout(t *testing.T) {
+	tests := []struct {
+		timeout, connect, wantAddress time.Duration
+	}{
+		{10 * time.Second, 0, perAddressTimeout},
+		{2 * time.Second, 0, 2 * time.Second},
+		{10 * time.Second, 500 * time.Millisecond, 500 * time.Millisecond},
+		// A per-check --timeout below connect_timeout still wins
+		{time.Second, 2 * time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		cfg := newConfig()
+		cfg.Timeout, cfg.ConnectTimeout = tt.timeout, tt.connect
+		if got := cfg.addressTimeout(); got != tt.wantAddress {
+			t.Errorf("addressTimeout() with timeout %s, connect_timeout %s = %s, want %s", tt.timeout, tt.connect, got, tt.wantAddress)
+		}
+		if got := cfg.dialer().Timeout; got != tt.connect {
+			t.Errorf("dialer().Timeout = %s, want %s", got, tt.connect)
+		}
+	}
+}
+
+func TestLoadConfigStdin(t *testing.T) {
+	cfg, err := loadConfigStdin(strings.NewReader(`{"regions": ["eu-west-1", "us-west-2"], "checks": ["dns", "tcp"], "timeout": "4s", "endpoint_url": "https://bedrock.example.com"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.Regions, []string{"eu-west-1", "us-west-2"}) || !slices.Equal(cfg.Checks, []string{"dns", "tcp"}) || cfg.Timeout != 4*time.Second || cfg.EndpointURL != "https://bedrock.example.com" {
+		t.Errorf("loadConfigStdin() = %+v", cfg)
+	}
+
+	for input, want := range map[string]string{
+		`{"regions": ["eu-west-1"]`: "expects a JSON object",
+		"regions: [eu-west-1]":      "expects a JSON object",
+		`["eu-west-1"]`:             "expects a JSON object",
+		`{"checks": ["nope"]}`:      "config file <stdin>: unknown check",
+		`{"region": "eu-west-1"}`:   "field region not found",
+	} {
+		if _, err := loadConfigStdin(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("loadConfigStdin(%s) error = %v, want one containing %q", input, err, want)
+		}
+	}
+}
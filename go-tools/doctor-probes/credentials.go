@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+)
+
+const (
+	noCredentialsFix      = "Run 'aws configure', 'aws sso login', or export AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY"
+	expiredCredentialsFix = "Session token has expired; refresh it with 'aws sso login' or re-run your credential process"
+)
+
+// credentialsExpired reports whether a credential or API error means an existing session has lapsed
+func credentialsExpired(err error) bool {
+	var tokenErr *ssocreds.InvalidTokenError
+	if errors.As(err, &tokenErr) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ExpiredToken", "ExpiredTokenException", "RequestExpired", "UnauthorizedException":
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "expired")
+}
+
+// credentialFailure reports an auth failure unless cause shows the request never reached STS
+func credentialFailure(message, fix string, cause error) []CheckResult {
+	return []CheckResult{{
+		Name:      "Credentials - STS",
+		Status:    "fail",
+		Message:   message,
+		Fix:       fix,
+		ErrorKind: newProbeError(ErrorKindAuth, cause).Kind,
+		reason:    reasonCredentials,
+	}}
+}
+
+func runCredentialChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	// Load AWS config using the default credential chain
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	logger.Debug("loaded AWS config", "region", region, "profile", cfg.Profile, "error", err)
+	if err != nil {
+		return credentialFailure(
+			fmt.Sprintf("Failed to load AWS config: %v", err),
+			"Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+			err,
+		)
+	}
+
+	// Resolve credentials up front so a missing chain is reported separately from API errors
+	if awsCfg.Credentials == nil {
+		return credentialFailure("No AWS credentials found", noCredentialsFix, nil)
+	}
+	if _, err := awsCfg.Credentials.Retrieve(ctx); err != nil {
+		if credentialsExpired(err) {
+			return credentialFailure(fmt.Sprintf("AWS credentials have expired: %v", err), expiredCredentialsFix, err)
+		}
+		var processErr *processcreds.ProviderError
+		if errors.As(err, &processErr) {
+			return credentialFailure(
+				fmt.Sprintf("credential_process failed: %v", err),
+				"Run the profile's credential_process command by hand to see why it fails",
+				err,
+			)
+		}
+		return credentialFailure(fmt.Sprintf("No AWS credentials found: %v", err), noCredentialsFix, err)
+	}
+
+	logger.Debug("calling sts:GetCallerIdentity", "region", region)
+	stsClient := sts.NewFromConfig(awsCfg)
+	identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		fix := awsErrorFix(err, "Check that the configured credentials are valid for this account")
+		var apiErr smithy.APIError
+		if credentialsExpired(err) {
+			fix = expiredCredentialsFix
+		} else if errors.As(err, &apiErr) {
+			switch apiErr.ErrorCode() {
+			case "AccessDenied", "AccessDeniedException":
+				// IAM policies cannot deny GetCallerIdentity, so something outside them is blocking it
+				fix = "GetCallerIdentity cannot be denied by IAM policies; check for an SCP or STS VPC endpoint policy blocking it, or whether this principal has been disabled or deleted"
+			case "InvalidClientTokenId", "SignatureDoesNotMatch":
+				fix = "Access key is invalid or revoked; rotate it and update your credentials"
+			}
+		}
+		return credentialFailure(fmt.Sprintf("GetCallerIdentity failed: %v", err), fix, err)
+	}
+
+	message := fmt.Sprintf("Authenticated as %s (account %s)", aws.ToString(identity.Arn), aws.ToString(identity.Account))
+	if cfg.Profile != "" {
+		message = fmt.Sprintf("Authenticated as %s (account %s, profile %s)", aws.ToString(identity.Arn), aws.ToString(identity.Account), cfg.Profile)
+	}
+	return []CheckResult{{
+		Name:    "Credentials - STS",
+		Status:  "pass",
+		Message: message,
+	}}
+}
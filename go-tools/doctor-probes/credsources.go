@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+)
+
+// credentialSource is one place the default credential chain could take
+// credentials from. Only where they come from is kept, never the values.
+type credentialSource struct {
+	Kind   string
+	Detail string
+}
+
+func (s credentialSource) String() string {
+	if s.Detail == "" {
+		return s.Kind
+	}
+	return fmt.Sprintf("%s (%s)", s.Kind, s.Detail)
+}
+
+// profileCredentialDetail describes how a shared config profile provides
+// credentials, following the same order as the SDK, or "" when it has none
+func profileCredentialDetail(shared *config.SharedConfig) string {
+	switch {
+	case shared.Source != nil:
+		if detail := profileCredentialDetail(shared.Source); detail != "" {
+			return fmt.Sprintf("assumes a role from profile %s, which has %s", shared.Source.Profile, detail)
+		}
+		return fmt.Sprintf("assumes a role from profile %s", shared.Source.Profile)
+	case shared.Credentials.HasKeys():
+		return "static keys in the shared credentials file"
+	case shared.CredentialSource != "":
+		return "assumes a role with credential_source " + shared.CredentialSource
+	case shared.WebIdentityTokenFile != "":
+		return "web identity token file"
+	case shared.SSOSession != nil || shared.SSOSessionName != "" || shared.SSOStartURL != "":
+		_, key := ssoTokenKey(shared)
+		if path, err := ssocreds.StandardCachedTokenFilepath(key); err == nil && fileExists(path) {
+			return "IAM Identity Center, with a cached token"
+		}
+		return "IAM Identity Center, without a cached token"
+	case shared.CredentialProcess != "":
+		return "credential_process"
+	}
+	return ""
+}
+
+// findCredentialSources lists every credential source present, and returns the
+// index of the one the SDK's default chain picks, or -1. A --profile override
+// is passed to the SDK explicitly, which makes it skip the environment keys
+// and web identity variables entirely.
+func findCredentialSources(ctx context.Context, profileOverride string, env *Environment) ([]credentialSource, int) {
+	var sources []credentialSource
+	chosen := -1
+	add := func(source credentialSource, usable bool) {
+		sources = append(sources, source)
+		if usable && chosen == -1 {
+			chosen = len(sources) - 1
+		}
+	}
+
+	profile, from := activeProfile(profileOverride)
+	configFile, credentialsFile := sharedConfigFiles()
+	profileSource := credentialSource{Kind: "profile " + profile}
+	shared, err := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		o.ConfigFiles = []string{configFile}
+		o.CredentialsFiles = []string{credentialsFile}
+	})
+	if err == nil {
+		profileSource.Detail = profileCredentialDetail(&shared)
+	}
+	hasProfile := profileSource.Detail != ""
+	logger.Debug("inspected profile for credentials", "profile", profile, "source", from, "credentials", profileSource.Detail, "error", err)
+
+	if profileOverride != "" && hasProfile {
+		add(profileSource, true)
+	}
+	if os.Getenv("AWS_ACCESS_KEY_ID") != "" && os.Getenv("AWS_SECRET_ACCESS_KEY") != "" {
+		detail := "long-term keys"
+		if os.Getenv("AWS_SESSION_TOKEN") != "" {
+			detail = "temporary keys with a session token"
+		}
+		add(credentialSource{Kind: "environment variables", Detail: detail}, profileOverride == "")
+	}
+	if os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE") != "" {
+		add(credentialSource{Kind: "AWS_WEB_IDENTITY_TOKEN_FILE", Detail: "web identity role"}, profileOverride == "")
+	}
+	if hasProfile && profileOverride == "" {
+		add(profileSource, true)
+	}
+	if os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI") != "" || os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI") != "" {
+		add(credentialSource{Kind: "container credentials endpoint", Detail: "ECS task role or EKS Pod Identity"}, true)
+	}
+	if (env.Platform == "ec2" || env.Platform == "eks") && !strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		add(credentialSource{Kind: "instance metadata", Detail: "EC2 instance profile"}, true)
+	}
+	return sources, chosen
+}
+
+// runCredentialSourceChecks reports which credential source the SDK will use
+// and warns when others are present, since the one picked by precedence may
+// not be the one the user refreshed
+func runCredentialSourceChecks(ctx context.Context, cfg *Config) []CheckResult {
+	name := "Credentials - Sources"
+	sources, chosen := findCredentialSources(ctx, cfg.Profile, currentEnvironment())
+	var present []string
+	for _, source := range sources {
+		present = append(present, source.String())
+	}
+	switch {
+	case len(sources) == 0:
+		return []CheckResult{{
+			Name:    name,
+			Status:  "warn",
+			Message: "No credential source found: no environment keys, profile credentials, container endpoint, or instance role",
+			Fix:     "Run 'aws configure' or 'aws sso login', or export AWS_PROFILE for a profile that has credentials",
+		}}
+	case chosen == -1:
+		return []CheckResult{{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("--profile %s has no credentials, and the SDK ignores %s when a profile is passed explicitly", cfg.Profile, strings.Join(present, " and ")),
+			Fix:     fmt.Sprintf("Add credentials to profile %s, or drop --profile", cfg.Profile),
+		}}
+	case len(sources) == 1:
+		return []CheckResult{{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("The SDK will use %s, the only credential source present", sources[0]),
+		}}
+	}
+
+	ignored := append(slices.Clone(present[:chosen]), present[chosen+1:]...)
+	fix := "If those aren't the credentials you meant, remove the ones you don't use"
+	if sources[chosen].Kind == "environment variables" {
+		fix = "Unset AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN to fall through to the profile, or pass --profile to prefer it"
+	}
+	return []CheckResult{{
+		Name:    name,
+		Status:  "warn",
+		Message: fmt.Sprintf("%d credential sources are present; the SDK will use %s and ignore %s", len(sources), sources[chosen], strings.Join(ignored, ", ")),
+		Fix:     fix,
+	}}
+}
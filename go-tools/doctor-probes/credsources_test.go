@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindCredentialSources(t *testing.T) {
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(credentialsFile, []byte("[dev]\naws_access_key_id = AKIDEXAMPLE\naws_secret_access_key = secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	for _, name := range []string{"AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_WEB_IDENTITY_TOKEN_FILE",
+		"AWS_CONTAINER_CREDENTIALS_FULL_URI", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_EC2_METADATA_DISABLED"} {
+		t.Setenv(name, "")
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		override string
+		platform string
+		want     []string
+		chosen   int
+	}{
+		{"none", nil, "", "", nil, -1},
+		{"profile only", map[string]string{"AWS_PROFILE": "dev"}, "", "", []string{"profile dev"}, 0},
+		{"env keys win over AWS_PROFILE", map[string]string{"AWS_PROFILE": "dev", "AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "s"}, "", "", []string{"environment variables", "profile dev"}, 0},
+		{"--profile wins over env keys", map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "s"}, "dev", "", []string{"profile dev", "environment variables"}, 0},
+		{"--profile without credentials", map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "s"}, "missing", "", []string{"environment variables"}, -1},
+		{"instance role fallback", map[string]string{"AWS_PROFILE": "dev"}, "", "ec2", []string{"profile dev", "instance metadata"}, 0},
+		{"IMDS disabled", map[string]string{"AWS_EC2_METADATA_DISABLED": "true"}, "", "ec2", nil, -1},
+		{"container", map[string]string{"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI": "/v2/credentials/x"}, "", "ecs", []string{"container credentials endpoint"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			sources, chosen := findCredentialSources(context.Background(), tt.override, &Environment{Platform: tt.platform})
+			var kinds []string
+			for _, source := range sources {
+				kinds = append(kinds, source.Kind)
+			}
+			if !slices.Equal(kinds, tt.want) || chosen != tt.chosen {
+				t.Errorf("sources = %v, chosen %d; want %v, chosen %d", kinds, chosen, tt.want, tt.chosen)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Destination regions of the geographic cross-region inference profiles,
+// from the Bedrock user guide's supported regions table. The SDK version
+// pinned here predates GetInferenceProfile, and the table changes rarely.
+var profileRegions = map[string][]string{
+	"us":     {"us-east-1", "us-east-2", "us-west-1", "us-west-2"},
+	"us-gov": {"us-gov-east-1", "us-gov-west-1"},
+	"eu":     {"eu-central-1", "eu-north-1", "eu-south-1", "eu-south-2", "eu-west-1", "eu-west-3"},
+	"apac":   {"ap-northeast-1", "ap-northeast-2", "ap-northeast-3", "ap-south-1", "ap-south-2", "ap-southeast-1", "ap-southeast-2", "ap-southeast-4"},
+	"jp":     {"ap-northeast-1", "ap-northeast-3"},
+	"au":     {"ap-southeast-2", "ap-southeast-4"},
+}
+
+// inferenceProfileRegions returns the regions a cross-region inference profile
+// ID can route requests to
+func inferenceProfileRegions(profileID string) ([]string, error) {
+	prefix := strings.TrimSuffix(inferenceProfilePrefix.FindString(profileID), ".")
+	switch {
+	case strings.HasPrefix(profileID, "arn:"):
+		return nil, fmt.Errorf("%s is an application inference profile; its regions are those of the profile it copies", profileID)
+	case prefix == "":
+		return nil, fmt.Errorf("%s is not a cross-region inference profile ID (want a prefix such as us. or eu.)", profileID)
+	case prefix == "global":
+		return nil, fmt.Errorf("%s is a global profile, which can route to any commercial region", profileID)
+	}
+	return profileRegions[prefix], nil
+}
+
+// runCrossRegionChecks resolves and connects to the Bedrock Runtime endpoint
+// of every region --inference-profile can route to, so an unreachable backing
+// region shows up before requests that land there fail
+func runCrossRegionChecks(ctx context.Context, cfg *Config, _ string) []CheckResult {
+	profileID := cfg.InferenceProfile
+	name := "Cross-Region - " + profileID
+	regions, err := inferenceProfileRegions(profileID)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("Cannot list backing regions: %v", err),
+			Fix:     "Pass a geographic profile ID such as us.anthropic.claude-sonnet-4-20250514-v1:0 to --inference-profile",
+		}}
+	}
+
+	// Backing regions are reached on their public endpoints, never through an override
+	regionalCfg := *cfg
+	regionalCfg.Endpoints = nil
+	regionalCfg.EndpointURL = ""
+
+	results := make([]CheckResult, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			address := net.JoinHostPort(regionalCfg.endpointHost("bedrock-runtime", region), "443")
+			results[i] = probeTCPAddress(ctx, &regionalCfg, name+" / "+region, address)
+		}()
+	}
+	wg.Wait()
+
+	// Bedrock forwards profile requests between regions itself, so only the
+	// source region has to be reachable for requests to succeed
+	var unreachable []string
+	for i, result := range results {
+		if result.Status == "fail" {
+			results[i].Status = "warn"
+			unreachable = append(unreachable, regions[i])
+		}
+	}
+	summary := CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("%s routes to %d regions (%s); all are reachable", profileID, len(regions), strings.Join(regions, ", ")),
+	}
+	if len(unreachable) > 0 {
+		summary.Status = "warn"
+		summary.Message = fmt.Sprintf("%s routes to %d regions; %d unreachable from here: %s", profileID, len(regions), len(unreachable), strings.Join(unreachable, ", "))
+		summary.Fix = "Requests still work through the source region; allow outbound TCP 443 to bedrock-runtime in the other regions only if clients may fail over to them"
+	}
+	return append([]CheckResult{summary}, results...)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInferenceProfileRegions(t *testing.T) {
+	tests := []struct {
+		profileID string
+		want      []string
+		wantErr   bool
+	}{
+		{"us.anthropic.claude-3-5-sonnet-20241022-v2:0", profileRegions["us"], false},
+		{"us-gov.anthropic.claude-3-5-sonnet-20240620-v1:0", []string{"us-gov-east-1", "us-gov-west-1"}, false},
+		{"apac.anthropic.claude-sonnet-4-20250514-v1:0", profileRegions["apac"], false},
+		{"global.anthropic.claude-sonnet-4-20250514-v1:0", nil, true},
+		{"anthropic.claude-3-haiku-20240307-v1:0", nil, true},
+		{"arn:aws:bedrock:us-east-1:123456789012:application-inference-profile/abc", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := inferenceProfileRegions(tt.profileID)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Errorf("inferenceProfileRegions(%q) = %v, %v; want %v (error %v)", tt.profileID, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+// freeSpace returns the bytes available to unprivileged users on the filesystem holding dir
+func freeSpace(dir string) (byteSize, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return byteSize(uint64(stat.Bavail) * uint64(stat.Bsize)), nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Used when /etc/resolv.conf cannot be read, matching the Go resolver's own fallback
+const fallbackNameserver = "127.0.0.1:53"
+
+// systemNameserver returns the first nameserver from /etc/resolv.conf
+func systemNameserver() string {
+	file, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return fallbackNameserver
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			if server, err := normalizeResolver(fields[1]); err == nil {
+				return server
+			}
+		}
+	}
+	return fallbackNameserver
+}
+
+// lookupOverTransport resolves host against server using only the given
+// network ("udp" or "tcp"). An NXDOMAIN answer still proves the transport works.
+func lookupOverTransport(ctx context.Context, host, server, network string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+	start := time.Now()
+	_, err := resolver.LookupHost(ctx, host)
+	logger.Debug("transport lookup finished", "host", host, "server", server, "network", network, "duration", time.Since(start), "error", err)
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil
+	}
+	return err
+}
+
+// runDNSTransportChecks tells apart a resolver that is down from an egress
+// firewall that only lets DNS through over TCP
+func runDNSTransportChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	name := "DNS Transport - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	host := cfg.endpointHost("bedrock-runtime", region)
+	server := cfg.Resolver
+	if server == "" {
+		server = systemNameserver()
+	}
+
+	start := time.Now()
+	udpErr := lookupOverTransport(ctx, host, server, "udp", cfg.Timeout)
+	tcpErr := lookupOverTransport(ctx, host, server, "tcp", cfg.Timeout)
+	durationMs := time.Since(start).Milliseconds()
+
+	switch {
+	case udpErr == nil && tcpErr == nil:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("%s answered over both UDP and TCP", server),
+			DurationMs: durationMs,
+		})
+	case udpErr == nil:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("%s answered over UDP; TCP/53 failed (%v), which only matters for large responses", server, tcpErr),
+			DurationMs: durationMs,
+		})
+	case tcpErr == nil:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("%s answered over TCP only; UDP/53 may be filtered (%v)", server, udpErr),
+			Fix:        "Allow outbound UDP 53 to the resolver; clients that don't fall back to TCP will fail to resolve",
+			DurationMs: durationMs,
+		})
+	default:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("%s did not answer over UDP (%v) or TCP (%v)", server, udpErr, tcpErr),
+			Fix:        "Check that outbound port 53 to the resolver is allowed, or pass --resolver with a reachable DNS server",
+			DurationMs: durationMs,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"time"
+)
+
+// A CNAME onto a VPC endpoint name cached longer than this can outlive the
+// endpoint: after it is deleted or recreated, clients keep resolving the old one
+const maxVPCEndpointTTL = 5 * time.Minute
+
+// DNS wire format (RFC 1035) values for the A queries sent here
+const (
+	dnsTypeA     = 1
+	dnsTypeCNAME = 5
+	dnsClassIN   = 1
+	dnsHeaderLen = 12
+)
+
+var dnsRcodes = map[uint16]string{
+	1: "FORMERR",
+	2: "SERVFAIL",
+	3: "NXDOMAIN",
+	4: "NOTIMP",
+	5: "REFUSED",
+}
+
+// dnsRecord is one answer record: an A record's address or a CNAME's target
+type dnsRecord struct {
+	Name  string
+	Type  uint16
+	TTL   time.Duration
+	Value string
+}
+
+func (r dnsRecord) String() string {
+	kind := "A"
+	if r.Type == dnsTypeCNAME {
+		kind = "CNAME"
+	}
+	return fmt.Sprintf("%s %s (TTL %s)", kind, r.Value, r.TTL)
+}
+
+// buildDNSQuery encodes a recursive A query for host
+func buildDNSQuery(id uint16, host string) ([]byte, error) {
+	query := binary.BigEndian.AppendUint16(nil, id)
+	// Recursion desired, one question
+	query = append(query, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0)
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if label == "" || len(label) > 63 {
+			return nil, fmt.Errorf("%q is not a valid hostname", host)
+		}
+		query = append(append(query, byte(len(label))), label...)
+	}
+	query = append(query, 0)
+	query = binary.BigEndian.AppendUint16(query, dnsTypeA)
+	return binary.BigEndian.AppendUint16(query, dnsClassIN), nil
+}
+
+// parseDNSAnswer returns the A and CNAME records in a response to query id.
+// truncated is set when the server wants the query repeated over TCP.
+func parseDNSAnswer(msg []byte, id uint16) (records []dnsRecord, truncated bool, err error) {
+	if len(msg) < dnsHeaderLen {
+		return nil, false, errors.New("DNS response is too short")
+	}
+	if binary.BigEndian.Uint16(msg) != id {
+		return nil, false, errors.New("DNS response does not match the query")
+	}
+	flags := binary.BigEndian.Uint16(msg[2:])
+	if flags&0x0200 != 0 {
+		return nil, true, nil
+	}
+	if rcode := flags & 0x000f; rcode != 0 {
+		name, ok := dnsRcodes[rcode]
+		if !ok {
+			name = fmt.Sprintf("rcode %d", rcode)
+		}
+		return nil, false, fmt.Errorf("server answered %s", name)
+	}
+	questions := binary.BigEndian.Uint16(msg[4:])
+	answers := binary.BigEndian.Uint16(msg[6:])
+
+	offset := dnsHeaderLen
+	for range questions {
+		if _, offset, err = readDNSName(msg, offset); err != nil {
+			return nil, false, err
+		}
+		offset += 4
+	}
+	for range answers {
+		var record dnsRecord
+		if record.Name, offset, err = readDNSName(msg, offset); err != nil {
+			return nil, false, err
+		}
+		if offset+10 > len(msg) {
+			return nil, false, errors.New("DNS response is truncated")
+		}
+		record.Type = binary.BigEndian.Uint16(msg[offset:])
+		record.TTL = time.Duration(binary.BigEndian.Uint32(msg[offset+4:])) * time.Second
+		length := int(binary.BigEndian.Uint16(msg[offset+8:]))
+		offset += 10
+		if offset+length > len(msg) {
+			return nil, false, errors.New("DNS response is truncated")
+		}
+		switch record.Type {
+		case dnsTypeA:
+			if length != net.IPv4len {
+				return nil, false, errors.New("DNS response has a malformed A record")
+			}
+			record.Value = net.IP(msg[offset : offset+length]).String()
+			records = append(records, record)
+		case dnsTypeCNAME:
+			if record.Value, _, err = readDNSName(msg, offset); err != nil {
+				return nil, false, err
+			}
+			records = append(records, record)
+		}
+		offset += length
+	}
+	return records, false, nil
+}
+
+// readDNSName decodes the possibly compressed name at offset and returns the
+// offset just past it
+func readDNSName(msg []byte, offset int) (string, int, error) {
+	var labels []string
+	next := -1
+	for jumps := 0; ; {
+		if offset >= len(msg) {
+			return "", 0, errors.New("DNS response is truncated")
+		}
+		length := int(msg[offset])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, "."), next, nil
+		case length&0xc0 == 0xc0:
+			if offset+1 >= len(msg) || jumps > 10 {
+				return "", 0, errors.New("DNS response has a malformed name")
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(msg[offset:]) & 0x3fff)
+			jumps++
+		default:
+			if offset+1+length > len(msg) {
+				return "", 0, errors.New("DNS response is truncated")
+			}
+			labels = append(labels, string(msg[offset+1:offset+1+length]))
+			offset += 1 + length
+		}
+	}
+}
+
+// queryDNSRecords asks server for host's A records over UDP, repeating the
+// query over TCP when the answer is truncated. net.Resolver drops TTLs, so
+// this speaks the wire format itself.
+func queryDNSRecords(ctx context.Context, host, server string, timeout time.Duration) ([]dnsRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	id := uint16(rand.Uint32())
+	query, err := buildDNSQuery(id, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, network := range []string{"udp", "tcp"} {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, network, server)
+		if err != nil {
+			return nil, err
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		msg, err := exchangeDNS(conn, network, query)
+		conn.Close()
+		if err != nil {
+			return nil, err
+		}
+		records, truncated, err := parseDNSAnswer(msg, id)
+		logger.Debug("DNS TTL query finished", "host", host, "server", server, "network", network, "records", len(records), "truncated", truncated, "error", err)
+		if !truncated {
+			return records, err
+		}
+	}
+	return nil, errors.New("DNS response was truncated over TCP")
+}
+
+// exchangeDNS sends query on conn; over TCP each message has a length prefix
+func exchangeDNS(conn net.Conn, network string, query []byte) ([]byte, error) {
+	if network == "udp" {
+		if _, err := conn.Write(query); err != nil {
+			return nil, err
+		}
+		msg := make([]byte, 1232)
+		n, err := conn.Read(msg)
+		if err != nil {
+			return nil, err
+		}
+		return msg[:n], nil
+	}
+	if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(query))), query...)); err != nil {
+		return nil, err
+	}
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// isVPCEndpointName reports whether host is an interface endpoint's own DNS name
+func isVPCEndpointName(host string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(host, "."), ".vpce.amazonaws.com")
+}
+
+// runDNSTTLChecks reports the TTLs the resolver serves for the Bedrock
+// Runtime hostname. The answer comes from the resolver's cache when it has
+// one, so a TTL lower than the zone's means it is counting down.
+func runDNSTTLChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	name := "DNS TTL - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	host := cfg.endpointHost("bedrock-runtime", region)
+	server := cfg.Resolver
+	if server == "" {
+		server = systemNameserver()
+	}
+
+	start := time.Now()
+	records, err := queryDNSRecords(ctx, host, server, cfg.Timeout)
+	result := dnsTTLResult(name, host, server, records, err)
+	result.DurationMs = time.Since(start).Milliseconds()
+	return []CheckResult{result}
+}
+
+func dnsTTLResult(name, host, server string, records []dnsRecord, err error) CheckResult {
+	if err != nil {
+		return CheckResult{
+			Name:      name,
+			Status:    "warn",
+			Message:   fmt.Sprintf("Could not read the TTL of %s from %s: %v", host, server, err),
+			Fix:       "Run the DNS check to see whether the hostname resolves at all, or pass --resolver with a reachable DNS server",
+			ErrorKind: ErrorKindDNS,
+		}
+	}
+	if len(records) == 0 {
+		return CheckResult{
+			Name:      name,
+			Status:    "warn",
+			Message:   fmt.Sprintf("%s returned no A or CNAME records for %s", server, host),
+			Fix:       "Run the DNS check to see whether the hostname resolves at all",
+			ErrorKind: ErrorKindDNS,
+		}
+	}
+
+	var answers []string
+	for _, record := range records {
+		answers = append(answers, record.String())
+	}
+	summary := fmt.Sprintf("%s from %s: %s", host, server, strings.Join(answers, ", "))
+	for _, record := range records {
+		if record.Type == dnsTypeCNAME && isVPCEndpointName(record.Value) && record.TTL > maxVPCEndpointTTL {
+			return CheckResult{
+				Name:    name,
+				Status:  "warn",
+				Message: fmt.Sprintf("%s; the CNAME onto VPC endpoint %s is cached for %s, so clients can keep using a deleted or replaced endpoint that long", summary, record.Value, record.TTL),
+				Fix:     fmt.Sprintf("Lower the TTL of the %s record to %s or less in its private hosted zone, or enable private DNS on the endpoint instead of a CNAME", record.Name, maxVPCEndpointTTL),
+			}
+		}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: summary,
+	}
+}
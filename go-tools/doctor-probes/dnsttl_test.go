@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dnsResponse answers query with a CNAME onto target and an A record for it,
+// pointing back at the question's name the way servers compress it
+func dnsResponse(query []byte, target string, cnameTTL, aTTL uint32) []byte {
+	msg := append([]byte{}, query[:2]...)
+	msg = append(msg, 0x81, 0x80, 0, 1, 0, 2, 0, 0, 0, 0)
+	msg = append(msg, query[dnsHeaderLen:]...)
+
+	var rdata []byte
+	for _, label := range strings.Split(target, ".") {
+		rdata = append(append(rdata, byte(len(label))), label...)
+	}
+	rdata = append(rdata, 0)
+	targetOffset := len(msg) + 12
+	msg = append(msg, 0xc0, dnsHeaderLen, 0, dnsTypeCNAME, 0, dnsClassIN)
+	msg = binary.BigEndian.AppendUint32(msg, cnameTTL)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(rdata)))
+	msg = append(msg, rdata...)
+
+	msg = append(msg, 0xc0|byte(targetOffset>>8), byte(targetOffset), 0, dnsTypeA, 0, dnsClassIN)
+	msg = binary.BigEndian.AppendUint32(msg, aTTL)
+	return append(msg, 0, 4, 10, 0, 1, 15)
+}
+
+func TestParseDNSAnswer(t *testing.T) {
+	query, err := buildDNSQuery(0x1234, "bedrock-runtime.us-east-1.amazonaws.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, truncated, err := parseDNSAnswer(dnsResponse(query, "vpce-0abc.bedrock-runtime.us-east-1.vpce.amazonaws.com", 3600, 60), 0x1234)
+	if err != nil || truncated {
+		t.Fatalf("parseDNSAnswer() = %v, truncated %v", err, truncated)
+	}
+	want := []dnsRecord{
+		{"bedrock-runtime.us-east-1.amazonaws.com", dnsTypeCNAME, time.Hour, "vpce-0abc.bedrock-runtime.us-east-1.vpce.amazonaws.com"},
+		{"vpce-0abc.bedrock-runtime.us-east-1.vpce.amazonaws.com", dnsTypeA, time.Minute, "10.0.1.15"},
+	}
+	if len(records) != len(want) || records[0] != want[0] || records[1] != want[1] {
+		t.Errorf("parseDNSAnswer() = %+v, want %+v", records, want)
+	}
+
+	if _, _, err := parseDNSAnswer(dnsResponse(query, "x", 1, 1), 0x9999); err == nil {
+		t.Error("parseDNSAnswer() accepted a response to a different query")
+	}
+	nxdomain := append([]byte{0x12, 0x34, 0x81, 0x83}, query[4:]...)
+	if _, _, err := parseDNSAnswer(nxdomain, 0x1234); err == nil || !strings.Contains(err.Error(), "NXDOMAIN") {
+		t.Errorf("parseDNSAnswer(NXDOMAIN) = %v, want an NXDOMAIN error", err)
+	}
+	if _, _, err := parseDNSAnswer(dnsResponse(query, "x", 1, 1)[:40], 0x1234); err == nil {
+		t.Error("parseDNSAnswer() accepted a cut-off response")
+	}
+}
+
+func TestQueryDNSRecordsFallsBackToTCP(t *testing.T) {
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udp.Close()
+	tcp, err := net.Listen("tcp", udp.LocalAddr().String())
+	if err != nil {
+		t.Skipf("TCP port %s is taken: %v", udp.LocalAddr(), err)
+	}
+	defer tcp.Close()
+
+	// UDP only ever answers with the truncated bit set
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := udp.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			reply := append([]byte{}, buf[:n]...)
+			reply[2] |= 0x82
+			udp.WriteTo(reply, addr)
+		}
+	}()
+	go func() {
+		conn, err := tcp.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var length [2]byte
+		io.ReadFull(conn, length[:])
+		query := make([]byte, binary.BigEndian.Uint16(length[:]))
+		io.ReadFull(conn, query)
+		reply := dnsResponse(query, "vpce-0abc.bedrock-runtime.us-east-1.vpce.amazonaws.com", 300, 60)
+		conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(reply))), reply...))
+	}()
+
+	records, err := queryDNSRecords(context.Background(), "bedrock-runtime.us-east-1.amazonaws.com", udp.LocalAddr().String(), 5*time.Second)
+	if err != nil || len(records) != 2 || records[1].Value != "10.0.1.15" {
+		t.Errorf("queryDNSRecords() = %+v, %v, want the TCP answer", records, err)
+	}
+}
+
+func TestDNSTTLResult(t *testing.T) {
+	host := "bedrock-runtime.us-east-1.amazonaws.com"
+	cname := func(ttl time.Duration) []dnsRecord {
+		return []dnsRecord{
+			{host, dnsTypeCNAME, ttl, "vpce-0abc.bedrock-runtime.us-east-1.vpce.amazonaws.com"},
+			{"vpce-0abc.bedrock-runtime.us-east-1.vpce.amazonaws.com", dnsTypeA, time.Minute, "10.0.1.15"},
+		}
+	}
+	tests := []struct {
+		name    string
+		records []dnsRecord
+		status  string
+		message string
+	}{
+		{"public", []dnsRecord{{host, dnsTypeA, 5 * time.Second, "52.94.1.10"}}, "pass", "A 52.94.1.10 (TTL 5s)"},
+		{"short CNAME", cname(time.Minute), "pass", "CNAME vpce-0abc"},
+		{"long CNAME", cname(24 * time.Hour), "warn", "cached for 24h0m0s"},
+		{"empty", nil, "warn", "no A or CNAME records"},
+	}
+	for _, tt := range tests {
+		got := dnsTTLResult("DNS TTL - Bedrock Runtime", host, "10.0.0.2:53", tt.records, nil)
+		if got.Status != tt.status || !strings.Contains(got.Message, tt.message) {
+			t.Errorf("%s: got %s %q, want %s containing %q", tt.name, got.Status, got.Message, tt.status, tt.message)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Shown in place of a region that would only be resolved from a profile or IMDS at run time
+const implicitRegion = "<region>"
+
+// writeDryRun prints the checks a run would execute without touching the
+// network. A region that would come from the environment is shown; lookups
+// that need the shared config or instance metadata are deferred.
+func writeDryRun(w io.Writer, cfg *Config, regionSource string) error {
+	plan := *cfg
+	if plan.RegionFromInstance {
+		plan.Regions, regionSource = []string{implicitRegion}, "resolved at run time from EC2 instance metadata"
+	} else if len(plan.Regions) == 0 {
+		regionSource = "resolved at run time from " + strings.Join(regionSources(), ", ")
+		plan.Regions = []string{implicitRegion}
+		for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+			if region := os.Getenv(name); region != "" {
+				plan.Regions, regionSource = []string{region}, name
+				break
+			}
+		}
+	}
+
+	for _, region := range plan.Regions {
+		if region != implicitRegion && !validRegion(region) {
+			return fmt.Errorf("%q from %s is not a valid AWS region (did you mean %s?)", region, regionSource, suggestRegion(region))
+		}
+	}
+
+	fmt.Fprintln(w, "Dry run: no probes were executed")
+	fmt.Fprintf(w, "Regions: %s (%s)\n", strings.Join(plan.Regions, ", "), regionSource)
+	fmt.Fprintf(w, "Timeout: %s\n", plan.Timeout)
+	fmt.Fprintf(w, "Services: %s\n", strings.Join(plan.services(), ", "))
+	if plan.ConnectTimeout > 0 {
+		fmt.Fprintf(w, "Connect timeout: %s\n", plan.ConnectTimeout)
+	}
+	if plan.Samples > 1 {
+		fmt.Fprintf(w, "Latency samples: %d per probe\n", plan.Samples)
+	}
+	if plan.SOCKS5 != "" {
+		fmt.Fprintf(w, "Probes connect%s\n", plan.viaProxy())
+	}
+	if plan.Deadline > 0 {
+		fmt.Fprintf(w, "Deadline: %s\n", plan.Deadline)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REGION\tCHECK\tNAME\tTIMEOUT")
+	for _, job := range buildJobs(&plan) {
+		region := job.region
+		if check, _ := lookupCheck(job.check); check.Scope == ScopeGlobal {
+			region = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", region, job.check, checkLabels[job.check], plan.timeoutFor(job.check))
+	}
+	return tw.Flush()
+}
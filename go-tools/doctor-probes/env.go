@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runEnvChecks verifies each --require variable is non-empty. Values are never
+// echoed since these are often tokens.
+func runEnvChecks(cfg *Config) []CheckResult {
+	var results []CheckResult
+
+	for _, name := range cfg.Require {
+		value, ok := os.LookupEnv(name)
+		switch {
+		case !ok:
+			results = append(results, CheckResult{
+				Name:    "Env - " + name,
+				Status:  "fail",
+				Message: fmt.Sprintf("%s is not set", name),
+				Fix:     fmt.Sprintf("export %s=...", name),
+			})
+		case value == "":
+			results = append(results, CheckResult{
+				Name:    "Env - " + name,
+				Status:  "fail",
+				Message: fmt.Sprintf("%s is set but empty", name),
+				Fix:     fmt.Sprintf("export %s=...", name),
+			})
+		default:
+			results = append(results, CheckResult{
+				Name:    "Env - " + name,
+				Status:  "pass",
+				Message: fmt.Sprintf("%s is set", name),
+			})
+		}
+	}
+
+	return results
+}
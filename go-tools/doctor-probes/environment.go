@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Environment describes where the probes ran, so a pasted report answers the
+// usual first questions. Detection uses only local files and variables.
+type Environment struct {
+	OS        string `json:"os" yaml:"os"`
+	Arch      string `json:"arch" yaml:"arch"`
+	Hostname  string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Container bool   `json:"container" yaml:"container"`
+	// AWS compute service: ec2, ecs, eks or lambda; empty elsewhere
+	Platform string `json:"platform,omitempty" yaml:"platform,omitempty"`
+}
+
+// currentEnvironment is detected once per process
+var currentEnvironment = sync.OnceValue(func() *Environment { return detectEnvironment("/") })
+
+// detectEnvironment inspects the host rooted at root
+func detectEnvironment(root string) *Environment {
+	hostname, _ := os.Hostname()
+	container := inContainer(root)
+	return &Environment{
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Hostname:  hostname,
+		Container: container,
+		Platform:  awsPlatform(root, container),
+	}
+}
+
+// inContainer checks the marker files Docker and Podman create, then PID 1's cgroups
+func inContainer(root string) bool {
+	for _, marker := range []string{".dockerenv", "run/.containerenv"} {
+		if fileExists(filepath.Join(root, marker)) {
+			return true
+		}
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+	cgroups, err := os.ReadFile(filepath.Join(root, "proc/1/cgroup"))
+	if err != nil {
+		return false
+	}
+	for _, name := range []string{"docker", "kubepods", "containerd", "libpod", "lxc", "ecs"} {
+		if strings.Contains(string(cgroups), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// awsPlatform names the AWS compute service from the variables each one sets,
+// falling back to the DMI vendor EC2 instances report
+func awsPlatform(root string, container bool) string {
+	switch {
+	case os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "":
+		return "lambda"
+	case os.Getenv("ECS_CONTAINER_METADATA_URI_V4") != "", os.Getenv("ECS_CONTAINER_METADATA_URI") != "",
+		strings.HasPrefix(os.Getenv("AWS_EXECUTION_ENV"), "AWS_ECS"):
+		return "ecs"
+	}
+	if !onEC2Hardware(root) {
+		return ""
+	}
+	if container && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return "eks"
+	}
+	return "ec2"
+}
+
+func onEC2Hardware(root string) bool {
+	vendor, err := os.ReadFile(filepath.Join(root, "sys/devices/virtual/dmi/id/sys_vendor"))
+	if err == nil && strings.TrimSpace(string(vendor)) == "Amazon EC2" {
+		return true
+	}
+	// Older Xen-based instance types only expose the hypervisor UUID
+	uuid, err := os.ReadFile(filepath.Join(root, "sys/hypervisor/uuid"))
+	return err == nil && strings.HasPrefix(strings.ToLower(string(uuid)), "ec2")
+}
+
+// environmentLine summarizes env for the human report
+func environmentLine(env *Environment, msgs messages) string {
+	line := msgs.text("report.environment", env.OS, env.Arch)
+	if env.Hostname != "" {
+		line += msgs.text("report.environment_host", env.Hostname)
+	}
+	var details []string
+	if env.Container {
+		details = append(details, msgs.text("report.container"))
+	}
+	if env.Platform != "" {
+		details = append(details, strings.ToUpper(env.Platform))
+	}
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	return line
+}
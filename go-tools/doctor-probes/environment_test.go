@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectEnvironment(t *testing.T) {
+	for _, name := range []string{"AWS_LAMBDA_FUNCTION_NAME", "ECS_CONTAINER_METADATA_URI_V4", "ECS_CONTAINER_METADATA_URI", "AWS_EXECUTION_ENV", "KUBERNETES_SERVICE_HOST"} {
+		t.Setenv(name, "")
+	}
+	write := func(root, path, content string) {
+		t.Helper()
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	laptop := t.TempDir()
+	write(laptop, "proc/1/cgroup", "0::/init.scope\n")
+	if env := detectEnvironment(laptop); env.Container || env.Platform != "" || env.OS == "" || env.Arch == "" {
+		t.Errorf("laptop: detectEnvironment() = %+v, want no container or platform", env)
+	}
+
+	docker := t.TempDir()
+	write(docker, ".dockerenv", "")
+	write(docker, "sys/devices/virtual/dmi/id/sys_vendor", "Amazon EC2\n")
+	if env := detectEnvironment(docker); !env.Container || env.Platform != "ec2" {
+		t.Errorf("docker on EC2: detectEnvironment() = %+v, want container on ec2", env)
+	}
+
+	pod := t.TempDir()
+	write(pod, "proc/1/cgroup", "0::/kubepods/besteffort/pod123\n")
+	write(pod, "sys/hypervisor/uuid", "ec2e1916-9099-7caf-fd21-012345abcdef\n")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.100.0.1")
+	if env := detectEnvironment(pod); !env.Container || env.Platform != "eks" {
+		t.Errorf("pod: detectEnvironment() = %+v, want container on eks", env)
+	}
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("AWS_EXECUTION_ENV", "AWS_ECS_FARGATE")
+	if env := detectEnvironment(laptop); env.Platform != "ecs" {
+		t.Errorf("fargate: detectEnvironment() = %+v, want ecs", env)
+	}
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "bcce-preflight")
+	if env := detectEnvironment(laptop); env.Platform != "lambda" {
+		t.Errorf("lambda: detectEnvironment() = %+v, want lambda", env)
+	}
+}
+
+func TestEnvironmentLine(t *testing.T) {
+	env := &Environment{OS: "linux", Arch: "arm64", Hostname: "build-7", Container: true, Platform: "ecs"}
+	if got, want := environmentLine(env, nil), "Environment: linux/arm64 on build-7 (container, ECS)"; got != want {
+		t.Errorf("environmentLine() = %q, want %q", got, want)
+	}
+	if got, want := environmentLine(&Environment{OS: "darwin", Arch: "arm64"}, nil), "Environment: darwin/arm64"; got != want {
+		t.Errorf("environmentLine() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// fixRule returns the fix the config's fixes map gives a result that did not
+// pass. Keys are an error code such as "BCCE-TCP-002", a check category such
+// as "tcp", or a glob matched against the result name without its region
+// prefix, tried in that order; globs are tried in sorted order, as for
+// severity.
+func (c *Config) fixRule(result CheckResult) (string, bool) {
+	if result.Code != "" {
+		if fix, ok := c.Fixes[result.Code]; ok {
+			return fix, true
+		}
+	}
+	if fix, ok := c.Fixes[result.check]; ok {
+		return fix, true
+	}
+	name := strings.ToLower(strings.TrimPrefix(result.Name, result.Region+" / "))
+	patterns := make([]string, 0, len(c.Fixes))
+	for pattern := range c.Fixes {
+		patterns = append(patterns, pattern)
+	}
+	slices.Sort(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return c.Fixes[pattern], true
+		}
+	}
+	return "", false
+}
+
+// applyFixOverride replaces a non-passing result's fix with the config's,
+// keeping the computed one in DefaultFix for the JSON report
+func (c *Config) applyFixOverride(result *CheckResult) {
+	if result.Status == "pass" {
+		return
+	}
+	fix, ok := c.fixRule(*result)
+	if !ok || fix == result.Fix {
+		return
+	}
+	logger.Debug("replacing fix from config", "check", result.Name, "default_fix", result.Fix)
+	result.DefaultFix = result.Fix
+	result.Fix = fix
+}
+
+// validFixOverride checks a fixes map entry from the config file
+func validFixOverride(pattern, fix string) error {
+	if strings.TrimSpace(fix) == "" {
+		return fmt.Errorf("fix for %q must not be empty", pattern)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("fix pattern %q: %v", pattern, err)
+	}
+	return nil
+}
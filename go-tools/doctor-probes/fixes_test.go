@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestApplyFixOverride(t *testing.T) {
+	cfg := newConfig()
+	cfg.Fixes = map[string]string{
+		"BCCE-TCP-002":         "File a NetOps ticket for outbound 443",
+		"tcp":                  "Ask NetOps about the firewall",
+		"dns - bedrock agent*": "Check the internal resolver",
+	}
+	tests := []struct {
+		result  CheckResult
+		wantFix string
+	}{
+		{CheckResult{Name: "us-east-1 / TCP - Bedrock Runtime", Region: "us-east-1", Status: "fail", Code: "BCCE-TCP-002", Fix: "Check your firewall", check: "tcp"}, "File a NetOps ticket for outbound 443"},
+		{CheckResult{Name: "TCP - Bedrock Runtime", Status: "fail", Code: "BCCE-TCP-003", Fix: "Check your firewall", check: "tcp"}, "Ask NetOps about the firewall"},
+		{CheckResult{Name: "us-east-1 / DNS - Bedrock Agents", Region: "us-east-1", Status: "warn", Fix: "Check DNS", check: "dns"}, "Check the internal resolver"},
+		{CheckResult{Name: "DNS - Bedrock Runtime", Status: "fail", Fix: "Check DNS", check: "dns"}, "Check DNS"},
+		{CheckResult{Name: "TCP - Bedrock Runtime", Status: "pass", check: "tcp"}, ""},
+	}
+	for _, tt := range tests {
+		result := tt.result
+		cfg.applyFixOverride(&result)
+		if result.Fix != tt.wantFix {
+			t.Errorf("%s fix = %q, want %q", tt.result.Name, result.Fix, tt.wantFix)
+		}
+		wantDefault := ""
+		if tt.wantFix != tt.result.Fix {
+			wantDefault = tt.result.Fix
+		}
+		if result.DefaultFix != wantDefault {
+			t.Errorf("%s default fix = %q, want %q", tt.result.Name, result.DefaultFix, wantDefault)
+		}
+	}
+}
+
+func TestValidFixOverride(t *testing.T) {
+	if err := validFixOverride("BCCE-TCP-002", "File a ticket"); err != nil {
+		t.Errorf("validFixOverride() = %v", err)
+	}
+	if err := validFixOverride("tcp", " "); err == nil {
+		t.Error("validFixOverride accepted an empty fix")
+	}
+	if err := validFixOverride("dns [", "File a ticket"); err == nil {
+		t.Error("validFixOverride accepted a malformed glob")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// A single-quoted span in a Fix, which is how fixes name a command to run;
+// the boundaries keep apostrophes in words like "don't" from pairing up
+var quotedCommandPattern = regexp.MustCompile(`(?:^|[\s(])'([^']+)'(?:$|[\s,.;:)])`)
+
+// A command starts with a program name, not a flag, path placeholder or key
+var commandWordPattern = regexp.MustCompile(`^[a-z][a-z0-9._-]*$`)
+
+// fixCommand returns the shell command a fix asks for, when it names exactly
+// one. Fixes that offer alternatives, need a value filled in, or would pass
+// shell syntax from a hostname or path into the script stay comments.
+func fixCommand(fix string) (string, bool) {
+	matches := quotedCommandPattern.FindAllStringSubmatch(fix, -1)
+	if len(matches) != 1 {
+		return "", false
+	}
+	command := matches[0][1]
+	word, _, _ := strings.Cut(command, " ")
+	if !commandWordPattern.MatchString(word) || !strings.Contains(command, " ") {
+		return "", false
+	}
+	if strings.ContainsAny(command, "$`;&|<>()\"\\\n") || strings.Contains(command, "...") {
+		return "", false
+	}
+	return command, true
+}
+
+// fixScriptRenderer writes the fixes of every non-passing check as a shell
+// script to review and run by hand. Fixes are printed as comments; only a
+// fix that names a single command adds an executable line after them.
+type fixScriptRenderer struct{}
+
+func (fixScriptRenderer) Render(output ProbeOutput, w io.Writer) error {
+	// Checks repeated per region usually share a fix, which only needs running once
+	type step struct {
+		checks []string
+		fix    string
+	}
+	var steps []*step
+	index := map[string]*step{}
+	count := 0
+	for _, result := range output.Checks {
+		if result.Status == "pass" || result.Fix == "" {
+			continue
+		}
+		count++
+		label := fmt.Sprintf("[%s] %s", strings.ToUpper(result.Status), result.Name)
+		if s, ok := index[result.Fix]; ok {
+			s.checks = append(s.checks, label)
+			continue
+		}
+		s := &step{checks: []string{label}, fix: result.Fix}
+		index[result.Fix] = s
+		steps = append(steps, s)
+	}
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	if len(steps) == 0 {
+		b.WriteString("# BCCE doctor probes found nothing to fix\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	fmt.Fprintf(&b, "# Fixes from BCCE doctor probes for %d failing or warning checks.\n", count)
+	b.WriteString("# Review before running: fixes that need judgment are comments, and the\n")
+	b.WriteString("# commands below them are only what each fix suggests.\n")
+	for _, s := range steps {
+		b.WriteString("\n")
+		for _, check := range s.checks {
+			fmt.Fprintf(&b, "# %s\n", check)
+		}
+		for _, line := range strings.Split(s.fix, "\n") {
+			fmt.Fprintf(&b, "# %s\n", line)
+		}
+		if command, ok := fixCommand(s.fix); ok {
+			b.WriteString(command + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestFixCommand(t *testing.T) {
+	tests := []struct {
+		fix, want string
+	}{
+		{"Create it with 'mkdir -p /srv/bcce', or point --workdir at the configured output directory", "mkdir -p /srv/bcce"},
+		{"Renew it with 'aws sso login --profile dev'", "aws sso login --profile dev"},
+		// Alternatives need a person to choose
+		{"Run 'aws configure' or 'aws sso login', or export AWS_PROFILE", ""},
+		{"Check internet connectivity and DNS settings", ""},
+		// Apostrophes are not quotes
+		{"If those aren't the credentials you meant, remove the ones you don't use", ""},
+		// A lone word is a name, not a command
+		{"Run 'chown' on it", ""},
+		{"Run 'aws ec2 describe-vpc-endpoints --filters Name=vpc-id,Values=<vpc-id>'", ""},
+		{"Run 'mkdir -p /tmp/$(reboot)'", ""},
+		{"Pass '--region us-east-1' to the CLI", ""},
+	}
+	for _, tt := range tests {
+		got, ok := fixCommand(tt.fix)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("fixCommand(%q) = %q, %v; want %q", tt.fix, got, ok, tt.want)
+		}
+	}
+}
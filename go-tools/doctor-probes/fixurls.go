@@ -0,0 +1,44 @@
+package main
+
+// failureReason identifies a known failure that has a documentation page
+type failureReason int
+
+const (
+	reasonNone failureReason = iota
+	reasonModelAccess
+	reasonInferenceProfile
+	reasonQuota
+	reasonVPCEndpoint
+	reasonIMDSv2
+	reasonCredentials
+	reasonRegion
+	reasonProxy
+	reasonClockSkew
+	reasonCABundle
+	reasonOutsideAWSRanges
+	reasonVPCUnknown
+)
+
+// Documentation for each failure reason, shown with --include-fix-urls. These
+// are the only AWS docs links in the tool, so audit them here
+var fixURLs = map[failureReason]string{
+	reasonModelAccess:      "https://docs.aws.amazon.com/bedrock/latest/userguide/model-access.html",
+	reasonInferenceProfile: "https://docs.aws.amazon.com/bedrock/latest/userguide/cross-region-inference.html",
+	reasonQuota:            "https://docs.aws.amazon.com/bedrock/latest/userguide/quotas.html",
+	reasonVPCEndpoint:      "https://docs.aws.amazon.com/bedrock/latest/userguide/vpc-interface-endpoints.html",
+	reasonIMDSv2:           "https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/configuring-instance-metadata-service.html",
+	reasonCredentials:      "https://docs.aws.amazon.com/sdkref/latest/guide/standardized-credentials.html",
+	reasonRegion:           "https://docs.aws.amazon.com/general/latest/gr/bedrock.html",
+	reasonProxy:            "https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-proxy.html",
+	reasonClockSkew:        "https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/set-time.html",
+	reasonCABundle:         "https://docs.aws.amazon.com/sdkref/latest/guide/feature-gen-config.html",
+	reasonOutsideAWSRanges: "https://docs.aws.amazon.com/vpc/latest/userguide/aws-ip-ranges.html",
+	reasonVPCUnknown:       "https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/configuring-instance-metadata-service.html",
+}
+
+// addFixURL attaches the documentation link for a non-passing result's failure reason
+func addFixURL(result *CheckResult) {
+	if result.Status != "pass" && result.FixURL == "" {
+		result.FixURL = fixURLs[result.reason]
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFixURLsCoverEveryReason(t *testing.T) {
+	for reason := reasonNone + 1; reason <= reasonVPCUnknown; reason++ {
+		if url := fixURLs[reason]; !strings.HasPrefix(url, "https://docs.aws.amazon.com/") {
+			t.Errorf("fixURLs[%d] = %q, want an AWS docs URL", reason, url)
+		}
+	}
+	if len(fixURLs) != int(reasonVPCUnknown) {
+		t.Errorf("fixURLs has %d entries, want one per reason (%d)", len(fixURLs), reasonVPCUnknown)
+	}
+}
+
+func TestAddFixURL(t *testing.T) {
+	tests := []struct {
+		result CheckResult
+		want   string
+	}{
+		{CheckResult{Status: "fail", reason: reasonModelAccess}, fixURLs[reasonModelAccess]},
+		{CheckResult{Status: "warn", reason: reasonQuota}, fixURLs[reasonQuota]},
+		{CheckResult{Status: "pass", reason: reasonQuota}, ""},
+		{CheckResult{Status: "fail"}, ""},
+		{CheckResult{Status: "fail", FixURL: "https://plugin.example/doc", reason: reasonRegion}, "https://plugin.example/doc"},
+	}
+	for _, tt := range tests {
+		addFixURL(&tt.result)
+		if tt.result.FixURL != tt.want {
+			t.Errorf("addFixURL(%s, reason %d) set %q, want %q", tt.result.Status, tt.result.reason, tt.result.FixURL, tt.want)
+		}
+	}
+}
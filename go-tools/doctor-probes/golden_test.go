@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite testdata/golden from the current output")
+
+// fakeCheck is a registry entry whose results are fixed; delay staggers when
+// it completes so completion order differs from registry order
+func fakeCheck(category, group string, scope RegionScope, delay time.Duration, results ...CheckResult) Check {
+	return Check{
+		Category: category,
+		Group:    group,
+		Name:     category,
+		Scope:    scope,
+		Run: func(context.Context, *Config, string) []CheckResult {
+			time.Sleep(delay)
+			return slices.Clone(results)
+		},
+	}
+}
+
+// withChecks replaces the check registry with checks for the rest of the test
+func withChecks(t *testing.T, checks ...Check) {
+	t.Helper()
+	savedRegistry, savedGlobal := checkRegistry, globalChecks
+	t.Cleanup(func() { checkRegistry, globalChecks = savedRegistry, savedGlobal })
+	checkRegistry = checks
+	globalChecks = registryCategories(func(check Check) bool { return check.Scope == ScopeGlobal })
+}
+
+// renderPipeline runs the checks in cfg and renders them in format, with the
+// fields that vary between runs pinned
+func renderPipeline(t *testing.T, cfg *Config, format string) []byte {
+	t.Helper()
+	results := runChecks(context.Background(), cfg, true, nil)
+	output := newProbeOutput(results, cfg.Regions)
+	output.Summary.GeneratedAt = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	output.Summary.Version, output.Summary.Commit = "test", ""
+	output.Environment = &Environment{OS: "linux", Arch: "amd64", Hostname: "probe-host"}
+
+	renderer, err := newRenderer(format, renderOptions{Plain: true, Pretty: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := renderer.Render(output, &buf); err != nil {
+		t.Fatalf("Render(%s) error = %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+// assertGolden compares got with testdata/golden/name, rewriting it with -update
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -run %s -update to create it)", err, t.Name())
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test -run %s -update to accept it)\ngot:\n%s\nwant:\n%s", path, t.Name(), got, want)
+	}
+}
+
+func TestGoldenOutput(t *testing.T) {
+	withChecks(t,
+		fakeCheck("slow", "network", ScopeRegional, 30*time.Millisecond,
+			CheckResult{Name: "Latency - Bedrock Runtime", Status: "warn", Message: "Slow handshake", Fix: "Check the proxy", DurationMs: 900},
+		),
+		fakeCheck("name", "network", ScopeRegional, 0,
+			CheckResult{Name: "DNS - Bedrock Runtime", Status: "pass", Message: "Resolved", DurationMs: 12, Addresses: []string{"10.0.0.5"}},
+		),
+		fakeCheck("auth", "auth", ScopeGlobal, 10*time.Millisecond,
+			CheckResult{Name: "Credentials - STS", Status: "fail", Message: "Expired token", Fix: "Refresh credentials", ErrorKind: ErrorKindAuth, DurationMs: 45},
+		),
+	)
+	cfg := newConfig()
+	cfg.Regions = []string{"us-west-2", "us-east-1"}
+	cfg.Checks = []string{"slow", "name", "auth"}
+
+	for _, format := range outputFormats {
+		t.Run(format, func(t *testing.T) {
+			first := renderPipeline(t, cfg, format)
+			// Concurrency must not change a single byte between runs
+			for range 3 {
+				if again := renderPipeline(t, cfg, format); !bytes.Equal(first, again) {
+					t.Fatalf("%s output changed between runs:\n%s\nthen:\n%s", format, first, again)
+				}
+			}
+			assertGolden(t, format+".golden", first)
+		})
+	}
+}
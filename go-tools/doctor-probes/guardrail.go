@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/smithy-go"
+)
+
+// runGuardrailChecks confirms the --guardrail-id guardrail exists in the region
+// and is READY. Workflows that require a guardrail fail every request when it
+// is missing or broken, which otherwise looks like a model problem.
+func runGuardrailChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	name := "Guardrail - " + cfg.GuardrailID
+	version := cfg.GuardrailVersion
+	label := cfg.GuardrailID
+	if version != "" {
+		label += " version " + version
+	}
+
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load AWS config: %v", err),
+			Fix:     "Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+		}}
+	}
+
+	input := &bedrock.GetGuardrailInput{GuardrailIdentifier: aws.String(cfg.GuardrailID)}
+	if version != "" {
+		input.GuardrailVersion = aws.String(version)
+	}
+	logger.Debug("getting guardrail", "guardrail", cfg.GuardrailID, "version", version, "region", region)
+	output, err := newControlClient(cfg, awsCfg).GetGuardrail(ctx, input)
+	if err != nil {
+		return []CheckResult{guardrailErrorResult(name, label, region, err)}
+	}
+
+	described := fmt.Sprintf("Guardrail %s (%s) version %s", aws.ToString(output.Name), aws.ToString(output.GuardrailId), aws.ToString(output.Version))
+	switch output.Status {
+	case types.GuardrailStatusReady:
+		return []CheckResult{{
+			Name:    name,
+			Status:  "pass",
+			Message: described + " is READY",
+		}}
+	case types.GuardrailStatusCreating, types.GuardrailStatusUpdating, types.GuardrailStatusVersioning:
+		return []CheckResult{{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("%s is %s; requests that apply it fail until it is READY", described, output.Status),
+			Fix:     "Wait for the change to finish and run the checks again",
+		}}
+	}
+
+	message := fmt.Sprintf("%s is %s, so every request that applies it is blocked", described, output.Status)
+	if len(output.StatusReasons) > 0 {
+		message += ": " + strings.Join(output.StatusReasons, "; ")
+	}
+	fix := fmt.Sprintf("Fix or recreate the guardrail in the Bedrock console for %s, or point --guardrail-id at a READY one", region)
+	if len(output.FailureRecommendations) > 0 {
+		fix = strings.Join(output.FailureRecommendations, "; ")
+	}
+	return []CheckResult{{
+		Name:    name,
+		Status:  "fail",
+		Message: message,
+		Fix:     fix,
+	}}
+}
+
+// guardrailErrorResult explains a GetGuardrail error; the API's own messages
+// for a wrong ID or version don't say which part is wrong
+func guardrailErrorResult(name, label, region string, err error) CheckResult {
+	var apiErr smithy.APIError
+	errors.As(err, &apiErr)
+	switch {
+	case apiErr != nil && apiErr.ErrorCode() == "ResourceNotFoundException":
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Guardrail %s does not exist in %s; requests that require it will be rejected", label, region),
+			Fix:     fmt.Sprintf("Check the guardrail ID and version with 'aws bedrock list-guardrails --region %s'; guardrails are regional", region),
+		}
+	case apiErr != nil && apiErr.ErrorCode() == "ValidationException":
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s is not a valid guardrail identifier or version: %s", label, apiErr.ErrorMessage()),
+			Fix:     "Pass the guardrail ID (e.g. abc123def456) or ARN with --guardrail-id, and a version number or DRAFT with --guardrail-version",
+		}
+	case apiErr != nil && apiErr.ErrorCode() == "AccessDeniedException":
+		return CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Not allowed to read guardrail %s in %s: %s", label, region, apiErr.ErrorMessage()),
+			Fix:       "Allow bedrock:GetGuardrail on the guardrail for this principal",
+			ErrorKind: ErrorKindAuth,
+		}
+	}
+	return CheckResult{
+		Name:      name,
+		Status:    "fail",
+		Message:   fmt.Sprintf("Could not get guardrail %s in %s: %v", label, region, err),
+		Fix:       awsErrorFix(err, "Check credentials and network access to Bedrock"),
+		ErrorKind: newProbeError("", err).Kind,
+	}
+}
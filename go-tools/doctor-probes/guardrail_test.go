@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestGuardrailErrorResult(t *testing.T) {
+	tests := []struct {
+		err      error
+		contains string
+		kind     string
+	}{
+		{&smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "The resource does not exist"}, "does not exist in us-east-1", ""},
+		{&smithy.GenericAPIError{Code: "ValidationException", Message: "1 validation error detected"}, "not a valid guardrail identifier", ""},
+		{&smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized"}, "Not allowed", ErrorKindAuth},
+		{errors.New("connection reset"), "Could not get guardrail", ""},
+	}
+	for _, tt := range tests {
+		result := guardrailErrorResult("Guardrail - gr1", "gr1 version 2", "us-east-1", tt.err)
+		if result.Status != "fail" || !strings.Contains(result.Message, tt.contains) || result.ErrorKind != tt.kind {
+			t.Errorf("guardrailErrorResult(%v) = %s %q (kind %q); want fail containing %q (kind %q)", tt.err, result.Status, result.Message, result.ErrorKind, tt.contains, tt.kind)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Runs are kept for a week unless --history-retention says otherwise
+const defaultHistoryRetention = 7 * 24 * time.Hour
+
+// History files hold one day of runs each, as one JSON line per run
+const historyFilePrefix, historyFileSuffix = "doctor-", ".ndjson"
+
+// historyEntry is one recorded run
+type historyEntry struct {
+	Time    time.Time      `json:"time"`
+	Summary *Summary       `json:"summary,omitempty"`
+	Checks  []historyCheck `json:"checks"`
+}
+
+// historyCheck keeps what the history table needs, not the full result
+type historyCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func historyFile(dir string, at time.Time) string {
+	return filepath.Join(dir, historyFilePrefix+at.UTC().Format(time.DateOnly)+historyFileSuffix)
+}
+
+// recordHistory appends a run to today's file in dir, then removes files
+// whose last run is older than retention
+func recordHistory(dir string, retention time.Duration, output ProbeOutput, now time.Time) error {
+	entry := historyEntry{Time: now.UTC(), Summary: output.Summary}
+	for _, check := range output.Checks {
+		entry.Checks = append(entry.Checks, historyCheck{Name: check.Name, Status: check.Status})
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(historyFile(dir, now), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return pruneHistory(dir, retention, now)
+}
+
+// pruneHistory removes history files last written before now-retention
+func pruneHistory(dir string, retention time.Duration, now time.Time) error {
+	paths, err := filepath.Glob(filepath.Join(dir, historyFilePrefix+"*"+historyFileSuffix))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err == nil && now.Sub(info.ModTime()) > retention {
+			logger.Debug("pruning history file", "path", path, "modified", info.ModTime())
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readHistory returns the runs in dir recorded at or after since, oldest first.
+// Lines that don't parse, such as one cut short by a crash, are skipped.
+func readHistory(dir string, since time.Time) ([]historyEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, historyFilePrefix+"*"+historyFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	var entries []historyEntry
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			var entry historyEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				logger.Debug("skipping unreadable history line", "path", path, "error", err)
+				continue
+			}
+			if !entry.Time.Before(since) {
+				entries = append(entries, entry)
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	slices.SortFunc(entries, func(a, b historyEntry) int { return a.Time.Compare(b.Time) })
+	return entries, nil
+}
+
+// runHistory prints each check's pass rate per time bucket from --history-dir
+func runHistory(args []string) {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	dir := flags.String("history-dir", "", "Directory the doctor's --history-dir recorded runs to (required)")
+	since := flags.Duration("since", 24*time.Hour, "How far back to report")
+	bucket := flags.Duration("bucket", 4*time.Hour, "Width of each column")
+	parseFlags(flags, args)
+	if *dir == "" {
+		fmt.Fprintln(os.Stderr, "history requires --history-dir")
+		os.Exit(exitConfigError)
+	}
+	if *since <= 0 || *bucket <= 0 {
+		fmt.Fprintln(os.Stderr, "--since and --bucket must be greater than zero")
+		os.Exit(exitConfigError)
+	}
+
+	now := time.Now()
+	entries, err := readHistory(*dir, now.Add(-*since))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read history: %v\n", err)
+		os.Exit(1)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "no runs recorded in %s in the last %s\n", *dir, *since)
+		os.Exit(1)
+	}
+	writeHistoryTable(os.Stdout, entries, now.Add(-*since).Truncate(*bucket), *bucket)
+}
+
+// writeHistoryTable prints one row per check and one column per bucket from
+// start, each cell the share of that bucket's runs in which the check passed
+func writeHistoryTable(w io.Writer, entries []historyEntry, start time.Time, bucket time.Duration) {
+	columns := int(entries[len(entries)-1].Time.Sub(start)/bucket) + 1
+	type counts struct{ passed, runs int }
+	rates := map[string][]counts{}
+	var names []string
+	for _, entry := range entries {
+		column := int(entry.Time.Sub(start) / bucket)
+		if column < 0 {
+			continue
+		}
+		for _, check := range entry.Checks {
+			if _, ok := rates[check.Name]; !ok {
+				rates[check.Name] = make([]counts, columns)
+				names = append(names, check.Name)
+			}
+			rates[check.Name][column].runs++
+			if check.Status == "pass" {
+				rates[check.Name][column].passed++
+			}
+		}
+	}
+	slices.Sort(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"CHECK"}
+	for i := range columns {
+		header = append(header, start.Add(time.Duration(i)*bucket).Local().Format("Jan 2 15:04"))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, name := range names {
+		row := []string{name}
+		for _, cell := range rates[name] {
+			if cell.runs == 0 {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, fmt.Sprintf("%d%%", cell.passed*100/cell.runs))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d runs; each cell is the share of runs in which the check passed\n", len(entries))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	runs := [][]CheckResult{
+		{{Name: "DNS - Bedrock Runtime", Status: "pass"}, {Name: "TCP - Bedrock Runtime", Status: "pass"}},
+		{{Name: "DNS - Bedrock Runtime", Status: "pass"}, {Name: "TCP - Bedrock Runtime", Status: "fail"}},
+		{{Name: "DNS - Bedrock Runtime", Status: "pass"}, {Name: "TCP - Bedrock Runtime", Status: "warn"}},
+	}
+	for i, run := range runs {
+		if err := recordHistory(dir, defaultHistoryRetention, newProbeOutput(run, []string{"us-east-1"}), now.Add(time.Duration(i)*90*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := readHistory(dir, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Checks[1].Status != "fail" {
+		t.Fatalf("readHistory after the first run = %+v, want the last two runs", entries)
+	}
+
+	entries, _ = readHistory(dir, now.Add(-time.Hour))
+	var buf bytes.Buffer
+	writeHistoryTable(&buf, entries, now, 2*time.Hour)
+	got := buf.String()
+	for _, want := range []string{"DNS - Bedrock Runtime  100%", "TCP - Bedrock Runtime  50%", "3 runs"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("history table missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	old := historyFile(dir, now.AddDate(0, 0, -10))
+	recent := historyFile(dir, now.AddDate(0, 0, -1))
+	for _, path := range []string{old, recent} {
+		if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Chtimes(old, now.AddDate(0, 0, -10), now.AddDate(0, 0, -10))
+	os.Chtimes(recent, now.AddDate(0, 0, -1), now.AddDate(0, 0, -1))
+
+	if err := pruneHistory(dir, defaultHistoryRetention, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("file older than the retention was kept: %v", err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recent file was removed: %v", err)
+	}
+}
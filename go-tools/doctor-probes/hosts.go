@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// parseHostPort validates a --hosts entry
+func parseHostPort(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		return fmt.Errorf("missing host")
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("port %q is not between 1 and 65535", port)
+	}
+	return nil
+}
+
+// runHostChecks probes each --hosts entry with the same DNS and TCP logic used for Bedrock
+func runHostChecks(ctx context.Context, cfg *Config) []CheckResult {
+	results := make([][]CheckResult, len(cfg.Hosts))
+	var wg sync.WaitGroup
+	for i, address := range cfg.Hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = checkHost(ctx, cfg, address)
+		}()
+	}
+	wg.Wait()
+
+	var all []CheckResult
+	for _, hostResults := range results {
+		all = append(all, hostResults...)
+	}
+	return all
+}
+
+func checkHost(ctx context.Context, cfg *Config, address string) []CheckResult {
+	host, _, _ := net.SplitHostPort(address)
+	name := "DNS - " + address
+
+	start := time.Now()
+	ips, attempts, err := checkDNS(ctx, cfg, host)
+	durationMs := time.Since(start).Milliseconds()
+	if err != nil {
+		message := fmt.Sprintf("Failed to resolve %s: %v", host, err)
+		if attempts > 1 {
+			message = fmt.Sprintf("Failed to resolve %s after %d attempts: %v", host, attempts, err)
+		}
+		// No TCP result: it would only repeat the lookup failure
+		return []CheckResult{{
+			Name:       name,
+			Status:     "fail",
+			Message:    message,
+			Fix:        "Check the hostname, internet connectivity and DNS settings",
+			ErrorKind:  errorKind(err),
+			DurationMs: durationMs,
+		}}
+	}
+
+	addresses := make([]string, len(ips))
+	for i, ip := range ips {
+		addresses[i] = ip.String()
+	}
+	return []CheckResult{
+		{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("Resolved %s to %s", host, strings.Join(addresses, ", ")),
+			DurationMs: durationMs,
+			Addresses:  addresses,
+		},
+		probeTCPAddress(ctx, cfg, "TCP - "+address, address),
+	}
+}
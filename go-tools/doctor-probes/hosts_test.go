@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestParseHostPort(t *testing.T) {
+	for _, value := range []string{"gateway.example.com:443", "127.0.0.1:8080", "[::1]:53"} {
+		if err := parseHostPort(value); err != nil {
+			t.Errorf("parseHostPort(%q) = %v, want nil", value, err)
+		}
+	}
+	for _, value := range []string{"example.com", ":443", "example.com:0", "example.com:https", "example.com:70000"} {
+		if err := parseHostPort(value); err == nil {
+			t.Errorf("parseHostPort(%q) succeeded, want error", value)
+		}
+	}
+}
+
+func TestRunHostChecks(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	cfg := newConfig()
+	cfg.Hosts = []string{listener.Addr().String()}
+	results := runHostChecks(context.Background(), cfg)
+
+	want := []string{"DNS - " + cfg.Hosts[0], "TCP - " + cfg.Hosts[0]}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, result := range results {
+		if result.Name != want[i] || result.Status != "pass" {
+			t.Errorf("result %d = %s %s (%s), want %s pass", i, result.Name, result.Status, result.Message, want[i])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// negotiateHTTP2 sends one HEAD request offering h2 and http/1.1 over ALPN,
+// through any configured proxy, and returns the protocol the server settled on
+func negotiateHTTP2(ctx context.Context, url string, tlsConfig *tls.Config) (string, *http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// A custom TLS config turns off HTTP/2 unless it is forced back on
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			TLSClientConfig:   tlsConfig,
+			ForceAttemptHTTP2: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	resp.Body.Close()
+
+	protocol := ""
+	if resp.TLS != nil {
+		protocol = resp.TLS.NegotiatedProtocol
+	}
+	return protocol, resp, nil
+}
+
+// runHTTP2Checks verifies the endpoint negotiates HTTP/2, which
+// InvokeModelWithResponseStream needs; TLS-inspecting proxies often only speak HTTP/1.1
+func runHTTP2Checks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	return []CheckResult{checkHTTP2(ctx, cfg, region, &tls.Config{})}
+}
+
+func checkHTTP2(ctx context.Context, cfg *Config, region string, tlsConfig *tls.Config) CheckResult {
+	name := "HTTP/2 - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	url := "https://" + cfg.endpointAddr("bedrock-runtime", region) + "/"
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	logger.Debug("negotiating HTTP/2", "url", url)
+	start := time.Now()
+	protocol, resp, err := negotiateHTTP2(ctx, url, tlsConfig)
+	durationMs := time.Since(start).Milliseconds()
+	if err != nil {
+		return CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("HEAD %s failed: %v", url, err),
+			Fix:        "Check proxy settings and that outbound HTTPS to AWS is allowed",
+			ErrorKind:  newProbeError(ErrorKindTLS, err).Kind,
+			DurationMs: durationMs,
+		}
+	}
+	logger.Debug("HTTP/2 negotiation finished", "url", url, "alpn", protocol, "proto", resp.Proto)
+
+	if protocol != "h2" {
+		if protocol == "" {
+			protocol = "none"
+		}
+		return CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("%s negotiated ALPN %q and answered over %s instead of HTTP/2; streaming responses may stall or fail", url, protocol, resp.Proto),
+			Fix:        "A proxy or TLS-inspecting firewall is likely downgrading to HTTP/1.1; ask for *.amazonaws.com to be exempted from inspection or to enable HTTP/2 on the proxy",
+			DurationMs: durationMs,
+		}
+	}
+	return CheckResult{
+		Name:       name,
+		Status:     "pass",
+		Message:    fmt.Sprintf("%s negotiated ALPN \"h2\" (%s)", url, resp.Proto),
+		DurationMs: durationMs,
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckHTTP2(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		http2 bool
+		want  string
+	}{
+		{"h2 negotiated", true, "pass"},
+		{"downgraded to http/1.1", false, "warn"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			server.EnableHTTP2 = tt.http2
+			server.StartTLS()
+			defer server.Close()
+
+			cfg := newConfig()
+			cfg.Endpoints = map[string]string{"bedrock-runtime": strings.TrimPrefix(server.URL, "https://")}
+			tlsConfig := &tls.Config{RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}
+
+			result := checkHTTP2(context.Background(), cfg, "us-east-1", tlsConfig)
+			if result.Status != tt.want {
+				t.Errorf("status = %s (%s), want %s", result.Status, result.Message, tt.want)
+			}
+		})
+	}
+}
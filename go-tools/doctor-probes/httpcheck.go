@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// runHTTPChecks times one unauthenticated HTTPS request to the Bedrock endpoint,
+// including DNS, TCP, TLS, and any proxy. Auth isn't the point, so any 4xx
+// still counts as reachable.
+func runHTTPChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	name := "HTTPS - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	url := "https://" + cfg.endpointAddr("bedrock-runtime", region) + "/"
+	target := url + cfg.viaProxy()
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to build request for %s: %v", url, err),
+		})
+		return results
+	}
+
+	// A fresh transport so connection reuse can't hide handshake time
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, DialContext: cfg.dialFunc()},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	logger.Debug("sending HTTPS request", "url", url)
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	durationMs := latency.Milliseconds()
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("HEAD %s failed: %v", target, err),
+			Fix:        "Check proxy settings and that outbound HTTPS to AWS is allowed",
+			ErrorKind:  newProbeError("", err).Kind,
+			DurationMs: durationMs,
+		})
+		return results
+	}
+	resp.Body.Close()
+	logger.Debug("HTTPS request finished", "url", url, "status", resp.Status, "duration", latency)
+
+	latency = latency.Round(time.Millisecond)
+	switch {
+	case resp.StatusCode >= 500:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("HEAD %s returned %s in %s", target, resp.Status, latency),
+			Fix:        "The endpoint or an intermediate proxy is returning server errors; retry later",
+			DurationMs: durationMs,
+		})
+	case cfg.HTTPLatencyWarn > 0 && latency > cfg.HTTPLatencyWarn:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("HEAD %s took %s (HTTP %d), above %s", target, latency, resp.StatusCode, cfg.HTTPLatencyWarn),
+			Fix:        "Check for slow proxies or a distant region; try a closer region with --regions",
+			DurationMs: durationMs,
+		})
+	default:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("HEAD %s returned HTTP %d in %s", target, resp.StatusCode, latency),
+			DurationMs: durationMs,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Streaming responses can pause this long between events, so anything that
+// drops idle connections sooner will cut long generations off
+const idleWarnThreshold = 60 * time.Second
+
+// holdIdle waits on an idle connection for up to idle and returns how long it
+// stayed open and the error that closed it, or nil if it outlived idle
+func holdIdle(ctx context.Context, conn net.Conn, idle time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn.SetReadDeadline(start.Add(idle))
+	// Closing the connection unblocks the read when the run is canceled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// The endpoint never sends unsolicited data, so the read only returns on close or deadline
+	_, err := conn.Read(make([]byte, 1))
+	held := time.Since(start)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return held, nil
+	}
+	if ctx.Err() != nil {
+		return held, ctx.Err()
+	}
+	if err == nil {
+		err = fmt.Errorf("unexpected data on the idle connection")
+	}
+	return held, err
+}
+
+// runIdleChecks opens a TLS connection to Bedrock Runtime and leaves it idle
+// for --idle-probe to see whether a proxy, NAT gateway or load balancer drops
+// it, then sends a request to catch connections dropped without a reset
+func runIdleChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	name := "Idle Connection - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	host := cfg.endpointHost("bedrock-runtime", region)
+	address := cfg.endpointAddr("bedrock-runtime", region)
+
+	dialCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	dialer := &tls.Dialer{NetDialer: cfg.dialer(), Config: &tls.Config{ServerName: host}}
+	conn, err := dialer.DialContext(dialCtx, "tcp", address)
+	cancel()
+	if err != nil {
+		return []CheckResult{{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Could not open a connection to %s: %v", address, err),
+			Fix:       "Fix basic TCP/TLS connectivity first (see the TCP and TLS checks)",
+			ErrorKind: ErrorKindTLS,
+		}}
+	}
+	defer conn.Close()
+
+	logger.Debug("holding idle connection", "address", address, "idle", cfg.IdleProbe)
+	held, err := holdIdle(ctx, conn, cfg.IdleProbe)
+	logger.Debug("idle connection finished", "address", address, "held", held, "error", err)
+	if ctx.Err() != nil {
+		return []CheckResult{{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("Canceled after holding %s idle for %s", address, held.Round(time.Second)),
+			DurationMs: held.Milliseconds(),
+		}}
+	}
+	if err != nil {
+		return []CheckResult{idleDropResult(name, address, held, err)}
+	}
+
+	// Some middleboxes forget the connection without telling either side
+	if err := sendIdleRequest(ctx, conn, host, cfg.Timeout); err != nil {
+		return []CheckResult{{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("Connection to %s was silently dropped within %s idle; the next request failed (%v)", address, cfg.IdleProbe, err),
+			Fix:        "Something in the path drops idle connections without a reset; raise its idle timeout or enable TCP keepalives below it",
+			DurationMs: held.Milliseconds(),
+		}}
+	}
+	return []CheckResult{{
+		Name:       name,
+		Status:     "pass",
+		Message:    fmt.Sprintf("Connection to %s stayed open and usable after %s idle", address, cfg.IdleProbe),
+		DurationMs: held.Milliseconds(),
+	}}
+}
+
+// idleDropResult reports a connection that was closed after held; only drops
+// sooner than idleWarnThreshold predict streaming disconnects
+func idleDropResult(name, address string, held time.Duration, err error) CheckResult {
+	message := fmt.Sprintf("Connection to %s was closed after %s idle (%v)", address, held.Round(time.Second), err)
+	if held < idleWarnThreshold {
+		return CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    message + "; streaming responses that pause this long will be cut off",
+			Fix:        "Raise the idle timeout on the proxy, load balancer or firewall in the path, or enable TCP keepalives below it",
+			DurationMs: held.Milliseconds(),
+		}
+	}
+	return CheckResult{
+		Name:       name,
+		Status:     "pass",
+		Message:    message + fmt.Sprintf("; an idle timeout of at least %s is enough for streaming", idleWarnThreshold),
+		DurationMs: held.Milliseconds(),
+	}
+}
+
+// sendIdleRequest checks that conn still carries a request and response
+func sendIdleRequest(ctx context.Context, conn net.Conn, host string, timeout time.Duration) error {
+	conn.SetDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if _, err := fmt.Fprintf(conn, "HEAD / HTTP/1.1\r\nHost: %s\r\nConnection: close\r\n\r\n", host); err != nil {
+		return err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
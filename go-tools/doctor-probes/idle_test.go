@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestHoldIdle(t *testing.T) {
+	t.Run("outlives the idle period", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer server.Close()
+		held, err := holdIdle(context.Background(), client, 20*time.Millisecond)
+		if err != nil || held < 20*time.Millisecond {
+			t.Errorf("holdIdle = %s, %v, want at least 20ms and no error", held, err)
+		}
+	})
+	t.Run("closed by the peer", func(t *testing.T) {
+		client, server := net.Pipe()
+		time.AfterFunc(10*time.Millisecond, func() { server.Close() })
+		held, err := holdIdle(context.Background(), client, time.Second)
+		if err != io.EOF || held >= time.Second {
+			t.Errorf("holdIdle = %s, %v, want EOF well before 1s", held, err)
+		}
+	})
+	t.Run("canceled", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := holdIdle(ctx, client, time.Second); err != context.DeadlineExceeded {
+			t.Errorf("holdIdle error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
+
+func TestIdleDropResult(t *testing.T) {
+	tests := []struct {
+		held time.Duration
+		want string
+	}{
+		{30 * time.Second, "warn"},
+		{idleWarnThreshold, "pass"},
+		{350 * time.Second, "pass"},
+	}
+	for _, tt := range tests {
+		if got := idleDropResult("Idle Connection", "bedrock:443", tt.held, io.EOF); got.Status != tt.want {
+			t.Errorf("idleDropResult(%s) status = %s, want %s", tt.held, got.Status, tt.want)
+		}
+	}
+}
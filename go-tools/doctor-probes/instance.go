@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultIMDSEndpoint = "http://169.254.169.254"
+	ecsCredentialsHost  = "http://169.254.170.2"
+
+	// Metadata endpoints are link-local; anything slower means we're not on EC2 or ECS
+	instanceMetadataTimeout = time.Second
+)
+
+func imdsEndpoint() string {
+	if endpoint := os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT"); endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/")
+	}
+	return defaultIMDSEndpoint
+}
+
+// metadataRequest performs a single metadata call bounded by the short instance metadata timeout
+func metadataRequest(ctx context.Context, method, url string, header http.Header) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, instanceMetadataTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	// Metadata must never go through a proxy
+	client := &http.Client{Transport: &http.Transport{Proxy: nil}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, string(body), err
+}
+
+// runInstanceRoleChecks reports the ECS task role or EC2 instance profile the
+// default credential chain would fall back to
+func runInstanceRoleChecks(ctx context.Context) []CheckResult {
+	// Lambda exports the execution role's credentials as environment variables
+	if _, ok := lambdaFunction(); ok {
+		return lambdaSkipsIMDS("Instance Role - Lambda")
+	}
+	if results, ok := checkECSTaskRole(ctx); ok {
+		return results
+	}
+	return checkEC2InstanceRole(ctx)
+}
+
+func checkECSTaskRole(ctx context.Context) ([]CheckResult, bool) {
+	name := "Instance Role - ECS Task"
+	url := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if relative := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); relative != "" {
+		url = ecsCredentialsHost + relative
+	}
+	if url == "" {
+		return nil, false
+	}
+
+	header := http.Header{}
+	if token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"); token != "" {
+		header.Set("Authorization", token)
+	}
+	logger.Debug("querying ECS credentials endpoint", "url", url)
+	status, body, err := metadataRequest(ctx, http.MethodGet, url, header)
+	if err != nil || status != http.StatusOK {
+		message := fmt.Sprintf("ECS credentials endpoint %s is not reachable: %v", url, err)
+		if err == nil {
+			message = fmt.Sprintf("ECS credentials endpoint %s returned HTTP %d", url, status)
+		}
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: message,
+			Fix:     "Check that the task definition has a taskRoleArn and the container can reach 169.254.170.2",
+		}}, true
+	}
+
+	var creds struct {
+		RoleArn string `json:"RoleArn"`
+	}
+	json.Unmarshal([]byte(body), &creds)
+	role := creds.RoleArn
+	if role == "" {
+		role = "unknown role"
+	}
+	return []CheckResult{{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("Running on ECS with task role %s", role),
+	}}, true
+}
+
+func checkEC2InstanceRole(ctx context.Context) []CheckResult {
+	name := "Instance Role - EC2"
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "pass",
+			Message: "Instance metadata lookups are disabled (AWS_EC2_METADATA_DISABLED=true)",
+		}}
+	}
+
+	endpoint := imdsEndpoint()
+	tokenHeader := http.Header{}
+	tokenHeader.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	logger.Debug("requesting IMDSv2 token", "endpoint", endpoint)
+	status, token, err := metadataRequest(ctx, http.MethodPut, endpoint+"/latest/api/token", tokenHeader)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "pass",
+			Message: "Not running on EC2 or ECS (instance metadata not reachable); instance roles don't apply",
+		}}
+	}
+	if status != http.StatusOK {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("IMDSv2 token request returned HTTP %d", status),
+			Fix:     "If running in a container on EC2, raise the instance's HttpPutResponseHopLimit to 2",
+			reason:  reasonIMDSv2,
+		}}
+	}
+
+	header := http.Header{}
+	header.Set("X-aws-ec2-metadata-token", token)
+	status, roles, err := metadataRequest(ctx, http.MethodGet, endpoint+"/latest/meta-data/iam/security-credentials/", header)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Instance metadata is reachable but the role lookup failed: %v", err),
+			Fix:     "Retry, or check for a host firewall blocking 169.254.169.254",
+		}}
+	}
+	role := strings.TrimSpace(strings.SplitN(roles, "\n", 2)[0])
+	if status == http.StatusNotFound || role == "" {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: "Running on EC2 but no IAM instance profile is attached",
+			Fix:     "Attach an instance profile with Bedrock permissions, or configure credentials another way",
+		}}
+	}
+
+	// A tokenless request is rejected with 401 when the instance requires IMDSv2
+	v1Status, _, v1Err := metadataRequest(ctx, http.MethodGet, endpoint+"/latest/meta-data/", nil)
+	imdsVersion := "IMDSv1 and IMDSv2 allowed"
+	if v1Err == nil && v1Status == http.StatusUnauthorized {
+		imdsVersion = "IMDSv2 enforced"
+	}
+
+	return []CheckResult{{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("Running on EC2 with instance role %s (%s)", role, imdsVersion),
+	}}
+}
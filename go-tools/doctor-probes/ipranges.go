@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ipRangesURL        = "https://ip-ranges.amazonaws.com/ip-ranges.json"
+	defaultIPRangesTTL = 24 * time.Hour
+)
+
+type ipRanges struct {
+	Prefixes []struct {
+		IPPrefix string `json:"ip_prefix"`
+		Region   string `json:"region"`
+		Service  string `json:"service"`
+	} `json:"prefixes"`
+	IPv6Prefixes []struct {
+		IPv6Prefix string `json:"ipv6_prefix"`
+		Region     string `json:"region"`
+		Service    string `json:"service"`
+	} `json:"ipv6_prefixes"`
+}
+
+func ipRangesCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bcce", "ip-ranges.json"), nil
+}
+
+// ipRangesLoader shares one load of ip-ranges.json between concurrent region jobs
+type ipRangesLoader struct {
+	once   sync.Once
+	ranges *ipRanges
+	err    error
+}
+
+// Replaced at the start of each run so watch mode still honors the cache TTL
+var currentIPRanges = &ipRangesLoader{}
+
+func resetIPRanges() {
+	currentIPRanges = &ipRangesLoader{}
+}
+
+func sharedIPRanges(ctx context.Context, cfg *Config) (*ipRanges, error) {
+	loader := currentIPRanges
+	loader.once.Do(func() {
+		loader.ranges, loader.err = loadIPRanges(ctx, cfg)
+	})
+	return loader.ranges, loader.err
+}
+
+// loadIPRanges returns the published AWS ranges, reusing the cached copy while it is younger than ttl
+func loadIPRanges(ctx context.Context, cfg *Config) (*ipRanges, error) {
+	cachePath, cacheErr := ipRangesCachePath()
+	if cacheErr == nil {
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < cfg.IPRangesTTL {
+			if data, err := os.ReadFile(cachePath); err == nil {
+				var ranges ipRanges
+				if err := json.Unmarshal(data, &ranges); err == nil {
+					logger.Debug("using cached AWS IP ranges", "path", cachePath, "age", time.Since(info.ModTime()))
+					return &ranges, nil
+				}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	logger.Debug("fetching AWS IP ranges", "url", ipRangesURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ipRangesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var ranges ipRanges
+	if err := json.Unmarshal(data, &ranges); err != nil {
+		return nil, fmt.Errorf("invalid ip-ranges.json: %w", err)
+	}
+
+	if cacheErr == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			if err := writeFileAtomic(cachePath, data); err != nil {
+				logger.Debug("failed to cache AWS IP ranges", "path", cachePath, "error", err)
+			}
+		}
+	}
+
+	return &ranges, nil
+}
+
+// regionNetworks returns the AMAZON prefixes published for a region (plus GLOBAL ones)
+func (r *ipRanges) regionNetworks(region string) []*net.IPNet {
+	var networks []*net.IPNet
+	add := func(prefix, prefixRegion, service string) {
+		if service != "AMAZON" || (prefixRegion != region && prefixRegion != "GLOBAL") {
+			return
+		}
+		if _, network, err := net.ParseCIDR(prefix); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	for _, p := range r.Prefixes {
+		add(p.IPPrefix, p.Region, p.Service)
+	}
+	for _, p := range r.IPv6Prefixes {
+		add(p.IPv6Prefix, p.Region, p.Service)
+	}
+	return networks
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func runIPRangeChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	name := "IP Ranges - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	bedrockHost := cfg.endpointHost("bedrock-runtime", region)
+	ips, _, err := checkDNS(ctx, cfg, bedrockHost)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to resolve %s: %v", bedrockHost, err),
+			Fix:     "Check internet connectivity and DNS settings",
+		})
+		return results
+	}
+
+	ranges, err := sharedIPRanges(ctx, cfg)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("Could not load AWS IP ranges: %v", err),
+			Fix:     fmt.Sprintf("Allow HTTPS access to %s or retry later", ipRangesURL),
+		})
+		return results
+	}
+
+	networks := ranges.regionNetworks(region)
+	var private, suspicious []string
+	for _, ip := range ips {
+		switch {
+		case ip.IsPrivate():
+			private = append(private, ip.String())
+		case !containsIP(networks, ip):
+			suspicious = append(suspicious, ip.String())
+		}
+	}
+
+	if len(suspicious) > 0 {
+		status := "warn"
+		if cfg.Strict {
+			status = "fail"
+		}
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  status,
+			Message: fmt.Sprintf("%s resolved to addresses outside published AWS ranges for %s: %s", bedrockHost, region, strings.Join(suspicious, ", ")),
+			Fix:     "Check for DNS hijacking, captive portals, or stale hosts file entries and VPC endpoint records",
+			reason:  reasonOutsideAWSRanges,
+		})
+		return results
+	}
+
+	message := fmt.Sprintf("All addresses for %s are within published AWS ranges for %s", bedrockHost, region)
+	if len(private) > 0 {
+		message = fmt.Sprintf("%s resolved to private addresses (%s), likely a VPC endpoint", bedrockHost, strings.Join(private, ", "))
+	}
+	results = append(results, CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: message,
+	})
+
+	return results
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func junitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// writeJUnit renders results as a single JUnit XML test suite for CI test reports.
+// Failures become <failure> elements; warnings pass with a note in <system-out>.
+func writeJUnit(w io.Writer, results []CheckResult) error {
+	suite := junitTestSuite{Name: "bcce-doctor-probes", Tests: len(results)}
+
+	var totalMs int64
+	for _, result := range results {
+		totalMs += result.DurationMs
+
+		className := "doctor-probes"
+		if result.Region != "" {
+			className = "doctor-probes." + result.Region
+		}
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: className,
+			Time:      junitSeconds(result.DurationMs),
+		}
+
+		switch result.Status {
+		case "fail":
+			suite.Failures++
+			body := result.Message
+			if result.Fix != "" {
+				body += "\nFix: " + result.Fix
+			}
+			if result.FixURL != "" {
+				body += "\nSee: " + result.FixURL
+			}
+			testCase.Failure = &junitFailure{Message: result.Message, Type: "fail", Body: body}
+		case "warn":
+			lines := []string{"WARN: " + result.Message}
+			if result.Fix != "" {
+				lines = append(lines, "Fix: "+result.Fix)
+			}
+			if result.FixURL != "" {
+				lines = append(lines, "See: "+result.FixURL)
+			}
+			testCase.SystemOut = strings.Join(lines, "\n")
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = junitSeconds(totalMs)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
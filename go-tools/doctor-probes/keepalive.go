@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// keepAliveRequest is what httptrace saw while sending one request
+type keepAliveRequest struct {
+	reused, handshake bool
+	latency           time.Duration
+	closeSent         bool
+}
+
+// sendTraced sends a HEAD request through client and records whether it went
+// out on a pooled connection
+func sendTraced(ctx context.Context, client *http.Client, url string) (keepAliveRequest, error) {
+	var request keepAliveRequest
+	trace := &httptrace.ClientTrace{
+		GotConn:           func(info httptrace.GotConnInfo) { request.reused = info.Reused },
+		ConnectStart:      func(string, string) { request.handshake = true },
+		TLSHandshakeStart: func() { request.handshake = true },
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodHead, url, nil)
+	if err != nil {
+		return request, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	request.latency = time.Since(start)
+	if err != nil {
+		return request, err
+	}
+	// A connection only goes back to the pool once its body is drained
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	request.closeSent = resp.Close
+	return request, nil
+}
+
+func runKeepAliveChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	return []CheckResult{checkKeepAlive(ctx, cfg, region, &tls.Config{})}
+}
+
+// checkKeepAlive sends two requests in a row on one pooled client, the way the
+// SDK does in a long-running process, and checks the second reuses the first's
+// connection instead of paying for another TCP and TLS handshake
+func checkKeepAlive(ctx context.Context, cfg *Config, region string, tlsConfig *tls.Config) CheckResult {
+	name := "Keep-Alive - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	address := cfg.endpointAddr("bedrock-runtime", region)
+	url := "https://" + address + "/"
+	target := address + cfg.viaProxy()
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	transport := &http.Transport{
+		Proxy:             http.ProxyFromEnvironment,
+		DialContext:       cfg.dialFunc(),
+		TLSClientConfig:   tlsConfig,
+		ForceAttemptHTTP2: true,
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	start := time.Now()
+	first, err := sendTraced(ctx, client, url)
+	if err != nil {
+		return CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("HEAD %s failed: %v", url, err),
+			Fix:        "Fix basic HTTPS connectivity first (see the TCP, TLS and HTTPS checks)",
+			ErrorKind:  newProbeError("", err).Kind,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+	}
+	second, err := sendTraced(ctx, client, url)
+	durationMs := time.Since(start).Milliseconds()
+	logger.Debug("keep-alive requests finished", "url", url, "first", first.latency, "second", second.latency, "reused", second.reused, "error", err)
+	if err != nil {
+		return CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("The first HEAD %s succeeded but the second failed: %v", url, err),
+			Fix:        "Something in the path breaks connections between requests; check the proxy or firewall for per-request connection limits",
+			ErrorKind:  newProbeError("", err).Kind,
+			DurationMs: durationMs,
+		}
+	}
+
+	if !second.reused || second.handshake {
+		message := fmt.Sprintf("The second request to %s opened a new connection instead of reusing the first", target)
+		if first.closeSent {
+			message += "; the first response carried Connection: close"
+		}
+		return CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    message + fmt.Sprintf(" (%s, then %s)", first.latency.Round(time.Millisecond), second.latency.Round(time.Millisecond)),
+			Fix:        "A proxy in the path likely closes connections after each request or when idle, so long-running processes pay a new TCP and TLS handshake per call; enable keep-alive on the proxy or bypass it for *.amazonaws.com with NO_PROXY",
+			DurationMs: durationMs,
+		}
+	}
+	return CheckResult{
+		Name:       name,
+		Status:     "pass",
+		Message:    fmt.Sprintf("The second request to %s reused the pooled connection (%s, then %s without a handshake)", target, first.latency.Round(time.Millisecond), second.latency.Round(time.Millisecond)),
+		DurationMs: durationMs,
+	}
+}
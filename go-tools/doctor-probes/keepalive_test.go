@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckKeepAlive(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		http2     bool
+		closeConn bool
+		want      string
+	}{
+		{"http/1.1 keep-alive", false, false, "pass"},
+		{"h2", true, false, "pass"},
+		{"connection closed after each response", false, true, "warn"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.closeConn {
+					w.Header().Set("Connection", "close")
+				}
+			}))
+			server.EnableHTTP2 = tt.http2
+			server.StartTLS()
+			defer server.Close()
+
+			cfg := newConfig()
+			cfg.Endpoints = map[string]string{"bedrock-runtime": strings.TrimPrefix(server.URL, "https://")}
+			tlsConfig := &tls.Config{RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}
+
+			result := checkKeepAlive(context.Background(), cfg, "us-east-1", tlsConfig)
+			if result.Status != tt.want {
+				t.Errorf("status = %s (%s), want %s", result.Status, result.Message, tt.want)
+			}
+			if tt.closeConn && !strings.Contains(result.Message, "Connection: close") {
+				t.Errorf("message = %q, want it to name the Connection: close header", result.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// lambdaFunction returns the name of the Lambda function the probes run in, if any
+func lambdaFunction() (string, bool) {
+	name := os.Getenv("AWS_LAMBDA_FUNCTION_NAME")
+	return name, name != ""
+}
+
+// runLambdaChecks reports the function's configuration and whether it can
+// reach Bedrock. A function with no egress almost always means a VPC-attached
+// function without a NAT gateway or interface endpoint, since functions
+// outside a VPC always have internet access.
+func runLambdaChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	function, _ := lambdaFunction()
+	message := "Running in Lambda function " + function
+	var details []string
+	if runtime := os.Getenv("AWS_EXECUTION_ENV"); runtime != "" {
+		details = append(details, runtime)
+	}
+	if memory := os.Getenv("AWS_LAMBDA_FUNCTION_MEMORY_SIZE"); memory != "" {
+		details = append(details, memory+" MB")
+	}
+	if len(details) > 0 {
+		message += " (" + strings.Join(details, ", ") + ")"
+	}
+	if functionRegion := os.Getenv("AWS_REGION"); functionRegion != "" {
+		message += " in " + functionRegion
+	}
+	results := []CheckResult{{
+		Name:    "Lambda - Function",
+		Status:  "pass",
+		Message: message + "; credentials come from its execution role",
+	}}
+
+	name := "Lambda - Egress"
+	host := cfg.endpointHost("bedrock-runtime", region)
+	start := time.Now()
+	ips, err := lookupIP(ctx, cfg, host)
+	durationMs := time.Since(start).Milliseconds()
+	if err != nil {
+		return append(results, CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("Cannot resolve %s: %v; the function is likely VPC-attached without a route to Bedrock", host, err),
+			Fix:        "Give the function's subnets a NAT gateway route, or add a bedrock-runtime interface VPC endpoint with private DNS enabled",
+			ErrorKind:  errorKind(err),
+			DurationMs: durationMs,
+			reason:     reasonVPCEndpoint,
+		})
+	}
+
+	var addresses []string
+	private := true
+	for _, ip := range ips {
+		addresses = append(addresses, ip.String())
+		private = private && ip.IsPrivate()
+	}
+	message = fmt.Sprintf("Resolved %s from inside the function", host)
+	if private {
+		message += "; private addresses mean traffic goes through a VPC interface endpoint"
+	}
+	return append(results, CheckResult{
+		Name:       name,
+		Status:     "pass",
+		Message:    message,
+		DurationMs: durationMs,
+		Addresses:  addresses,
+	})
+}
+
+// lambdaSkipsIMDS is what IMDS-based checks report inside Lambda, which has no
+// instance metadata service to query
+func lambdaSkipsIMDS(name string) []CheckResult {
+	function, _ := lambdaFunction()
+	return []CheckResult{{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("Running in Lambda function %s; instance metadata doesn't apply", function),
+	}}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLambdaChecks(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "claude-proxy")
+	t.Setenv("AWS_EXECUTION_ENV", "AWS_Lambda_provided.al2023")
+	t.Setenv("AWS_LAMBDA_FUNCTION_MEMORY_SIZE", "512")
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	tests := []struct {
+		name       string
+		endpoint   string
+		wantStatus string
+	}{
+		{"reachable", "https://localhost", "pass"},
+		{"no egress", "https://bedrock.invalid", "warn"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			cfg.EndpointURL = tt.endpoint
+			cfg.Timeout = 2 * time.Second
+			results := runLambdaChecks(context.Background(), cfg, "eu-west-1")
+			if len(results) != 2 {
+				t.Fatalf("got %d results, want 2", len(results))
+			}
+			if !strings.Contains(results[0].Message, "claude-proxy") || !strings.Contains(results[0].Message, "eu-west-1") {
+				t.Errorf("function message = %q, want the function name and region", results[0].Message)
+			}
+			if results[1].Status != tt.wantStatus {
+				t.Errorf("egress status = %s (%s), want %s", results[1].Status, results[1].Message, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestInstanceRoleSkippedInLambda(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "claude-proxy")
+	// IMDS would otherwise be tried at this unroutable address
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", "http://192.0.2.1")
+	results := runInstanceRoleChecks(context.Background())
+	if len(results) != 1 || results[0].Status != "pass" || results[0].Name != "Instance Role - Lambda" {
+		t.Errorf("runInstanceRoleChecks = %+v, want a single Lambda pass", results)
+	}
+}
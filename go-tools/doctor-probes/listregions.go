@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/smithy-go"
+)
+
+// regionListing is one row of list-regions
+type regionListing struct {
+	Region string `json:"region"`
+	// Foundation model IDs offered in the region, when they were looked up
+	Models []string `json:"models,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// regionList is the list-regions report; Source is "live" when models were
+// looked up with ListFoundationModels and "static" for the embedded list only
+type regionList struct {
+	Source  string          `json:"source"`
+	Regions []regionListing `json:"regions"`
+}
+
+// runListRegions prints the regions Bedrock is offered in, with each region's
+// foundation models when credentials are available
+func runListRegions(args []string) {
+	flags := flag.NewFlagSet("list-regions", flag.ContinueOnError)
+	jsonOutput := flags.Bool("json", false, "Print the list as JSON")
+	static := flags.Bool("static", false, "Only print the embedded region list, without calling ListFoundationModels")
+	profile := flags.String("profile", "", "AWS profile for the live lookup, overriding AWS_PROFILE")
+	timeout := flags.Duration("timeout", defaultTimeout, "Timeout for each region's lookup")
+	parseFlags(flags, args)
+
+	cfg := newConfig()
+	cfg.Profile = *profile
+	cfg.Timeout = *timeout
+	list := regionList{Source: "static"}
+	for _, region := range bedrockRegions {
+		list.Regions = append(list.Regions, regionListing{Region: region})
+	}
+	if !*static {
+		ctx := context.Background()
+		if credentialsAvailable(ctx, cfg) != nil {
+			fmt.Fprintln(os.Stderr, "No AWS credentials found; showing the embedded region list")
+		} else {
+			list.Source = "live"
+			lookupRegionModels(ctx, cfg, list.Regions)
+		}
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(list)
+		return
+	}
+	writeRegionList(os.Stdout, list)
+}
+
+// credentialsAvailable reports why the default credential chain has nothing to sign with, if so
+func credentialsAvailable(ctx context.Context, cfg *Config) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	awsCfg, err := cfg.loadAWSConfig(ctx, bedrockRegions[0])
+	if err != nil {
+		return err
+	}
+	if awsCfg.Credentials == nil {
+		return fmt.Errorf("no credential provider configured")
+	}
+	_, err = awsCfg.Credentials.Retrieve(ctx)
+	return err
+}
+
+// lookupRegionModels fills in each region's foundation models at once
+func lookupRegionModels(ctx context.Context, cfg *Config, regions []regionListing) {
+	var wg sync.WaitGroup
+	for i := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			models, err := listRegionModels(ctx, cfg, regions[i].Region)
+			regions[i].Models = models
+			if err != nil {
+				regions[i].Error = regionListError(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// regionListError shortens an SDK error, which repeats the operation and request
+// ID, to fit one table row
+func regionListError(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() + ": " + apiErr.ErrorMessage()
+	}
+	if kind := newProbeError("", err).Kind; kind != "" {
+		return fmt.Sprintf("unreachable (%s error)", kind)
+	}
+	return err.Error()
+}
+
+func listRegionModels(ctx context.Context, cfg *Config, region string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("listing foundation models", "region", region)
+	output, err := newControlClient(cfg, awsCfg).ListFoundationModels(ctx, &bedrock.ListFoundationModelsInput{})
+	if err != nil {
+		return nil, err
+	}
+	models := make([]string, 0, len(output.ModelSummaries))
+	for _, summary := range output.ModelSummaries {
+		models = append(models, aws.ToString(summary.ModelId))
+	}
+	slices.Sort(models)
+	return models, nil
+}
+
+// writeRegionList prints one region per line, with a model count for a live lookup
+func writeRegionList(w io.Writer, list regionList) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if list.Source == "static" {
+		fmt.Fprintln(tw, "REGION")
+		for _, region := range list.Regions {
+			fmt.Fprintln(tw, region.Region)
+		}
+	} else {
+		fmt.Fprintln(tw, "REGION\tMODELS\tSTATUS")
+		for _, region := range list.Regions {
+			count, status := strconv.Itoa(len(region.Models)), "available"
+			if region.Error != "" {
+				count, status = "-", region.Error
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", region.Region, count, status)
+		}
+	}
+	tw.Flush()
+	if list.Source == "static" {
+		fmt.Fprintf(w, "\n%d regions in this build's list; with AWS credentials, each region's models are looked up live\n", len(list.Regions))
+	} else {
+		fmt.Fprintf(w, "\n%d regions; model counts from ListFoundationModels (--json lists the IDs)\n", len(list.Regions))
+	}
+}
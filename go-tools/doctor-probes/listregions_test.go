@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestWriteRegionList(t *testing.T) {
+	var static bytes.Buffer
+	writeRegionList(&static, regionList{Source: "static", Regions: []regionListing{{Region: "us-east-1"}, {Region: "eu-west-1"}}})
+	if got := static.String(); !strings.HasPrefix(got, "REGION\nus-east-1\neu-west-1\n") || strings.Contains(got, "MODELS") {
+		t.Errorf("static list:\n%s", got)
+	}
+
+	var live bytes.Buffer
+	writeRegionList(&live, regionList{Source: "live", Regions: []regionListing{
+		{Region: "us-east-1", Models: []string{"amazon.titan-text-lite-v1", "anthropic.claude-3-haiku-20240307-v1:0"}},
+		{Region: "us-gov-west-1", Error: "UnrecognizedClientException: The security token included in the request is invalid."},
+	}})
+	got := live.String()
+	for _, want := range []string{"REGION         MODELS  STATUS", "us-east-1      2       available", "us-gov-west-1  -       UnrecognizedClientException"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("live list missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRegionListError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&smithy.GenericAPIError{Code: "AccessDeniedException", Message: "denied"}, "AccessDeniedException: denied"},
+		{&net.DNSError{Err: "no such host", Name: "bedrock.us-east-1.amazonaws.com"}, "unreachable (dns error)"},
+		{errors.New("boom"), "boom"},
+	}
+	for _, tt := range tests {
+		if got := regionListError(tt.err); got != tt.want {
+			t.Errorf("regionListError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultMinFree = 500 << 20
+
+	// Below this even small artifacts and lock files fail to write
+	nearZeroFree = 16 << 20
+)
+
+// byteSize is a size in bytes that parses human units such as 500MB or 2GiB;
+// units are binary, matching what df -h reports
+type byteSize uint64
+
+var byteUnits = map[string]uint64{
+	"": 1, "b": 1,
+	"k": 1 << 10, "kb": 1 << 10, "kib": 1 << 10,
+	"m": 1 << 20, "mb": 1 << 20, "mib": 1 << 20,
+	"g": 1 << 30, "gb": 1 << 30, "gib": 1 << 30,
+	"t": 1 << 40, "tb": 1 << 40, "tib": 1 << 40,
+}
+
+func parseByteSize(value string) (byteSize, error) {
+	value = strings.TrimSpace(value)
+	split := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split < 0 {
+		split = len(value)
+	}
+	number, unit := value[:split], strings.ToLower(strings.TrimSpace(value[split:]))
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", unit)
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("expected a size such as 500MB or 2GB")
+	}
+	return byteSize(n * float64(multiplier)), nil
+}
+
+func (s *byteSize) UnmarshalYAML(node *yaml.Node) error {
+	size, err := parseByteSize(node.Value)
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+func (s byteSize) String() string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	value := float64(s)
+	i := 0
+	for ; value >= 1024 && i < len(units)-1; i++ {
+		value /= 1024
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", uint64(s))
+	}
+	return fmt.Sprintf("%.1f %s", value, units[i])
+}
+
+// checkWritable creates, writes and removes a file in dir, which catches
+// read-only mounts and exhausted inodes that a stat would miss
+func checkWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".bcce-doctor-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString("ok"); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func runLocalChecks(cfg *Config) []CheckResult {
+	results := []CheckResult{checkLocalDir(cfg, "Local - Temp Directory", os.TempDir(), "point TMPDIR at a writable directory")}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return append(results, CheckResult{
+			Name:    "Local - Working Directory",
+			Status:  "fail",
+			Message: fmt.Sprintf("Could not determine the working directory: %v", err),
+			Fix:     "cd into an existing directory and re-run",
+		})
+	}
+	return append(results, checkLocalDir(cfg, "Local - Working Directory", wd, "run from a writable directory"))
+}
+
+func checkLocalDir(cfg *Config, name, dir, elsewhere string) CheckResult {
+	start := time.Now()
+	if err := checkWritable(dir); err != nil {
+		return CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("Cannot write to %s: %v", dir, err),
+			Fix:        fmt.Sprintf("Free up space or fix permissions on %s, or %s", dir, elsewhere),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+	}
+
+	free, err := freeSpace(dir)
+	durationMs := time.Since(start).Milliseconds()
+	switch {
+	case err != nil:
+		return CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("%s is writable (free space unknown: %v)", dir, err),
+			DurationMs: durationMs,
+		}
+	case free < nearZeroFree:
+		return CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("%s is almost full (%s free)", dir, free),
+			Fix:        fmt.Sprintf("Free up space on %s, or %s", dir, elsewhere),
+			DurationMs: durationMs,
+		}
+	case free < cfg.MinFree:
+		return CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("%s is writable but only %s is free, below %s", dir, free, cfg.MinFree),
+			Fix:        fmt.Sprintf("Free up space on %s, or lower --min-free", dir),
+			DurationMs: durationMs,
+		}
+	}
+	return CheckResult{
+		Name:       name,
+		Status:     "pass",
+		Message:    fmt.Sprintf("%s is writable (%s free)", dir, free),
+		DurationMs: durationMs,
+	}
+}
+
+// runWorkdirCheck preflights the --workdir directory BCCE writes transcripts
+// and artifacts to. Unlike the temp and working directories it is never
+// created on demand, so a missing one is reported with how to create it.
+func runWorkdirCheck(cfg *Config) []CheckResult {
+	name := "Local - Workdir"
+	dir := cfg.Workdir
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s does not exist, so workflows fail when they first write output", dir),
+			Fix:     fmt.Sprintf("Create it with 'mkdir -p %s', or point --workdir at the configured output directory", dir),
+		}}
+	case errors.Is(err, fs.ErrPermission):
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Cannot access %s: %v", dir, err),
+			Fix:     fmt.Sprintf("Grant this user search (x) permission on every parent directory of %s", dir),
+		}}
+	case err != nil:
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Cannot stat %s: %v", dir, err),
+			Fix:     fmt.Sprintf("Check that %s is on a mounted, healthy filesystem", dir),
+		}}
+	case !info.IsDir():
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s is a file, not a directory", dir),
+			Fix:     fmt.Sprintf("Move %s aside and create a directory in its place, or point --workdir at the output directory", dir),
+		}}
+	}
+	if err := checkWritable(dir); errors.Is(err, fs.ErrPermission) {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s exists but is not writable by this user (mode %s): %v", dir, info.Mode().Perm(), err),
+			Fix:     fmt.Sprintf("Run 'chmod u+w %s', or 'chown' it to the user that runs the workflows", dir),
+		}}
+	}
+	return []CheckResult{checkLocalDir(cfg, name, dir, "point --workdir at another directory")}
+}
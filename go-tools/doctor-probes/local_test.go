@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  byteSize
+	}{
+		{"1024", 1024},
+		{"500MB", 500 << 20},
+		{"2GiB", 2 << 30},
+		{"1.5g", 3 << 29},
+		{"10 kb", 10 << 10},
+	}
+	for _, tt := range tests {
+		got, err := parseByteSize(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "MB", "5XB", "-1GB"} {
+		if _, err := parseByteSize(value); err == nil {
+			t.Errorf("parseByteSize(%q) succeeded, want error", value)
+		}
+	}
+}
+
+func TestMinFreeYAML(t *testing.T) {
+	var cfg Config
+	if err := yaml.Unmarshal([]byte("min_free: 2GB\n"), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MinFree != 2<<30 {
+		t.Errorf("MinFree = %d, want %d", cfg.MinFree, 2<<30)
+	}
+}
+
+func TestCheckLocalDir(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newConfig()
+
+	if result := checkLocalDir(cfg, "Local - Test", dir, "elsewhere"); result.Status != "pass" {
+		t.Errorf("status = %s (%s), want pass", result.Status, result.Message)
+	}
+
+	cfg.MinFree = 1 << 60
+	if result := checkLocalDir(cfg, "Local - Test", dir, "elsewhere"); result.Status != "warn" {
+		t.Errorf("status with a huge --min-free = %s (%s), want warn", result.Status, result.Message)
+	}
+
+	if result := checkLocalDir(cfg, "Local - Test", dir+"/missing", "elsewhere"); result.Status != "fail" {
+		t.Errorf("status for a missing directory = %s (%s), want fail", result.Status, result.Message)
+	}
+}
+
+func TestWorkdirCheck(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "transcripts")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path, wantStatus, wantFix string
+	}{
+		{dir, "pass", ""},
+		{filepath.Join(dir, "missing"), "fail", "mkdir -p"},
+		{file, "fail", "create a directory"},
+	}
+	for _, tt := range tests {
+		cfg := newConfig()
+		cfg.MinFree = 0
+		cfg.Workdir = tt.path
+		got := runWorkdirCheck(cfg)[0]
+		if got.Status != tt.wantStatus || !strings.Contains(got.Fix, tt.wantFix) {
+			t.Errorf("runWorkdirCheck(%s) = %s %q (fix %q), want %s with a fix containing %q", tt.path, got.Status, got.Message, got.Fix, tt.wantStatus, tt.wantFix)
+		}
+	}
+}
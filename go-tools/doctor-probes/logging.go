@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/smithy-go"
+)
+
+// An IAM role ARN, which CloudWatch delivery assumes to write the log group
+var roleARNPattern = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/.+$`)
+
+// runLoggingChecks reads the region's model invocation logging configuration
+// and checks each destination it names. Bedrock writes the logs itself, so a
+// deleted bucket or a half-filled CloudWatch setting only shows up later as
+// failing invocations or silently missing logs.
+func runLoggingChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	name := "Invocation Logging"
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load AWS config: %v", err),
+			Fix:     "Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+		}}
+	}
+	logger.Debug("getting model invocation logging configuration", "region", region)
+	output, err := newControlClient(cfg, awsCfg).GetModelInvocationLoggingConfiguration(ctx, &bedrock.GetModelInvocationLoggingConfigurationInput{})
+	if err != nil {
+		return []CheckResult{loggingErrorResult(name, region, err)}
+	}
+	return checkLoggingConfig(ctx, cfg, region, output.LoggingConfig, "https://"+cfg.endpointAddr("s3", region))
+}
+
+// checkLoggingConfig reports each configured destination, checking buckets
+// against the S3 endpoint at s3URL
+func checkLoggingConfig(ctx context.Context, cfg *Config, region string, logging *types.LoggingConfig, s3URL string) []CheckResult {
+	if logging == nil || (logging.S3Config == nil && logging.CloudWatchConfig == nil) {
+		return []CheckResult{{
+			Name:    "Invocation Logging",
+			Status:  "pass",
+			Message: fmt.Sprintf("Model invocation logging is off in %s, so there is no destination to check", region),
+		}}
+	}
+	var results []CheckResult
+	if !aws.ToBool(logging.TextDataDeliveryEnabled) && !aws.ToBool(logging.ImageDataDeliveryEnabled) && !aws.ToBool(logging.EmbeddingDataDeliveryEnabled) {
+		results = append(results, CheckResult{
+			Name:    "Invocation Logging",
+			Status:  "warn",
+			Message: fmt.Sprintf("Model invocation logging in %s has a destination but delivers no text, image or embedding data", region),
+			Fix:     "Select the data types to log under Settings > Model invocation logging in the Bedrock console",
+		})
+	}
+	if s3 := logging.S3Config; s3 != nil {
+		results = append(results, checkLoggingBucket(ctx, cfg, region, "S3", s3, s3URL))
+	}
+	if cw := logging.CloudWatchConfig; cw != nil {
+		results = append(results, checkLoggingCloudWatch(region, cw))
+		if cw.LargeDataDeliveryS3Config != nil {
+			results = append(results, checkLoggingBucket(ctx, cfg, region, "CloudWatch large data S3", cw.LargeDataDeliveryS3Config, s3URL))
+		}
+	}
+	return results
+}
+
+func checkLoggingCloudWatch(region string, cw *types.CloudWatchConfig) CheckResult {
+	group, role := aws.ToString(cw.LogGroupName), aws.ToString(cw.RoleArn)
+	name := "Invocation Logging - CloudWatch " + group
+	fix := "Set the log group and a role Bedrock can assume with logs:CreateLogStream and logs:PutLogEvents on it, under Settings > Model invocation logging in the Bedrock console"
+	switch {
+	case group == "":
+		return CheckResult{Name: "Invocation Logging - CloudWatch", Status: "fail", Message: fmt.Sprintf("CloudWatch invocation logging in %s has no log group", region), Fix: fix}
+	case role == "":
+		return CheckResult{Name: name, Status: "fail", Message: fmt.Sprintf("CloudWatch invocation logging to %s has no role for Bedrock to write with", group), Fix: fix}
+	case !roleARNPattern.MatchString(role):
+		return CheckResult{Name: name, Status: "fail", Message: fmt.Sprintf("CloudWatch invocation logging to %s uses %q, which is not an IAM role ARN", group, role), Fix: fix}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("Invocations in %s are logged to CloudWatch log group %s as %s", region, group, role),
+	}
+}
+
+// checkLoggingBucket checks the bucket exists in the logging region. Bedrock
+// only delivers to a bucket in its own region.
+func checkLoggingBucket(ctx context.Context, cfg *Config, region, label string, s3 *types.S3Config, s3URL string) CheckResult {
+	bucket := aws.ToString(s3.BucketName)
+	name := fmt.Sprintf("Invocation Logging - %s %s", label, bucket)
+	if bucket == "" {
+		return CheckResult{
+			Name:    "Invocation Logging - " + label,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s invocation logging in %s has no bucket", label, region),
+			Fix:     "Set the S3 bucket under Settings > Model invocation logging in the Bedrock console",
+		}
+	}
+	destination := "s3://" + bucket
+	if prefix := aws.ToString(s3.KeyPrefix); prefix != "" {
+		destination += "/" + strings.TrimPrefix(prefix, "/")
+	}
+
+	exists, bucketRegion, err := probeBucket(ctx, cfg, s3URL, bucket)
+	logger.Debug("probed logging bucket", "bucket", bucket, "exists", exists, "region", bucketRegion, "error", err)
+	switch {
+	case err != nil:
+		return CheckResult{
+			Name:      name,
+			Status:    "warn",
+			Message:   fmt.Sprintf("Invocations in %s are logged to %s, but S3 could not be reached to confirm the bucket exists: %v", region, destination, err),
+			Fix:       fmt.Sprintf("Confirm the bucket exists with 'aws s3api head-bucket --bucket %s'", bucket),
+			ErrorKind: newProbeError("", err).Kind,
+		}
+	case !exists:
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Invocations in %s are logged to %s, but the bucket does not exist", region, destination),
+			Fix:     fmt.Sprintf("Create the bucket in %s with a policy allowing bedrock.amazonaws.com to s3:PutObject, or point invocation logging at an existing one", region),
+		}
+	case bucketRegion != "" && bucketRegion != region:
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Invocations in %s are logged to %s, but the bucket is in %s; Bedrock only delivers to a bucket in its own region", region, destination, bucketRegion),
+			Fix:     fmt.Sprintf("Point invocation logging in %s at a bucket in %s", region, region),
+		}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("Invocations in %s are logged to %s, which exists", region, destination),
+	}
+}
+
+// probeBucket sends an unsigned HEAD for the bucket. S3 answers 404 for a
+// bucket that doesn't exist and otherwise names the bucket's region, even
+// when it refuses the anonymous request.
+func probeBucket(ctx context.Context, cfg *Config, s3URL, bucket string) (exists bool, region string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s3URL+"/"+bucket, nil)
+	if err != nil {
+		return false, "", err
+	}
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, DialContext: cfg.dialer().DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, "", err
+	}
+	resp.Body.Close()
+	region = resp.Header.Get("X-Amz-Bucket-Region")
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, "", nil
+	case resp.StatusCode < 500 && (region != "" || resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusForbidden):
+		return true, region, nil
+	}
+	return false, "", fmt.Errorf("HEAD %s returned %s", bucket, resp.Status)
+}
+
+// loggingErrorResult explains a GetModelInvocationLoggingConfiguration error
+func loggingErrorResult(name, region string, err error) CheckResult {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException" {
+		return CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Not allowed to read the model invocation logging configuration in %s: %s", region, apiErr.ErrorMessage()),
+			Fix:       "Allow bedrock:GetModelInvocationLoggingConfiguration for this principal",
+			ErrorKind: ErrorKindAuth,
+		}
+	}
+	return CheckResult{
+		Name:      name,
+		Status:    "fail",
+		Message:   fmt.Sprintf("Could not read the model invocation logging configuration in %s: %v", region, err),
+		Fix:       awsErrorFix(err, "Check credentials and network access to Bedrock"),
+		ErrorKind: newProbeError("", err).Kind,
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestCheckLoggingConfig(t *testing.T) {
+	// A stand-in for S3: "logs" exists here, "eu-logs" in another region
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logs":
+			w.Header().Set("X-Amz-Bucket-Region", "us-east-1")
+			w.WriteHeader(http.StatusForbidden)
+		case "/eu-logs":
+			w.Header().Set("X-Amz-Bucket-Region", "eu-west-1")
+			w.WriteHeader(http.StatusMovedPermanently)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	cfg := newConfig()
+
+	tests := []struct {
+		name    string
+		logging *types.LoggingConfig
+		want    []string
+		message string
+	}{
+		{"off", nil, []string{"pass"}, "logging is off"},
+		{"bucket exists", &types.LoggingConfig{TextDataDeliveryEnabled: aws.Bool(true), S3Config: &types.S3Config{BucketName: aws.String("logs"), KeyPrefix: aws.String("bedrock")}}, []string{"pass"}, "s3://logs/bedrock, which exists"},
+		{"no bucket", &types.LoggingConfig{TextDataDeliveryEnabled: aws.Bool(true), S3Config: &types.S3Config{BucketName: aws.String("gone")}}, []string{"fail"}, "does not exist"},
+		{"other region", &types.LoggingConfig{TextDataDeliveryEnabled: aws.Bool(true), S3Config: &types.S3Config{BucketName: aws.String("eu-logs")}}, []string{"fail"}, "is in eu-west-1"},
+		{"no role", &types.LoggingConfig{TextDataDeliveryEnabled: aws.Bool(true), CloudWatchConfig: &types.CloudWatchConfig{LogGroupName: aws.String("bedrock")}}, []string{"fail"}, "no role"},
+		{"bad role", &types.LoggingConfig{TextDataDeliveryEnabled: aws.Bool(true), CloudWatchConfig: &types.CloudWatchConfig{LogGroupName: aws.String("bedrock"), RoleArn: aws.String("BedrockLogging")}}, []string{"fail"}, "not an IAM role ARN"},
+		{"nothing delivered", &types.LoggingConfig{CloudWatchConfig: &types.CloudWatchConfig{
+			LogGroupName:              aws.String("bedrock"),
+			RoleArn:                   aws.String("arn:aws:iam::123456789012:role/BedrockLogging"),
+			LargeDataDeliveryS3Config: &types.S3Config{BucketName: aws.String("logs")},
+		}}, []string{"warn", "pass", "pass"}, "delivers no text"},
+	}
+	for _, tt := range tests {
+		results := checkLoggingConfig(context.Background(), cfg, "us-east-1", tt.logging, server.URL)
+		var statuses []string
+		for _, result := range results {
+			statuses = append(statuses, result.Status)
+		}
+		if strings.Join(statuses, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: statuses = %v, want %v (%+v)", tt.name, statuses, tt.want, results)
+			continue
+		}
+		if !strings.Contains(results[0].Message, tt.message) {
+			t.Errorf("%s: message = %q, want it to contain %q", tt.name, results[0].Message, tt.message)
+		}
+	}
+}
+
+func TestLoggingErrorResult(t *testing.T) {
+	result := loggingErrorResult("Invocation Logging", "us-east-1", &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized"})
+	if result.Status != "fail" || result.ErrorKind != ErrorKindAuth || !strings.Contains(result.Fix, "bedrock:GetModelInvocationLoggingConfiguration") {
+		t.Errorf("loggingErrorResult() = %+v, want an IAM fail naming the action", result)
+	}
+}
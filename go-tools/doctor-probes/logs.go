@@ -0,0 +1,18 @@
+package main
+
+import "context"
+
+// CloudWatch Logs, where BCCE workflows ship their telemetry
+var logsService = bedrockService{Name: "CloudWatch Logs", Prefix: "logs"}
+
+// runLogsChecks resolves and connects to the regional CloudWatch Logs endpoint,
+// which hosts can fail to reach even when Bedrock works, silently losing logs
+func runLogsChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	dns := checkServiceDNS(ctx, cfg, region, logsService)
+	if dns.Status == "fail" {
+		// Dialing would only repeat the lookup failure
+		return []CheckResult{dns}
+	}
+	tcp := probeTCPAddress(ctx, cfg, "TCP - "+cfg.endpointLabel(logsService.Name, logsService.Prefix), cfg.endpointAddr(logsService.Prefix, region))
+	return []CheckResult{dns, tcp}
+}
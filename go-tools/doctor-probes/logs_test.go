@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLogsChecks(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	cfg := newConfig()
+	cfg.Timeout = 2 * time.Second
+	cfg.Endpoints = map[string]string{"logs": "localhost:" + portOf(t, listener.Addr())}
+	results := runLogsChecks(context.Background(), cfg, "us-east-1")
+	if len(results) != 2 || results[0].Status != "pass" || results[1].Status != "pass" {
+		t.Fatalf("runLogsChecks = %+v, want DNS and TCP passes", results)
+	}
+
+	cfg.Endpoints["logs"] = "logs.invalid"
+	if results := runLogsChecks(context.Background(), cfg, "us-east-1"); len(results) != 1 || results[0].Status != "fail" {
+		t.Errorf("runLogsChecks with an unresolvable endpoint = %+v, want only the DNS failure", results)
+	}
+}
+
+func portOf(t *testing.T, addr net.Addr) string {
+	t.Helper()
+	_, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return port
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		want     int
+	}{
+		{"empty", nil, exitPass},
+		{"all pass", []string{"pass", "pass"}, exitPass},
+		{"warn only", []string{"warn"}, exitWarn},
+		{"pass and warn", []string{"pass", "warn", "pass"}, exitWarn},
+		{"fail only", []string{"fail"}, exitFail},
+		{"fail before warn", []string{"fail", "warn"}, exitFail},
+		{"warn before fail", []string{"pass", "warn", "fail"}, exitFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results []CheckResult
+			for _, status := range tt.statuses {
+				results = append(results, CheckResult{Name: status, Status: status})
+			}
+			if got := exitCode(results); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.statuses, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGateExitCodeWarningsAsErrors(t *testing.T) {
+	warnOnly := []CheckResult{{Name: "TCP", Status: "warn"}, {Name: "DNS", Status: "pass"}}
+	if got := gateExitCode(exitCode(warnOnly), true); got != exitFail {
+		t.Errorf("warn-only results with --warnings-as-errors exit %d, want %d", got, exitFail)
+	}
+	if got := gateExitCode(exitCode(warnOnly), false); got != exitWarn {
+		t.Errorf("warn-only results exit %d, want %d", got, exitWarn)
+	}
+	if warnOnly[0].Status != "warn" {
+		t.Errorf("status changed to %q, want warn", warnOnly[0].Status)
+	}
+	for _, code := range []int{exitPass, exitFail, exitInterrupted} {
+		if got := gateExitCode(code, true); got != code {
+			t.Errorf("gateExitCode(%d, true) = %d, want it unchanged", code, got)
+		}
+	}
+}
+
+func TestRemapExitCode(t *testing.T) {
+	cfg := newConfig()
+	cfg.ExitWarn = 0
+	cfg.ExitFail = 3
+	tests := []struct {
+		code, want int
+	}{
+		{exitPass, 0},
+		{exitWarn, 0},
+		{exitFail, 3},
+		{exitInterrupted, exitInterrupted},
+	}
+	for _, tt := range tests {
+		if got := cfg.remapExitCode(tt.code); got != tt.want {
+			t.Errorf("remapExitCode(%d) = %d, want %d", tt.code, got, tt.want)
+		}
+	}
+	// Warnings promoted by --warnings-as-errors take the fail code
+	if got := cfg.remapExitCode(gateExitCode(exitWarn, true)); got != 3 {
+		t.Errorf("remapExitCode(gateExitCode(exitWarn, true)) = %d, want 3", got)
+	}
+}
+
+// TestConfigErrorExitCode runs the binary's main in a child process, since
+// the config errors under test exit before any report is written
+func TestConfigErrorExitCode(t *testing.T) {
+	if args, ok := os.LookupEnv("DOCTOR_PROBES_TEST_ARGS"); ok {
+		os.Args = append([]string{"doctor-probes"}, strings.Fields(args)...)
+		main()
+		os.Exit(exitPass)
+	}
+	if testing.Short() {
+		t.Skip("runs child processes")
+	}
+	tests := []struct {
+		args string
+		want int
+	}{
+		{"--no-such-flag", exitConfigError},
+		{"--timeout soon", exitConfigError},
+		{"--config " + t.TempDir() + "/missing.yaml", exitConfigError},
+		{"--regions us-esat-1 --exit-fail 5", exitConfigError},
+		{"no-such-command", exitConfigError},
+		{"history", exitConfigError},
+		// Checks that ran and failed keep the failure code
+		{"--assume-offline --regions us-east-1", exitFail},
+		{"--help", exitPass},
+	}
+	for _, tt := range tests {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestConfigErrorExitCode$")
+		cmd.Env = append(os.Environ(), "DOCTOR_PROBES_TEST_ARGS="+tt.args)
+		err := cmd.Run()
+		code := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			t.Fatalf("running %q: %v", tt.args, err)
+		}
+		if code != tt.want {
+			t.Errorf("doctor-probes %s exited %d, want %d", tt.args, code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// AWS Marketplace Metering, which Bedrock calls for models sold through the
+// Marketplace; it has no FIPS variant
+var marketplaceService = bedrockService{Name: "AWS Marketplace Metering", Prefix: "metering.marketplace"}
+
+// runMarketplaceChecks resolves and connects to the regional Marketplace
+// metering endpoint. When it is blocked, inference on third-party models fails
+// with errors that don't mention the Marketplace at all.
+func runMarketplaceChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	regionalCfg := *cfg
+	regionalCfg.FIPS = false
+	host := regionalCfg.endpointHost(marketplaceService.Prefix, region)
+	// Only models sold through the Marketplace need the endpoint
+	unless := "; ignore this if you only use models that aren't sold through AWS Marketplace"
+
+	dns := checkServiceDNS(ctx, &regionalCfg, region, marketplaceService)
+	if dns.Status == "fail" {
+		dns.Fix = fmt.Sprintf("Check that %s resolves from this host; a DNS firewall or private hosted zone may hide it%s", host, unless)
+		// Dialing would only repeat the lookup failure
+		return []CheckResult{dns}
+	}
+	tcp := probeTCPAddress(ctx, &regionalCfg, "TCP - "+regionalCfg.endpointLabel(marketplaceService.Name, marketplaceService.Prefix), regionalCfg.endpointAddr(marketplaceService.Prefix, region))
+	if tcp.Status != "pass" {
+		tcp.Fix = fmt.Sprintf("Allow outbound TCP 443 to %s, or add a VPC endpoint for com.amazonaws.%s.metering.marketplace%s", host, region, unless)
+	}
+	return []CheckResult{dns, tcp}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarketplaceChecks(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := portOf(t, listener.Addr())
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	cfg := newConfig()
+	cfg.Timeout = 2 * time.Second
+	cfg.Endpoints = map[string]string{"metering.marketplace": "localhost:" + port}
+	results := runMarketplaceChecks(context.Background(), cfg, "us-east-1")
+	if len(results) != 2 || results[0].Status != "pass" || results[1].Status != "pass" {
+		t.Fatalf("runMarketplaceChecks = %+v, want DNS and TCP passes", results)
+	}
+
+	listener.Close()
+	results = runMarketplaceChecks(context.Background(), cfg, "us-east-1")
+	if len(results) != 2 || results[1].Status != "fail" || !strings.Contains(results[1].Fix, "com.amazonaws.us-east-1.metering.marketplace") {
+		t.Errorf("runMarketplaceChecks with nothing listening = %+v, want a TCP failure pointing at the metering endpoint", results)
+	}
+
+	// There is no FIPS metering endpoint, so --fips must not change the host
+	delete(cfg.Endpoints, "metering.marketplace")
+	cfg.FIPS = true
+	cfg.Resolver = "127.0.0.1:" + port
+	if results := runMarketplaceChecks(context.Background(), cfg, "us-east-1"); !strings.Contains(results[0].Message, "metering.marketplace.us-east-1.amazonaws.com") {
+		t.Errorf("runMarketplaceChecks with --fips = %+v, want the non-FIPS host", results[0])
+	}
+}
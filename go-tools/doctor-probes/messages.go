@@ -0,0 +1,71 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Message catalogs for the fixed strings of the human report, one
+// locales/<lang>.json per --lang value. Check names, messages and fixes stay
+// in English: they carry hostnames and error strings that must match logs.
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// messages maps message IDs to format strings in one language
+type messages map[string]string
+
+// languages lists the --lang values with a catalog, sorted
+func languages() []string {
+	entries, _ := localeFiles.ReadDir("locales")
+	var langs []string
+	for _, entry := range entries {
+		langs = append(langs, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+func loadMessages(lang string) (messages, error) {
+	if !slices.Contains(languages(), lang) {
+		return nil, fmt.Errorf("no message catalog for %q (want one of %s)", lang, strings.Join(languages(), ", "))
+	}
+	data, err := localeFiles.ReadFile(path.Join("locales", lang+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var catalog messages
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("locales/%s.json: %w", lang, err)
+	}
+	return catalog, nil
+}
+
+// englishMessages is the fallback for IDs another catalog doesn't translate
+var englishMessages = func() messages {
+	catalog, err := loadMessages("en")
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}()
+
+// text formats message id, falling back to English and then to the ID itself
+// so a missing translation never drops a line. A nil catalog is English.
+func (m messages) text(id string, args ...any) string {
+	format, ok := m[id]
+	if !ok {
+		format, ok = englishMessages[id]
+	}
+	if !ok {
+		format = id
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
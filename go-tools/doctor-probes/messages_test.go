@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMessageCatalogsMatchEnglish(t *testing.T) {
+	for _, lang := range languages() {
+		catalog, err := loadMessages(lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for id, format := range catalog {
+			english, ok := englishMessages[id]
+			if !ok {
+				t.Errorf("%s: %s is not an English message ID", lang, id)
+				continue
+			}
+			// Arguments are passed positionally, so every translation needs the same verbs
+			if got, want := strings.Count(format, "%"), strings.Count(english, "%"); got != want {
+				t.Errorf("%s: %s has %d format verbs, want %d like English", lang, id, got, want)
+			}
+		}
+	}
+}
+
+func TestMessagesFallBackToEnglish(t *testing.T) {
+	catalog := messages{"report.passed": "Todo bien"}
+	if got := catalog.text("report.passed"); got != "Todo bien" {
+		t.Errorf("text(report.passed) = %q, want the translation", got)
+	}
+	if got, want := catalog.text("report.fix", "run it"), "Fix: run it"; got != want {
+		t.Errorf("text(report.fix) = %q, want English %q", got, want)
+	}
+	if got := catalog.text("no.such.id"); got != "no.such.id" {
+		t.Errorf("text(no.such.id) = %q, want the ID", got)
+	}
+}
+
+func TestWriteHumanTranslated(t *testing.T) {
+	msgs, err := loadMessages("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	writeHuman(&buf, []CheckResult{{Name: "DNS - bedrock.us-east-1.amazonaws.com", Status: "fail", Message: "lookup failed", Fix: "Check DNS"}}, false, true, msgs)
+	got := buf.String()
+	for _, want := range []string{"Informe de BCCE Doctor Probes", "Solución: Check DNS", "[FAIL] Se detectaron problemas de conectividad", "DNS - bedrock.us-east-1.amazonaws.com: lookup failed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestLoadMessagesUnknownLang(t *testing.T) {
+	if _, err := loadMessages("xx"); err == nil {
+		t.Error("loadMessages(xx) succeeded, want an error")
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go"
+)
+
+// Cross-region inference profile IDs (e.g. us.anthropic...) are not foundation model IDs
+var inferenceProfilePrefix = regexp.MustCompile(`^(us|eu|apac|us-gov|jp|au|global)\.`)
+
+// newRuntimeClient returns a Bedrock Runtime client honoring any endpoint override
+func newRuntimeClient(cfg *Config, awsCfg aws.Config) *bedrockruntime.Client {
+	return bedrockruntime.NewFromConfig(awsCfg, func(o *bedrockruntime.Options) {
+		if host, port, ok := cfg.endpointOverride("bedrock-runtime"); ok {
+			o.BaseEndpoint = aws.String("https://" + net.JoinHostPort(host, port))
+		}
+	})
+}
+
+// newControlClient returns a Bedrock control plane client honoring any endpoint override
+func newControlClient(cfg *Config, awsCfg aws.Config) *bedrock.Client {
+	return bedrock.NewFromConfig(awsCfg, func(o *bedrock.Options) {
+		if host, port, ok := cfg.endpointOverride("bedrock"); ok {
+			o.BaseEndpoint = aws.String("https://" + net.JoinHostPort(host, port))
+		}
+	})
+}
+
+// invokeEmpty sends InvokeModel with an empty JSON body. Bedrock authorizes the
+// request before rejecting the body with a ValidationException, so no tokens
+// are generated and that error means the model is enabled
+func invokeEmpty(ctx context.Context, cfg *Config, awsCfg aws.Config, modelID string) error {
+	_, err := newRuntimeClient(cfg, awsCfg).InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(modelID),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        []byte("{}"),
+	})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ValidationException" {
+		return nil
+	}
+	return err
+}
+
+// runModelAccessChecks verifies --check-model is offered in the region and
+// enabled for the account. Access is tested with an InvokeModel call whose empty
+// body Bedrock rejects after authorization, so no tokens are generated.
+func runModelAccessChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	modelID := cfg.CheckModel
+	name := "Model Access - " + modelID
+
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load AWS config: %v", err),
+			Fix:     "Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+		})
+		return results
+	}
+
+	if !inferenceProfilePrefix.MatchString(modelID) {
+		control := newControlClient(cfg, awsCfg)
+		logger.Debug("looking up foundation model", "model", modelID, "region", region)
+		// The lookup is advisory, so leave at least half the budget for the access check
+		lookupCtx, cancel := context.WithTimeout(ctx, cfg.Timeout/2)
+		_, err := control.GetFoundationModel(lookupCtx, &bedrock.GetFoundationModelInput{ModelIdentifier: aws.String(modelID)})
+		cancel()
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.ErrorCode() {
+			case "ResourceNotFoundException", "ValidationException":
+				results = append(results, CheckResult{
+					Name:    name,
+					Status:  "fail",
+					Message: fmt.Sprintf("%s is not offered in %s", modelID, region),
+					Fix:     "Check the model ID, or use a region or cross-region inference profile that offers it",
+				})
+				return results
+			}
+		}
+		// Other errors (e.g. no bedrock:GetFoundationModel permission) don't decide access; fall through
+		logger.Debug("foundation model lookup finished", "model", modelID, "error", err)
+	}
+
+	logger.Debug("checking model access", "model", modelID, "region", region)
+	err = invokeEmpty(ctx, cfg, awsCfg, modelID)
+
+	host := cfg.endpointHost("bedrock-runtime", region)
+	var apiErr smithy.APIError
+	switch mismatch, signing := signingMismatch(err, awsCfg.Region, host); {
+	case err == nil:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("%s is enabled for this account in %s", modelID, region),
+		})
+	case signing:
+		results = append(results, CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Could not verify access to %s: %s", modelID, mismatch),
+			Fix:       signingMismatchFix(host),
+			ErrorKind: ErrorKindAuth,
+			reason:    reasonRegion,
+		})
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException":
+		results = append(results, CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Access to %s is denied: %s", modelID, apiErr.ErrorMessage()),
+			Fix:       fmt.Sprintf("Enable %s at %s and allow bedrock:InvokeModel for this principal", modelID, modelAccessURL(region)),
+			ErrorKind: ErrorKindAuth,
+			reason:    reasonModelAccess,
+		})
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "ResourceNotFoundException":
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s was not found in %s", modelID, region),
+			Fix:     "Check the model ID and that it is available in this region",
+		})
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "ThrottlingException":
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("Requests for %s are being throttled, so access could not be confirmed", modelID),
+			Fix:     "Retry later",
+		})
+	default:
+		results = append(results, CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Could not verify access to %s: %v", modelID, err),
+			Fix:       awsErrorFix(err, "Check credentials and network access to Bedrock"),
+			ErrorKind: newProbeError("", err).Kind,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/smithy-go"
+)
+
+// configuredModel returns the model ID Claude Code will use and where it came from
+func (c *Config) configuredModel() (modelID, source string) {
+	if c.Model != "" {
+		return c.Model, "--model"
+	}
+	return os.Getenv("ANTHROPIC_MODEL"), "ANTHROPIC_MODEL"
+}
+
+// closestModel returns the listed model ID nearest to modelID by edit distance
+func closestModel(modelID string, models []string) string {
+	best, bestDistance := "", -1
+	for _, known := range models {
+		if distance := editDistance(strings.ToLower(modelID), strings.ToLower(known)); bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = known, distance
+		}
+	}
+	return best
+}
+
+// matchFoundationModel looks up modelID, less any cross-region inference
+// profile prefix, in the region's foundation models. On a miss it returns the
+// closest listed ID, with the prefix restored, as a suggestion
+func matchFoundationModel(modelID string, models []types.FoundationModelSummary) (summary types.FoundationModelSummary, suggestion string, ok bool) {
+	prefix := inferenceProfilePrefix.FindString(modelID)
+	baseID := strings.TrimPrefix(modelID, prefix)
+
+	ids := make([]string, 0, len(models))
+	for _, model := range models {
+		id := aws.ToString(model.ModelId)
+		if id == baseID {
+			return model, "", true
+		}
+		ids = append(ids, id)
+	}
+	if closest := closestModel(baseID, ids); closest != "" {
+		suggestion = prefix + closest
+	}
+	return types.FoundationModelSummary{}, suggestion, false
+}
+
+// runModelIDChecks validates the configured Claude Code model ID against
+// ListFoundationModels for the region, then confirms it is enabled for the
+// account without generating tokens
+func runModelIDChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	name := "Model ID"
+	modelID, source := cfg.configuredModel()
+	if modelID == "" {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: "No model ID configured",
+			Fix:     "export ANTHROPIC_MODEL=<model ID> or pass --model",
+		})
+		return results
+	}
+
+	// Application inference profiles are account resources, not foundation models
+	if strings.HasPrefix(modelID, "arn:") {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("%s (from %s) is an inference profile ARN; it is not validated against the foundation model list", modelID, source),
+		})
+		return results
+	}
+
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load AWS config: %v", err),
+			Fix:     "Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+		})
+		return results
+	}
+
+	logger.Debug("listing foundation models", "model", modelID, "region", region)
+	output, err := newControlClient(cfg, awsCfg).ListFoundationModels(ctx, &bedrock.ListFoundationModelsInput{})
+	if err != nil {
+		kind := newProbeError("", err).Kind
+		fix := "Allow bedrock:ListFoundationModels for this principal and check network access to Bedrock"
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException" {
+			kind = ErrorKindAuth
+		} else {
+			fix = awsErrorFix(err, fix)
+		}
+		results = append(results, CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Could not list foundation models in %s: %v", region, err),
+			Fix:       fix,
+			ErrorKind: kind,
+		})
+		return results
+	}
+
+	summary, suggestion, ok := matchFoundationModel(modelID, output.ModelSummaries)
+	if !ok {
+		fix := fmt.Sprintf("Check %s against the models listed at %s", source, modelAccessURL(region))
+		if suggestion != "" {
+			fix = fmt.Sprintf("Did you mean %s? Set %s to a model ID offered in %s", suggestion, source, region)
+		}
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s (from %s) is not a foundation model offered in %s", modelID, source, region),
+			Fix:     fix,
+		})
+		return results
+	}
+
+	logger.Debug("checking model access", "model", modelID, "region", region)
+	err = invokeEmpty(ctx, cfg, awsCfg, modelID)
+
+	host := cfg.endpointHost("bedrock-runtime", region)
+	var apiErr smithy.APIError
+	switch mismatch, signing := signingMismatch(err, awsCfg.Region, host); {
+	case signing:
+		results = append(results, CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("%s is offered in %s but access could not be confirmed: %s", modelID, region, mismatch),
+			Fix:       signingMismatchFix(host),
+			ErrorKind: ErrorKindAuth,
+			reason:    reasonRegion,
+		})
+	case err == nil && summary.ModelLifecycle != nil && summary.ModelLifecycle.Status == types.FoundationModelLifecycleStatusLegacy:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("%s (from %s) is enabled in %s but is a legacy model scheduled for retirement", modelID, source, region),
+			Fix:     fmt.Sprintf("Move %s to an active model", source),
+		})
+	case err == nil:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("%s (from %s) is offered and enabled in %s", modelID, source, region),
+		})
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException":
+		results = append(results, CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("%s exists in %s but is not enabled for this account: %s", modelID, region, apiErr.ErrorMessage()),
+			Fix:       fmt.Sprintf("Enable %s at %s and allow bedrock:InvokeModel for this principal", modelID, modelAccessURL(region)),
+			ErrorKind: ErrorKindAuth,
+			reason:    reasonModelAccess,
+		})
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "ResourceNotFoundException":
+		// The foundation model exists, so it's the inference profile that's missing
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("%s is a foundation model in %s but the %s inference profile is not available there", modelID, region, strings.TrimSuffix(inferenceProfilePrefix.FindString(modelID), ".")),
+			Fix:     fmt.Sprintf("Use the inference profile prefix for %s's geography, or the bare model ID", region),
+			reason:  reasonInferenceProfile,
+		})
+	default:
+		results = append(results, CheckResult{
+			Name:      name,
+			Status:    "warn",
+			Message:   fmt.Sprintf("%s is offered in %s but access could not be confirmed: %v", modelID, region, err),
+			Fix:       awsErrorFix(err, "Check credentials and network access to Bedrock"),
+			ErrorKind: newProbeError("", err).Kind,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+)
+
+func TestMatchFoundationModel(t *testing.T) {
+	var models []types.FoundationModelSummary
+	for _, id := range []string{
+		"anthropic.claude-3-5-sonnet-20240620-v1:0",
+		"anthropic.claude-3-7-sonnet-20250219-v1:0",
+		"anthropic.claude-3-haiku-20240307-v1:0",
+		"amazon.titan-text-lite-v1",
+	} {
+		models = append(models, types.FoundationModelSummary{ModelId: aws.String(id)})
+	}
+
+	tests := []struct {
+		modelID    string
+		found      bool
+		suggestion string
+	}{
+		{"anthropic.claude-3-7-sonnet-20250219-v1:0", true, ""},
+		{"us.anthropic.claude-3-7-sonnet-20250219-v1:0", true, ""},
+		{"anthropic.claude-3-7-sonnet-20250219-v1", false, "anthropic.claude-3-7-sonnet-20250219-v1:0"},
+		{"anthropic.claude-3-5-sonet-20240620-v1:0", false, "anthropic.claude-3-5-sonnet-20240620-v1:0"},
+		{"eu.anthropic.claude-3-haiku-20240307-v2:0", false, "eu.anthropic.claude-3-haiku-20240307-v1:0"},
+	}
+	for _, tt := range tests {
+		summary, suggestion, ok := matchFoundationModel(tt.modelID, models)
+		if ok != tt.found || suggestion != tt.suggestion {
+			t.Errorf("matchFoundationModel(%q) = %q, %v; want %q, %v", tt.modelID, suggestion, ok, tt.suggestion, tt.found)
+		}
+		if ok && aws.ToString(summary.ModelId) != inferenceProfilePrefix.ReplaceAllString(tt.modelID, "") {
+			t.Errorf("matchFoundationModel(%q) matched %s", tt.modelID, aws.ToString(summary.ModelId))
+		}
+	}
+
+	if _, suggestion, ok := matchFoundationModel("anthropic.claude", nil); ok || suggestion != "" {
+		t.Errorf("matchFoundationModel with no models = %q, %v; want no match and no suggestion", suggestion, ok)
+	}
+}
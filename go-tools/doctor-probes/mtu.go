@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Request sizes tried in order; anything above one full segment exercises PMTU discovery
+var mtuProbeSizes = []int{256, 576, 1024, 1280, 1400, 1460, 2048, 4096, 8000}
+
+const (
+	// Approximate per-packet overhead: IPv4 (20) + TCP (20) + TLS record (29)
+	mtuPacketOverhead = 69
+	mtuWarnThreshold  = 1400
+)
+
+// sendPaddedRequest sends one HTTP request padded to size bytes over a fresh TLS
+// connection and waits for the status line
+func sendPaddedRequest(ctx context.Context, address, serverName string, size int) error {
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: serverName}}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	request := fmt.Sprintf("HEAD / HTTP/1.1\r\nHost: %s\r\nConnection: close\r\nX-Bcce-Padding: ", serverName)
+	padding := max(size-len(request)-4, 0)
+	request += strings.Repeat("x", padding) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		return err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// runMTUChecks looks for Path MTU black holes: small requests get through but
+// larger ones stall because fragmentation-needed ICMP is being dropped
+func runMTUChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	name := "MTU - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	host := cfg.endpointHost("bedrock-runtime", region)
+	address := cfg.endpointAddr("bedrock-runtime", region)
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	largest := 0
+	var stalled error
+	stalledSize := 0
+	for _, size := range mtuProbeSizes {
+		logger.Debug("sending padded request", "address", address, "size", size)
+		if err := sendPaddedRequest(ctx, address, host, size); err != nil {
+			stalled, stalledSize = err, size
+			break
+		}
+		largest = size
+	}
+	durationMs := time.Since(start).Milliseconds()
+
+	switch {
+	case largest == 0:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("Even a %d byte request to %s failed: %v", mtuProbeSizes[0], address, stalled),
+			Fix:        "Fix basic TCP/TLS connectivity first (see the TCP and TLS checks)",
+			DurationMs: durationMs,
+		})
+	case stalled == nil:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("Requests up to %d bytes to %s completed; no PMTU black hole detected", largest, address),
+			DurationMs: durationMs,
+		})
+	default:
+		estimate := largest + mtuPacketOverhead
+		status := "pass"
+		fix := ""
+		if estimate < mtuWarnThreshold {
+			status = "warn"
+			fix = "Large requests may hang; clamp TCP MSS on the VPN/tunnel interface or fix the jumbo-frame/MTU mismatch along the path"
+		}
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     status,
+			Message:    fmt.Sprintf("%d byte requests to %s completed but %d bytes stalled (%v); effective path MTU is roughly %d", largest, address, stalledSize, stalled, estimate),
+			Fix:        fix,
+			DurationMs: durationMs,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// dialContext dials for the connectivity probes; tests swap it out
+var dialContext = func(ctx context.Context, dialer *net.Dialer, network, address string) (net.Conn, error) {
+	return dialer.DialContext(ctx, network, address)
+}
+
+// netErrorClass describes why a connection attempt failed in terms an operator can act on
+type netErrorClass struct {
+	Kind  string
+	Label string
+	Fix   string
+}
+
+var (
+	netErrorTimeout = netErrorClass{
+		Kind:  "timeout",
+		Label: "timed out",
+		Fix:   "Packets are being dropped silently; check that firewall rules, security groups and NACLs allow outbound TCP 443 to AWS",
+	}
+	netErrorRefused = netErrorClass{
+		Kind:  "refused",
+		Label: "connection refused",
+		Fix:   "The host answered but nothing is listening on that port; check the endpoint URL and port, or the proxy or VPC endpoint in the path",
+	}
+	netErrorUnreachable = netErrorClass{
+		Kind:  "unreachable",
+		Label: "no route to host",
+		Fix:   "There is no route to AWS from this machine; check the default gateway, VPN split-tunnel routes, or the subnet route table",
+	}
+	netErrorReset = netErrorClass{
+		Kind:  "reset",
+		Label: "connection reset",
+		Fix:   "A firewall or proxy reset the connection; check its policy for *.amazonaws.com",
+	}
+	netErrorOther = netErrorClass{
+		Kind:  "other",
+		Label: "connection failed",
+		Fix:   "Check that firewall rules and security groups allow outbound TCP 443 to AWS",
+	}
+)
+
+// classifyNetError maps a dial or handshake error to a class; the boolean is false for non-network errors
+// such as certificate failures
+func classifyNetError(err error) (netErrorClass, bool) {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return netErrorClass{}, false
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return netErrorTimeout, true
+	case isErrno(err, refusedErrnos):
+		return netErrorRefused, true
+	case isErrno(err, unreachableErrnos):
+		return netErrorUnreachable, true
+	case isErrno(err, resetErrnos):
+		return netErrorReset, true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return netErrorOther, true
+	}
+	return netErrorClass{}, false
+}
+
+// isErrno reports whether err wraps any of the platform's errno values
+func isErrno(err error, errnos []syscall.Errno) bool {
+	for _, errno := range errnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
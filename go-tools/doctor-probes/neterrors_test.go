@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"testing"
+)
+
+// timeoutError mimics the net.Error a dialer returns when its deadline passes
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func dialFailure(err error) error {
+	return &net.OpError{Op: "dial", Net: "tcp", Err: err}
+}
+
+func TestTCPFailureClassification(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		class netErrorClass
+		kind  string
+	}{
+		{"timeout", dialFailure(timeoutError{}), netErrorTimeout, ErrorKindTimeout},
+		{"refused", dialFailure(os.NewSyscallError("connect", refusedErrnos[0])), netErrorRefused, ErrorKindTCP},
+		{"network unreachable", dialFailure(os.NewSyscallError("connect", unreachableErrnos[0])), netErrorUnreachable, ErrorKindTCP},
+		{"host unreachable", dialFailure(os.NewSyscallError("connect", unreachableErrnos[1])), netErrorUnreachable, ErrorKindTCP},
+		{"reset", dialFailure(os.NewSyscallError("read", resetErrnos[0])), netErrorReset, ErrorKindTCP},
+	}
+
+	original := dialContext
+	t.Cleanup(func() { dialContext = original })
+
+	cfg := newConfig()
+	cfg.Endpoints = map[string]string{"bedrock-runtime": "127.0.0.1"}
+	cfg.Services = []string{"runtime"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialContext = func(ctx context.Context, _ *net.Dialer, network, address string) (net.Conn, error) {
+				return nil, tt.err
+			}
+
+			results := runTCPChecks(context.Background(), cfg, "us-east-1")
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Status != "fail" {
+				t.Errorf("status = %s, want fail", result.Status)
+			}
+			if !strings.Contains(result.Message, tt.class.Label) {
+				t.Errorf("message %q does not mention %q", result.Message, tt.class.Label)
+			}
+			if result.Fix != tt.class.Fix {
+				t.Errorf("fix = %q, want %q", result.Fix, tt.class.Fix)
+			}
+			if result.ErrorKind != tt.kind {
+				t.Errorf("error kind = %q, want %q", result.ErrorKind, tt.kind)
+			}
+		})
+	}
+}
+
+func TestClassifyNetErrorIgnoresNonNetworkErrors(t *testing.T) {
+	if class, ok := classifyNetError(os.ErrNotExist); ok {
+		t.Errorf("classifyNetError(ErrNotExist) = %+v, want no class", class)
+	}
+	if _, ok := classifyNetError(nil); ok {
+		t.Error("classifyNetError(nil) returned a class")
+	}
+}
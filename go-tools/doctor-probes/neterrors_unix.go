@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+var (
+	refusedErrnos     = []syscall.Errno{syscall.ECONNREFUSED}
+	unreachableErrnos = []syscall.Errno{syscall.ENETUNREACH, syscall.EHOSTUNREACH}
+	resetErrnos       = []syscall.Errno{syscall.ECONNRESET}
+)
@@ -0,0 +1,12 @@
+//go:build windows
+
+package main
+
+import "syscall"
+
+// Winsock reports connection failures with its own codes, most of which the syscall package doesn't name
+var (
+	refusedErrnos     = []syscall.Errno{10061}        // WSAECONNREFUSED
+	unreachableErrnos = []syscall.Errno{10051, 10065} // WSAENETUNREACH, WSAEHOSTUNREACH
+	resetErrnos       = []syscall.Errno{syscall.WSAECONNRESET}
+)
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+)
+
+// Enough for a few hundred concurrent streaming connections plus the files a
+// workflow keeps open; many distributions still default the soft limit to 1024
+const defaultMinOpenFiles = 4096
+
+// errNoFileLimit is returned by openFileLimit where the platform has no such limit
+var errNoFileLimit = errors.New("no per-process open file limit on this platform")
+
+// runOpenFilesCheck warns when the soft open file limit is below
+// --min-open-files. Every Bedrock stream holds a socket, so a low ulimit -n
+// surfaces mid-run as "too many open files" rather than at startup.
+func runOpenFilesCheck(cfg *Config) CheckResult {
+	name := "Open Files - ulimit -n"
+	soft, hard, err := openFileLimit()
+	return openFilesResult(name, cfg.MinOpenFiles, soft, hard, err)
+}
+
+func openFilesResult(name string, minimum int, soft, hard uint64, err error) CheckResult {
+	if errors.Is(err, errNoFileLimit) {
+		return CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("Skipped: %s has no per-process open file limit", runtime.GOOS),
+		}
+	}
+	if err != nil {
+		return CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("Could not read the open file limit: %v", err),
+			Fix:     "Check the limit with 'ulimit -n' in the shell that runs BCCE",
+		}
+	}
+
+	limits := fmt.Sprintf("soft %s, hard %s", formatFileLimit(soft), formatFileLimit(hard))
+	if soft >= uint64(minimum) {
+		return CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("Open file limit is %s, at least %d", limits, minimum),
+		}
+	}
+	fix := fmt.Sprintf("Raise the soft limit with 'ulimit -n %d' in the shell or service that starts BCCE", minimum)
+	switch {
+	case hard >= uint64(minimum):
+	case runtime.GOOS == "darwin":
+		fix = fmt.Sprintf("The hard limit is below %d too; raise it with 'sudo launchctl limit maxfiles %d unlimited', then 'ulimit -n %d'", minimum, minimum, minimum)
+	default:
+		fix = fmt.Sprintf("The hard limit is below %d too; raise nofile as root in /etc/security/limits.conf or the service's LimitNOFILE, then 'ulimit -n %d'", minimum, minimum)
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "warn",
+		Message: fmt.Sprintf("Open file limit is %s, below %d; many concurrent Bedrock streams can fail with \"too many open files\"", limits, minimum),
+		Fix:     fix,
+	}
+}
+
+// formatFileLimit prints RLIM_INFINITY as unlimited, as ulimit does
+func formatFileLimit(limit uint64) string {
+	if limit == math.MaxUint64 || limit == math.MaxInt64 {
+		return "unlimited"
+	}
+	return strconv.FormatUint(limit, 10)
+}
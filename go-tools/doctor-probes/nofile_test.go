@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestOpenFilesResult(t *testing.T) {
+	tests := []struct {
+		soft, hard uint64
+		err        error
+		status     string
+		contains   string
+	}{
+		{65536, math.MaxUint64, nil, "pass", "hard unlimited"},
+		{1024, 524288, nil, "warn", "ulimit -n 4096"},
+		{1024, 1024, nil, "warn", "hard limit is below 4096"},
+		{0, 0, errNoFileLimit, "pass", "Skipped"},
+		{0, 0, errors.New("operation not permitted"), "warn", "Could not read"},
+	}
+	for _, tt := range tests {
+		result := openFilesResult("Open Files", defaultMinOpenFiles, tt.soft, tt.hard, tt.err)
+		text := result.Message + " " + result.Fix
+		if result.Status != tt.status || !strings.Contains(text, tt.contains) {
+			t.Errorf("openFilesResult(%d, %d, %v) = %s %q; want %s containing %q", tt.soft, tt.hard, tt.err, result.Status, text, tt.status, tt.contains)
+		}
+	}
+}
+
+func TestOpenFilesCheck(t *testing.T) {
+	cfg := newConfig()
+	cfg.MinOpenFiles = 1
+	if result := runOpenFilesCheck(cfg); result.Status != "pass" {
+		t.Errorf("runOpenFilesCheck() with a minimum of 1 = %s (%s), want pass", result.Status, result.Message)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package main
+
+import (
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// openFileLimit returns the soft and hard RLIMIT_NOFILE that processes started
+// from here get. The Go runtime raises its own soft limit to the hard one at
+// startup and restores the original in children, so the soft limit comes from
+// a shell, falling back to the process's own when none runs.
+func openFileLimit() (soft, hard uint64, err error) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, 0, err
+	}
+	soft, hard = uint64(limit.Cur), uint64(limit.Max)
+	out, err := exec.Command("/bin/sh", "-c", "ulimit -Sn").Output()
+	if err != nil {
+		logger.Debug("could not read the inherited open file limit", "error", err)
+		return soft, hard, nil
+	}
+	value := strings.TrimSpace(string(out))
+	if value == "unlimited" {
+		soft = math.MaxUint64
+	} else if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+		soft = n
+	}
+	return soft, hard, nil
+}
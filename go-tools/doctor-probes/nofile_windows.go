@@ -0,0 +1,9 @@
+//go:build windows
+
+package main
+
+// openFileLimit reports that Windows has no RLIMIT_NOFILE; handles are
+// limited per process by memory instead
+func openFileLimit() (soft, hard uint64, err error) {
+	return 0, 0, errNoFileLimit
+}
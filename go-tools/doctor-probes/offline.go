@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"slices"
+)
+
+// hasIPv4Route reports whether the host has a route to the public IPv4
+// internet; like hasIPv6Route it sends no packets
+func hasIPv4Route() bool {
+	conn, err := net.Dial("udp4", "8.8.8.8:53")
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// networkOffline reports whether there is no route to the internet at all,
+// e.g. no default gateway, so every network probe is bound to fail
+func networkOffline() bool {
+	return !hasIPv4Route() && !hasIPv6Route()
+}
+
+// offlineRun narrows cfg to the checks tagged offline and returns the one
+// result that stands in for the network checks it skipped
+func offlineRun(cfg *Config, assumed bool) (*Config, CheckResult) {
+	local, _ := taggedChecks([]string{"offline"})
+	narrowed := *cfg
+	narrowed.Checks = nil
+	for _, check := range cfg.Checks {
+		if slices.Contains(local, check) {
+			narrowed.Checks = append(narrowed.Checks, check)
+		}
+	}
+
+	reason := "this host has no IPv4 or IPv6 route to the internet (no default gateway)"
+	if assumed {
+		reason = "--assume-offline is set"
+	}
+	return &narrowed, CheckResult{
+		Name:     "Network",
+		Status:   "fail",
+		Message:  fmt.Sprintf("No network connectivity detected: %s; skipped %d network checks", reason, len(cfg.Checks)-len(narrowed.Checks)),
+		Fix:      "Connect to a network or VPN and check the default route ('ip route' or 'route print'), then run the checks again",
+		Category: "network",
+		Code:     codeNetworkOffline,
+	}
+}
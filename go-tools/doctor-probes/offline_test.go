@@ -0,0 +1,23 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOfflineRun(t *testing.T) {
+	cfg := newConfig()
+	cfg.Checks = []string{"region", "dns", "tcp", "tls", "proxy", "local"}
+	narrowed, check := offlineRun(cfg, true)
+
+	if want := []string{"region", "proxy", "local"}; !slices.Equal(narrowed.Checks, want) {
+		t.Errorf("offline checks = %v, want %v", narrowed.Checks, want)
+	}
+	if len(cfg.Checks) != 6 {
+		t.Errorf("offlineRun modified the original config: %v", cfg.Checks)
+	}
+	if check.Status != "fail" || !strings.Contains(check.Message, "skipped 3 network checks") {
+		t.Errorf("offline result = %s %q, want a failure counting 3 skipped checks", check.Status, check.Message)
+	}
+}
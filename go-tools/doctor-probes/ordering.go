@@ -0,0 +1,49 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Orderings --probe-ordering accepts; category is the default
+var probeOrderings = []string{"category", "declared", "fast-first"}
+
+// checkCost ranks checks by how long they usually take, from what the registry
+// declares about them: offline checks read local state, aws-api checks make
+// signed calls with retries, and the rest do a network round trip or two
+func checkCost(check Check) int {
+	switch {
+	case slices.Contains(check.Tags, "offline"):
+		return 0
+	case slices.Contains(check.Tags, "aws-api"):
+		return 2
+	}
+	return 1
+}
+
+// orderResults reorders results already in sortResults order for display.
+// declared follows the registry and fast-first puts cheap checks first; ties
+// keep the category and name order, so the output stays deterministic.
+// Results no registered check produced, such as the region source, sort first.
+func orderResults(results []CheckResult, ordering string) []CheckResult {
+	var key func(CheckResult) int
+	switch ordering {
+	case "declared":
+		key = func(result CheckResult) int {
+			return slices.IndexFunc(checkRegistry, func(check Check) bool { return check.Category == result.check })
+		}
+	case "fast-first":
+		key = func(result CheckResult) int {
+			check, ok := lookupCheck(result.check)
+			if !ok {
+				return -1
+			}
+			return checkCost(check)
+		}
+	default:
+		return results
+	}
+	ordered := slices.Clone(results)
+	slices.SortStableFunc(ordered, func(a, b CheckResult) int { return cmp.Compare(key(a), key(b)) })
+	return ordered
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOrderResults(t *testing.T) {
+	results := sortResults([]CheckResult{
+		{Name: "us-west-2 / TCP - Bedrock Runtime", Category: "network", check: "tcp"},
+		{Name: "Credentials - Identity", Category: "auth", check: "creds"},
+		{Name: "us-east-1 / TCP - Bedrock Runtime", Category: "network", check: "tcp"},
+		{Name: "DNS - Bedrock Runtime", Category: "network", check: "dns"},
+		{Name: "AWS_REGION", Category: "config"},
+		{Name: "Env - API_KEY", Category: "config", check: "env"},
+	})
+	names := func(results []CheckResult) []string {
+		var names []string
+		for _, result := range results {
+			names = append(names, result.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		ordering string
+		want     []string
+	}{
+		{"category", []string{"Credentials - Identity", "AWS_REGION", "Env - API_KEY", "DNS - Bedrock Runtime", "us-east-1 / TCP - Bedrock Runtime", "us-west-2 / TCP - Bedrock Runtime"}},
+		{"declared", []string{"AWS_REGION", "DNS - Bedrock Runtime", "us-east-1 / TCP - Bedrock Runtime", "us-west-2 / TCP - Bedrock Runtime", "Credentials - Identity", "Env - API_KEY"}},
+		{"fast-first", []string{"AWS_REGION", "Env - API_KEY", "DNS - Bedrock Runtime", "us-east-1 / TCP - Bedrock Runtime", "us-west-2 / TCP - Bedrock Runtime", "Credentials - Identity"}},
+	}
+	for _, tt := range tests {
+		if got := names(orderResults(results, tt.ordering)); !slices.Equal(got, tt.want) {
+			t.Errorf("orderResults(%s) = %q\nwant %q", tt.ordering, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Collectors answer quickly or not at all; don't hold up the report for one
+const otelExportTimeout = 10 * time.Second
+
+// The OTLP/HTTP JSON encoding of a trace export request, trimmed to the
+// fields the probes fill in. Hand-rolled so the tool doesn't pull in the
+// OpenTelemetry SDK for one POST.
+type otlpExport struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID      string          `json:"traceId"`
+	SpanID       string          `json:"spanId"`
+	ParentSpanID string          `json:"parentSpanId,omitempty"`
+	Name         string          `json:"name"`
+	Kind         int             `json:"kind"`
+	Start        string          `json:"startTimeUnixNano"`
+	End          string          `json:"endTimeUnixNano"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	Status       otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	String *string `json:"stringValue,omitempty"`
+	Int    *string `json:"intValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// OTLP span kind and status codes
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{String: &value}}
+}
+
+// intAttribute encodes an int64 as a string, as OTLP JSON requires
+func intAttribute(key string, value int64) otlpAttribute {
+	encoded := strconv.FormatInt(value, 10)
+	return otlpAttribute{Key: key, Value: otlpValue{Int: &encoded}}
+}
+
+func randomHex(n int) string {
+	id := make([]byte, n)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// buildTrace makes one trace for a run that started at start and took
+// elapsed: a root span for the run and a child span per check. Checks only
+// record how long they took, so every child starts with the run.
+func buildTrace(results []CheckResult, start time.Time, elapsed time.Duration) otlpExport {
+	traceID := randomHex(16)
+	root := otlpSpan{
+		TraceID: traceID,
+		SpanID:  randomHex(8),
+		Name:    "bcce doctor probes",
+		Kind:    otlpSpanKindInternal,
+		Start:   unixNano(start),
+		End:     unixNano(start.Add(elapsed)),
+		Status:  otlpStatus{Code: otlpStatusOK},
+	}
+	spans := []otlpSpan{root}
+	for _, result := range results {
+		span := otlpSpan{
+			TraceID:      traceID,
+			SpanID:       randomHex(8),
+			ParentSpanID: root.SpanID,
+			Name:         result.Name,
+			Kind:         otlpSpanKindInternal,
+			Start:        unixNano(start),
+			End:          unixNano(start.Add(time.Duration(result.DurationMs) * time.Millisecond)),
+			Attributes: []otlpAttribute{
+				stringAttribute("check.name", result.Name),
+				stringAttribute("check.status", result.Status),
+				intAttribute("check.duration_ms", result.DurationMs),
+			},
+		}
+		for _, attribute := range []struct{ key, value string }{
+			{"check.region", result.Region},
+			{"check.category", result.Category},
+			{"check.error_kind", result.ErrorKind},
+			{"check.code", result.Code},
+		} {
+			if attribute.value != "" {
+				span.Attributes = append(span.Attributes, stringAttribute(attribute.key, attribute.value))
+			}
+		}
+		// Warnings keep the status unset so only failures count as span errors
+		switch result.Status {
+		case "pass":
+			span.Status = otlpStatus{Code: otlpStatusOK}
+		case "fail":
+			span.Status = otlpStatus{Code: otlpStatusError, Message: result.Message}
+			spans[0].Status = otlpStatus{Code: otlpStatusError}
+		}
+		spans = append(spans, span)
+	}
+	return otlpExport{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			stringAttribute("service.name", "bcce-doctor-probes"),
+			stringAttribute("service.version", version),
+		}},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "bcce/go-tools/doctor-probes", Version: version}, Spans: spans}},
+	}}}
+}
+
+// otelTracesURL appends the OTLP traces path to an endpoint given without
+// one, as OTEL_EXPORTER_OTLP_ENDPOINT does
+func otelTracesURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || strings.TrimSuffix(u.Path, "/") != "" {
+		return endpoint
+	}
+	u.Path = "/v1/traces"
+	return u.String()
+}
+
+// otelHeaders parses OTEL_EXPORTER_OTLP_HEADERS, a comma-separated list of
+// url-encoded key=value pairs that collectors commonly use for auth
+func otelHeaders(value string) (http.Header, error) {
+	headers := http.Header{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, "=")
+		key, keyErr := url.QueryUnescape(strings.TrimSpace(key))
+		val, valErr := url.QueryUnescape(strings.TrimSpace(val))
+		if !ok || key == "" || keyErr != nil || valErr != nil {
+			return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_HEADERS entry %q is not key=value", pair)
+		}
+		headers.Set(key, val)
+	}
+	return headers, nil
+}
+
+// exportSpans sends the run's spans to --otel-endpoint. Export is best effort:
+// a failure comes back as a warning to add to the report, and nil means the
+// collector accepted the spans.
+func exportSpans(ctx context.Context, cfg *Config, results []CheckResult, start time.Time, elapsed time.Duration) *CheckResult {
+	name := "OpenTelemetry - " + webhookHost(cfg.OTelEndpoint)
+	began := time.Now()
+	err := sendSpans(ctx, cfg.OTelEndpoint, buildTrace(results, start, elapsed))
+	logger.Debug("OTLP export finished", "endpoint", cfg.OTelEndpoint, "spans", len(results)+1, "error", err)
+	if err == nil {
+		return nil
+	}
+	return &CheckResult{
+		Name:       name,
+		Status:     "warn",
+		Message:    fmt.Sprintf("Failed to export spans: %v", err),
+		Fix:        "Check that the collector accepts OTLP over HTTP (usually port 4318) from here, and any auth in OTEL_EXPORTER_OTLP_HEADERS",
+		Category:   "config",
+		ErrorKind:  newProbeError("", err).Kind,
+		DurationMs: time.Since(began).Milliseconds(),
+	}
+}
+
+func sendSpans(ctx context.Context, endpoint string, export otlpExport) error {
+	headers, err := otelHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, otelExportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, otelTracesURL(endpoint), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = headers
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}}
+	resp, err := client.Do(req)
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("POST %s: %w", webhookHost(endpoint), urlErr.Err)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("POST %s returned %s", webhookHost(endpoint), resp.Status)
+	}
+	return nil
+}
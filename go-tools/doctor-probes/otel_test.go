@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExportSpans(t *testing.T) {
+	var got otlpExport
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding OTLP body: %v", err)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer%20secret")
+
+	results := []CheckResult{
+		{Name: "DNS - Bedrock Runtime", Status: "pass", Region: "us-east-1", DurationMs: 12},
+		{Name: "TCP 443 - Bedrock Runtime", Status: "fail", Region: "us-east-1", Code: "BCCE-TCP-002", Message: "timed out"},
+	}
+	cfg := newConfig()
+	cfg.OTelEndpoint = server.URL
+
+	start := time.Unix(1700000000, 0)
+	if check := exportSpans(context.Background(), cfg, results, start, time.Second); check != nil {
+		t.Fatalf("exportSpans() = %+v, want exported", check)
+	}
+	if path != "/v1/traces" || auth != "Bearer secret" {
+		t.Errorf("POST %s with Authorization %q, want /v1/traces with the OTEL_EXPORTER_OTLP_HEADERS value", path, auth)
+	}
+	if len(got.ResourceSpans) != 1 || len(got.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("export = %+v, want one resource and scope", got)
+	}
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want a root and one per check", len(spans))
+	}
+	root, dns, tcp := spans[0], spans[1], spans[2]
+	if dns.ParentSpanID != root.SpanID || dns.TraceID != root.TraceID || len(root.TraceID) != 32 || len(dns.SpanID) != 16 {
+		t.Errorf("spans %+v and %+v, want a child of the root in one trace", root, dns)
+	}
+	if dns.Start != "1700000000000000000" || dns.End != "1700000000012000000" {
+		t.Errorf("DNS span runs %s to %s, want the run start plus its duration", dns.Start, dns.End)
+	}
+	attributes := map[string]string{}
+	for _, attribute := range tcp.Attributes {
+		if attribute.Value.String != nil {
+			attributes[attribute.Key] = *attribute.Value.String
+		}
+	}
+	if attributes["check.name"] != "TCP 443 - Bedrock Runtime" || attributes["check.status"] != "fail" || attributes["check.region"] != "us-east-1" || attributes["check.code"] != "BCCE-TCP-002" {
+		t.Errorf("TCP span attributes = %v", attributes)
+	}
+	if tcp.Status.Code != otlpStatusError || root.Status.Code != otlpStatusError || dns.Status.Code != otlpStatusOK {
+		t.Errorf("statuses root=%d dns=%d tcp=%d, want the failure to mark the root", root.Status.Code, dns.Status.Code, tcp.Status.Code)
+	}
+
+	server.Close()
+	check := exportSpans(context.Background(), cfg, results, start, time.Second)
+	if check == nil || check.Status != "warn" || check.Category != "config" {
+		t.Fatalf("exportSpans() = %+v, want a warning when the collector is down", check)
+	}
+}
+
+func TestOTelTracesURL(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"http://collector:4318":              "http://collector:4318/v1/traces",
+		"http://collector:4318/":             "http://collector:4318/v1/traces",
+		"https://otlp.example.com/v1/traces": "https://otlp.example.com/v1/traces",
+		"https://otlp.example.com/custom":    "https://otlp.example.com/custom",
+	} {
+		if got := otelTracesURL(endpoint); got != want {
+			t.Errorf("otelTracesURL(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+	if _, err := otelHeaders("api-key"); err == nil {
+		t.Error("otelHeaders() accepted an entry without =")
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// nonPassing filters results down to warnings and failures
+func nonPassing(results []CheckResult) []CheckResult {
+	filtered := []CheckResult{}
+	for _, result := range results {
+		if result.Status != "pass" {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// plainOutput reports whether the human report should avoid emoji and ANSI
+// escapes: when --no-color or NO_COLOR is set, or stdout is not a terminal
+func plainOutput(noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+	return !stdoutIsTerminal()
+}
+
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func statusIcon(status string, plain bool) string {
+	switch status {
+	case "warn":
+		if plain {
+			return "[WARN]"
+		}
+		return "⚠️"
+	case "fail":
+		if plain {
+			return "[FAIL]"
+		}
+		return "❌"
+	default:
+		if plain {
+			return "[PASS]"
+		}
+		return "✅"
+	}
+}
+
+// sortResults returns results ordered by category and then name. Checks run
+// concurrently, so every format renders this order rather than completion order.
+func sortResults(results []CheckResult) []CheckResult {
+	sorted := slices.Clone(results)
+	slices.SortStableFunc(sorted, func(a, b CheckResult) int {
+		return cmp.Or(cmp.Compare(a.Category, b.Category), cmp.Compare(a.Name, b.Name))
+	})
+	return sorted
+}
+
+// newProbeOutput builds the report every renderer takes, in sortResults order
+func newProbeOutput(results []CheckResult, regions []string) ProbeOutput {
+	results = sortResults(results)
+	return ProbeOutput{
+		Checks:      results,
+		ByCategory:  byCategory(results),
+		Summary:     newSummary(results, regions),
+		Environment: currentEnvironment(),
+	}
+}
+
+// overallResult is the synthetic last check that gives renderers a single
+// headline line. It is derived from summary, which doesn't count it.
+func overallResult(summary *Summary) CheckResult {
+	result := CheckResult{Name: "Overall", Status: "pass", Category: "overall"}
+	noun := "checks"
+	if summary.Total == 1 {
+		noun = "check"
+	}
+	switch {
+	case summary.Total == 0:
+		result.Message = "No checks ran"
+	case summary.Fail > 0:
+		result.Status = "fail"
+		result.Message = fmt.Sprintf("%d of %d %s failed", summary.Fail, summary.Total, noun)
+		if summary.Warn > 0 {
+			result.Message += fmt.Sprintf(", %d warned", summary.Warn)
+		}
+	case summary.Warn > 0:
+		result.Status = "warn"
+		result.Message = fmt.Sprintf("%d of %d %s warned", summary.Warn, summary.Total, noun)
+	default:
+		result.Message = fmt.Sprintf("All %d %s passed", summary.Total, noun)
+	}
+	return result
+}
+
+// groupByCategory splits results into runs of one category each, ordered by
+// each category's first result and keeping the order within a category
+func groupByCategory(results []CheckResult) [][]CheckResult {
+	var groups [][]CheckResult
+	index := map[string]int{}
+	for _, result := range results {
+		i, ok := index[result.Category]
+		if !ok {
+			i = len(groups)
+			index[result.Category] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], result)
+	}
+	return groups
+}
+
+// byCategory keys results by category for ProbeOutput.ByCategory
+func byCategory(results []CheckResult) map[string][]CheckResult {
+	if len(results) == 0 {
+		return nil
+	}
+	grouped := map[string][]CheckResult{}
+	for _, result := range results {
+		category := result.Category
+		if category == "" {
+			category = "other"
+		}
+		grouped[category] = append(grouped[category], result)
+	}
+	return grouped
+}
+
+// writeHuman renders the interactive report, with its fixed strings from msgs.
+// In quiet mode passing checks are omitted and nothing is written at all when
+// every check passes.
+func writeHuman(w io.Writer, results []CheckResult, quiet, plain bool, msgs messages) {
+	code := exitCode(results)
+	// The footer agrees with the Overall line, which need not count every
+	// result, e.g. failures in regions --require-regions excuses
+	if n := len(results); n > 0 && results[n-1].Name == "Overall" && results[n-1].Category == "overall" {
+		code = exitCode(results[n-1:])
+	}
+	if quiet {
+		if code == exitPass {
+			return
+		}
+		results = nonPassing(results)
+	} else {
+		if plain {
+			fmt.Fprintln(w, msgs.text("report.title"))
+		} else {
+			fmt.Fprintln(w, "🩺", msgs.text("report.title"))
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Quiet output stays a bare list of problems in run order
+	groups := [][]CheckResult{results}
+	if !quiet {
+		groups = groupByCategory(results)
+	}
+	for i, group := range groups {
+		if !quiet && group[0].Category != "" {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "%s%s\n", strings.ToUpper(group[0].Category[:1]), group[0].Category[1:])
+		}
+		writeHumanResults(w, group, plain, msgs)
+	}
+
+	fmt.Fprintln(w)
+
+	switch code {
+	case exitFail:
+		fmt.Fprintln(w, statusIcon("fail", plain), msgs.text("report.failed"))
+	case exitWarn:
+		// The warning emoji renders narrow in most terminals, so pad it
+		if plain {
+			fmt.Fprintln(w, statusIcon("warn", plain), msgs.text("report.warned"))
+		} else {
+			fmt.Fprintln(w, "⚠️ ", msgs.text("report.warned"))
+		}
+	default:
+		fmt.Fprintln(w, statusIcon("pass", plain), msgs.text("report.passed"))
+	}
+}
+
+// writeFileAtomic replaces path via a temp file and rename so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func writeHumanResults(w io.Writer, results []CheckResult, plain bool, msgs messages) {
+	for _, result := range results {
+		icon := statusIcon(result.Status, plain)
+		if result.DurationMs > 0 {
+			fmt.Fprintf(w, "%s %s: %s (%dms)\n", icon, result.Name, result.Message, result.DurationMs)
+		} else {
+			fmt.Fprintf(w, "%s %s: %s\n", icon, result.Name, result.Message)
+		}
+		if result.Fix != "" {
+			fmt.Fprintf(w, "   %s\n", msgs.text("report.fix", result.Fix))
+		}
+		if result.FixURL != "" {
+			fmt.Fprintf(w, "   %s\n", msgs.text("report.see", result.FixURL))
+		}
+		if result.Code != "" {
+			fmt.Fprintf(w, "   %s\n", msgs.text("report.code", result.Code))
+		}
+	}
+}
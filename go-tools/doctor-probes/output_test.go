@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriteHumanPlain(t *testing.T) {
+	results := []CheckResult{
+		{Name: "DNS - Bedrock Runtime", Status: "pass", Message: "resolved", DurationMs: 12},
+		{Name: "TCP - Bedrock Runtime", Status: "warn", Message: "slow"},
+		{Name: "TLS - Bedrock Runtime", Status: "fail", Message: "handshake failed", Fix: "check proxy"},
+	}
+
+	var buf bytes.Buffer
+	writeHuman(&buf, results, false, true, nil)
+	out := buf.String()
+
+	for _, b := range buf.Bytes() {
+		if b >= 0x80 {
+			t.Fatalf("plain output contains non-ASCII byte %#x:\n%s", b, out)
+		}
+		if b == 0x1b {
+			t.Fatalf("plain output contains an ANSI escape:\n%s", out)
+		}
+	}
+	for _, want := range []string{
+		"[PASS] DNS - Bedrock Runtime: resolved (12ms)",
+		"[WARN] TCP - Bedrock Runtime: slow",
+		"[FAIL] TLS - Bedrock Runtime: handshake failed",
+		"   Fix: check proxy",
+		"[FAIL] Connectivity issues detected",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plain output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteHumanPlainQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	writeHuman(&buf, []CheckResult{{Name: "DNS", Status: "pass"}}, true, true, nil)
+	if buf.Len() != 0 {
+		t.Errorf("quiet output for passing run = %q, want empty", buf.String())
+	}
+
+	buf.Reset()
+	writeHuman(&buf, []CheckResult{{Name: "DNS", Status: "pass"}, {Name: "TCP", Status: "warn", Message: "slow"}}, true, true, nil)
+	if strings.Contains(buf.String(), "DNS") {
+		t.Errorf("quiet output includes passing check:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "[WARN] Some warnings detected") {
+		t.Errorf("quiet output missing warning summary:\n%s", buf.String())
+	}
+}
+
+func TestPlainOutputNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if !plainOutput(false) {
+		t.Error("plainOutput(false) = false with NO_COLOR set, want true")
+	}
+	t.Setenv("NO_COLOR", "")
+	if !plainOutput(true) {
+		t.Error("plainOutput(true) = false, want true")
+	}
+}
+
+func TestPlainOutputNonTTY(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	os.Unsetenv("NO_COLOR")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	if !plainOutput(false) {
+		t.Error("plainOutput(false) = false with stdout on a pipe, want true")
+	}
+}
+
+func TestWriteHumanGroupsByCategory(t *testing.T) {
+	results := []CheckResult{
+		{Name: "AWS_REGION", Status: "pass", Message: "us-east-1", Category: "config"},
+		{Name: "DNS - Bedrock Runtime", Status: "pass", Message: "resolved", Category: "network"},
+		{Name: "Credentials - STS", Status: "fail", Message: "expired", Category: "auth"},
+		{Name: "TCP - Bedrock Runtime", Status: "warn", Message: "slow", Category: "network"},
+	}
+
+	var buf bytes.Buffer
+	writeHuman(&buf, results, false, true, nil)
+	want := "BCCE Doctor Probes Report\n\n" +
+		"Config\n[PASS] AWS_REGION: us-east-1\n\n" +
+		"Network\n[PASS] DNS - Bedrock Runtime: resolved\n[WARN] TCP - Bedrock Runtime: slow\n\n" +
+		"Auth\n[FAIL] Credentials - STS: expired\n\n"
+	if out := buf.String(); !strings.HasPrefix(out, want) {
+		t.Errorf("grouped output =\n%s\nwant prefix\n%s", out, want)
+	}
+
+	grouped := byCategory(append(results, CheckResult{Name: "plugin", Status: "pass"}))
+	if len(grouped["network"]) != 2 || len(grouped["auth"]) != 1 || len(grouped["other"]) != 1 {
+		t.Errorf("byCategory() = %v", grouped)
+	}
+}
+
+func TestOverallResult(t *testing.T) {
+	tests := []struct {
+		results     []CheckResult
+		wantStatus  string
+		wantMessage string
+	}{
+		{nil, "pass", "No checks ran"},
+		{[]CheckResult{{Status: "pass"}}, "pass", "All 1 check passed"},
+		{[]CheckResult{{Status: "pass"}, {Status: "warn"}, {Status: "pass"}}, "warn", "1 of 3 checks warned"},
+		{[]CheckResult{{Status: "fail"}, {Status: "warn"}, {Status: "fail"}, {Status: "pass"}}, "fail", "2 of 4 checks failed, 1 warned"},
+	}
+	for _, tt := range tests {
+		got := overallResult(newSummary(tt.results, nil))
+		if got.Name != "Overall" || got.Status != tt.wantStatus || got.Message != tt.wantMessage {
+			t.Errorf("overallResult(%v) = %s %q, want %s %q", tt.results, got.Status, got.Message, tt.wantStatus, tt.wantMessage)
+		}
+	}
+}
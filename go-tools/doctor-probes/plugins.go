@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Plugin contract
+//
+// Every executable file directly inside --plugin-dir is run with no arguments
+// and the environment of doctor-probes plus BCCE_REGION (the first region
+// probed) and BCCE_TIMEOUT (the probe timeout as a Go duration). A plugin must
+// finish within the timeout and print to stdout either a single CheckResult
+// object or a ProbeOutput object ({"checks": [...]}). Results missing a name
+// are named after the plugin file; any status other than pass/warn/fail is
+// treated as fail. A plugin that exits non-zero without printing anything, or
+// prints something that is not valid JSON, is reported as a synthetic fail
+// naming the plugin.
+
+// listPlugins returns the executables in dir, in name order
+func listPlugins(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var plugins []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		plugins = append(plugins, filepath.Join(dir, entry.Name()))
+	}
+	return plugins, nil
+}
+
+// parsePluginOutput accepts a ProbeOutput or a single CheckResult
+func parsePluginOutput(data []byte) ([]CheckResult, error) {
+	var output struct {
+		Checks *[]CheckResult `json:"checks"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+	if output.Checks != nil {
+		return *output.Checks, nil
+	}
+	var result CheckResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	if result.Status == "" && result.Name == "" {
+		return nil, errors.New("expected a CheckResult or {\"checks\": [...]}")
+	}
+	return []CheckResult{result}, nil
+}
+
+func runPlugin(ctx context.Context, cfg *Config, region, path string) []CheckResult {
+	plugin := filepath.Base(path)
+	name := "Plugin - " + plugin
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(os.Environ(), "BCCE_REGION="+region, "BCCE_TIMEOUT="+cfg.Timeout.String())
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	logger.Debug("running plugin", "path", path)
+	start := time.Now()
+	runErr := cmd.Run()
+	durationMs := time.Since(start).Milliseconds()
+	logger.Debug("plugin finished", "path", path, "duration", time.Since(start), "error", runErr)
+
+	failure := func(message string) []CheckResult {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			message = fmt.Sprintf("%s: %s", message, detail)
+		}
+		return []CheckResult{{
+			Name:       name,
+			Status:     "fail",
+			Message:    message,
+			Fix:        fmt.Sprintf("Run %s by hand to debug it", path),
+			DurationMs: durationMs,
+		}}
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return failure(fmt.Sprintf("Plugin %s did not finish within %s", plugin, cfg.Timeout))
+	}
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		if runErr != nil {
+			return failure(fmt.Sprintf("Plugin %s failed without reporting a result (%v)", plugin, runErr))
+		}
+		return failure(fmt.Sprintf("Plugin %s printed no result", plugin))
+	}
+
+	results, err := parsePluginOutput(stdout.Bytes())
+	if err != nil {
+		return failure(fmt.Sprintf("Plugin %s printed invalid output: %v", plugin, err))
+	}
+	for i := range results {
+		if results[i].Name == "" {
+			results[i].Name = name
+		}
+		switch results[i].Status {
+		case "pass", "warn", "fail":
+		default:
+			results[i].Message = strings.TrimSpace(fmt.Sprintf("%s (unknown status %q)", results[i].Message, results[i].Status))
+			results[i].Status = "fail"
+		}
+		if results[i].DurationMs == 0 {
+			results[i].DurationMs = durationMs
+		}
+	}
+	return results
+}
+
+// runPluginChecks runs every plugin in parallel and returns their results in plugin name order
+func runPluginChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	plugins, err := listPlugins(cfg.PluginDir)
+	if err != nil {
+		return []CheckResult{{
+			Name:    "Plugins",
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to read plugin directory %s: %v", cfg.PluginDir, err),
+			Fix:     "Check the --plugin-dir path and its permissions",
+		}}
+	}
+
+	pluginResults := make([][]CheckResult, len(plugins))
+	var wg sync.WaitGroup
+	for i, path := range plugins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pluginResults[i] = runPlugin(ctx, cfg, region, path)
+		}()
+	}
+	wg.Wait()
+
+	var results []CheckResult
+	for _, r := range pluginResults {
+		results = append(results, r...)
+	}
+	return results
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Port for plain HTTP; a variable so tests can listen somewhere unprivileged
+var plainHTTPPort = "80"
+
+// explainHTTPSBlocked dials port 80 on an address that refused 443. Filters
+// that allow plain HTTP but not HTTPS are common on restrictive networks, and
+// Bedrock only listens on 443, so that case gets a pointed message.
+func explainHTTPSBlocked(ctx context.Context, cfg *Config, dialer *net.Dialer, ip net.IP, result *CheckResult) {
+	address := net.JoinHostPort(ip.String(), plainHTTPPort)
+	if _, err := checkTCP(ctx, dialer, address, cfg.addressTimeout()); err != nil {
+		result.Message += "; port 80 is blocked too, so there is no direct egress to this host"
+		return
+	}
+	result.Message = fmt.Sprintf("HTTPS egress is filtered: port 80 on %s is open but 443 is not (%s)", ip, result.Message)
+	result.Fix = "Bedrock only accepts HTTPS on port 443; ask for outbound TCP 443 to *.amazonaws.com to be allowed, or set HTTPS_PROXY to a proxy that may reach it"
+}
+
+// explainPrivateLinkBlocked handles a 443 timeout to addresses that are all
+// private, which for an AWS hostname means DNS already points at a VPC
+// interface endpoint. The route is there, so the usual culprit is the
+// endpoint's security group rather than an egress firewall.
+func explainPrivateLinkBlocked(host string, ips []net.IP, result *CheckResult) bool {
+	for _, ip := range ips {
+		if !ip.IsPrivate() {
+			return false
+		}
+	}
+	if len(ips) == 0 {
+		return false
+	}
+	result.Message = fmt.Sprintf("%s resolves to private address %s, a VPC interface endpoint, but TCP 443 to it timed out (%s)", host, ips[0], result.Message)
+	find := "find the interface endpoint whose DNS name matches " + host
+	if region, ok := endpointRegion(host); ok {
+		// vpce-0abc.bedrock-runtime.us-east-1.vpce.amazonaws.com names the service second
+		labels := strings.Split(host, ".")
+		service := labels[0]
+		if strings.HasPrefix(service, "vpce-") {
+			service = labels[1]
+		}
+		find = fmt.Sprintf("find it with 'aws ec2 describe-vpc-endpoints --region %s --filters Name=service-name,Values=com.amazonaws.%s.%s'", region, region, service)
+	}
+	result.reason = reasonVPCEndpoint
+	result.Fix = fmt.Sprintf("The endpoint's security group must allow inbound TCP 443 from this client; %s and add an inbound rule for 443 from this host's subnet CIDR or security group", find)
+	return true
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestExplainHTTPSBlocked(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := portOf(t, listener.Addr())
+	t.Cleanup(func() { plainHTTPPort = "80" })
+
+	cfg := newConfig()
+	plainHTTPPort = open
+	result := CheckResult{Status: "fail", Message: "Failed to connect to bedrock-runtime.us-east-1.amazonaws.com:443"}
+	explainHTTPSBlocked(context.Background(), cfg, cfg.dialer(), net.ParseIP("127.0.0.1"), &result)
+	if !strings.HasPrefix(result.Message, "HTTPS egress is filtered: port 80 on 127.0.0.1 is open") || !strings.Contains(result.Fix, "443") {
+		t.Errorf("with port 80 open: %+v", result)
+	}
+
+	// Nothing listens once the listener is closed
+	listener.Close()
+	result = CheckResult{Status: "fail", Message: "Failed to connect", Fix: "Check the firewall"}
+	explainHTTPSBlocked(context.Background(), cfg, cfg.dialer(), net.ParseIP("127.0.0.1"), &result)
+	if result.Message != "Failed to connect; port 80 is blocked too, so there is no direct egress to this host" || result.Fix != "Check the firewall" {
+		t.Errorf("with port 80 closed: %+v", result)
+	}
+}
+
+func TestExplainPrivateLinkBlocked(t *testing.T) {
+	tests := []struct {
+		host    string
+		ips     []string
+		want    bool
+		wantFix string
+	}{
+		{"bedrock-runtime.us-east-1.amazonaws.com", []string{"10.0.1.12", "10.0.2.40"}, true, "com.amazonaws.us-east-1.bedrock-runtime"},
+		{"vpce-0abc-1234.bedrock-runtime.eu-west-1.vpce.amazonaws.com", []string{"172.31.4.9"}, true, "com.amazonaws.eu-west-1.bedrock-runtime"},
+		{"bedrock.internal.example.com", []string{"192.168.5.5"}, true, "DNS name matches bedrock.internal.example.com"},
+		// A public address means the timeout is an egress problem, not PrivateLink
+		{"bedrock-runtime.us-east-1.amazonaws.com", []string{"10.0.1.12", "52.94.0.10"}, false, ""},
+		{"bedrock-runtime.us-east-1.amazonaws.com", nil, false, ""},
+	}
+	for _, tt := range tests {
+		var ips []net.IP
+		for _, ip := range tt.ips {
+			ips = append(ips, net.ParseIP(ip))
+		}
+		result := CheckResult{Message: "i/o timeout", Fix: netErrorTimeout.Fix}
+		if got := explainPrivateLinkBlocked(tt.host, ips, &result); got != tt.want {
+			t.Errorf("explainPrivateLinkBlocked(%s, %v) = %v, want %v", tt.host, tt.ips, got, tt.want)
+			continue
+		}
+		if !tt.want {
+			if result.Fix != netErrorTimeout.Fix {
+				t.Errorf("explainPrivateLinkBlocked(%s, %v) changed the fix to %q", tt.host, tt.ips, result.Fix)
+			}
+			continue
+		}
+		if !strings.Contains(result.Fix, "security group") || !strings.Contains(result.Fix, tt.wantFix) {
+			t.Errorf("fix = %q, want the endpoint security group and %q", result.Fix, tt.wantFix)
+		}
+		if !strings.Contains(result.Message, "VPC interface endpoint") {
+			t.Errorf("message = %q, want it to name the VPC interface endpoint", result.Message)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// Error kinds reported in CheckResult.ErrorKind so automation can branch without parsing messages
+const (
+	ErrorKindDNS     = "dns"
+	ErrorKindTCP     = "tcp"
+	ErrorKindTLS     = "tls"
+	ErrorKindAuth    = "auth"
+	ErrorKindTimeout = "timeout"
+)
+
+// ProbeError tags a probe failure with the layer it happened at. errors.Is
+// matches on Kind alone when the target has no Cause, e.g.
+// errors.Is(err, &ProbeError{Kind: ErrorKindTimeout}).
+type ProbeError struct {
+	Kind  string
+	Cause error
+}
+
+// newProbeError wraps cause as a failure of the given kind. Timeouts, DNS and
+// connection errors surfacing from a higher layer keep their more specific
+// kind, and an existing ProbeError is returned unchanged.
+func newProbeError(kind string, cause error) *ProbeError {
+	var probeErr *ProbeError
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.As(cause, &probeErr):
+		return probeErr
+	case errors.Is(cause, context.DeadlineExceeded), errors.As(cause, &netErr) && netErr.Timeout():
+		kind = ErrorKindTimeout
+	case errors.As(cause, &dnsErr):
+		kind = ErrorKindDNS
+	case errors.As(cause, &opErr) && opErr.Op == "dial":
+		kind = ErrorKindTCP
+	}
+	return &ProbeError{Kind: kind, Cause: cause}
+}
+
+// Error returns the cause's message so existing result messages read the same
+func (e *ProbeError) Error() string {
+	if e.Cause == nil {
+		return e.Kind + " failure"
+	}
+	return e.Cause.Error()
+}
+
+func (e *ProbeError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *ProbeError) Is(target error) bool {
+	t, ok := target.(*ProbeError)
+	return ok && t.Cause == nil && t.Kind == e.Kind
+}
+
+// errorKind returns the kind of the ProbeError in err's chain, or "" if there is none
+func errorKind(err error) string {
+	var probeErr *ProbeError
+	if errors.As(err, &probeErr) {
+		return probeErr.Kind
+	}
+	return ""
+}
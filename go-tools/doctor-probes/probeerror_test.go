@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewProbeErrorKind(t *testing.T) {
+	tests := []struct {
+		name  string
+		kind  string
+		cause error
+		want  string
+	}{
+		{"tls failure", ErrorKindTLS, errors.New("remote error: tls: handshake failure"), ErrorKindTLS},
+		{"deadline", ErrorKindTLS, context.DeadlineExceeded, ErrorKindTimeout},
+		{"net timeout", ErrorKindTCP, dialFailure(timeoutError{}), ErrorKindTimeout},
+		{"dns under auth", ErrorKindAuth, fmt.Errorf("operation error STS: %w", &net.DNSError{Err: "no such host", Name: "sts.amazonaws.com", IsNotFound: true}), ErrorKindDNS},
+		{"dial under tls", ErrorKindTLS, dialFailure(os.NewSyscallError("connect", refusedErrnos[0])), ErrorKindTCP},
+		{"no cause", ErrorKindAuth, nil, ErrorKindAuth},
+		{"already wrapped", ErrorKindTLS, &ProbeError{Kind: ErrorKindDNS, Cause: errors.New("x")}, ErrorKindDNS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newProbeError(tt.kind, tt.cause).Kind; got != tt.want {
+				t.Errorf("kind = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeErrorIsAs(t *testing.T) {
+	cause := os.NewSyscallError("connect", refusedErrnos[0])
+	err := fmt.Errorf("probe: %w", newProbeError(ErrorKindTCP, dialFailure(cause)))
+
+	if !errors.Is(err, &ProbeError{Kind: ErrorKindTCP}) {
+		t.Error("errors.Is did not match the tcp kind")
+	}
+	if errors.Is(err, &ProbeError{Kind: ErrorKindDNS}) {
+		t.Error("errors.Is matched the wrong kind")
+	}
+	if !errors.Is(err, refusedErrnos[0]) {
+		t.Error("errors.Is did not reach the underlying cause")
+	}
+	var probeErr *ProbeError
+	if !errors.As(err, &probeErr) || probeErr.Kind != ErrorKindTCP {
+		t.Errorf("errors.As = %+v, want tcp ProbeError", probeErr)
+	}
+	if errorKind(errors.New("plain")) != "" {
+		t.Error("errorKind of a plain error should be empty")
+	}
+}
+
+func TestErrorKindJSON(t *testing.T) {
+	data, err := json.Marshal(CheckResult{Name: "x", Status: "pass"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "error_kind") {
+		t.Errorf("passing result should omit error_kind: %s", data)
+	}
+
+	data, err = json.Marshal(CheckResult{Name: "x", Status: "fail", ErrorKind: ErrorKindTimeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"error_kind":"timeout"`) {
+		t.Errorf("failing result is missing error_kind: %s", data)
+	}
+}
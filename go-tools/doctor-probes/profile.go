@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// sharedConfigFiles returns the shared config and credentials paths the SDK will read
+func sharedConfigFiles() (configFile, credentialsFile string) {
+	configFile = os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = config.DefaultSharedConfigFilename()
+	}
+	credentialsFile = os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = config.DefaultSharedCredentialsFilename()
+	}
+	return configFile, credentialsFile
+}
+
+// activeProfile returns the profile the SDK will use and where that choice
+// came from. A --profile override wins over the environment
+func activeProfile(override string) (profile, source string) {
+	if override != "" {
+		return override, "--profile"
+	}
+	for _, name := range []string{"AWS_PROFILE", "AWS_DEFAULT_PROFILE"} {
+		if value := os.Getenv(name); value != "" {
+			return value, name
+		}
+	}
+	return "default", "default"
+}
+
+// listProfiles returns the profile names defined in a shared config or credentials file
+func listProfiles(path string, isConfig bool) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var profiles []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		name, ok := profileSection(scanner.Text(), isConfig)
+		if ok && name != "" && !slices.Contains(profiles, name) {
+			profiles = append(profiles, name)
+		}
+	}
+	return profiles
+}
+
+// profileSection reports whether line is a section header and, if so, the
+// profile it declares. Non-profile sections such as [sso-session x] yield ""
+func profileSection(line string, isConfig bool) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	name := strings.TrimSpace(strings.Trim(line, "[]"))
+	if isConfig {
+		// The config file uses [profile name] for everything except [default]
+		if after, ok := strings.CutPrefix(name, "profile "); ok {
+			return strings.TrimSpace(after), true
+		} else if name != "default" {
+			return "", true
+		}
+	}
+	return name, true
+}
+
+// profileValue returns key from the profile's section in a shared config or
+// credentials file. A later section for the same profile overrides an earlier one
+func profileValue(path string, isConfig bool, profile, key string) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	var value string
+	var found, inProfile bool
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := profileSection(line, isConfig); ok {
+			inProfile = name == profile
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if !inProfile || line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		// Indented lines continue a nested value such as s3 = ..., never a top-level key
+		if raw := scanner.Text(); raw[0] == ' ' || raw[0] == '\t' {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(k) == key {
+			value, found = strings.TrimSpace(v), true
+		}
+	}
+	return value, found
+}
+
+// definedProfiles returns the profiles defined in either shared file, config file first
+func definedProfiles(configFile, credentialsFile string) []string {
+	available := listProfiles(configFile, true)
+	for _, p := range listProfiles(credentialsFile, false) {
+		if !slices.Contains(available, p) {
+			available = append(available, p)
+		}
+	}
+	return available
+}
+
+// loadAWSConfig loads the SDK config for region, using --profile when given
+// in place of AWS_PROFILE
+func (c *Config) loadAWSConfig(ctx context.Context, region string) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if c.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(c.Profile))
+	}
+	return config.LoadDefaultConfig(ctx, opts...)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// runProfileChecks reports which shared config profile would be used and
+// whether a region can be derived from it
+func runProfileChecks(ctx context.Context, profileOverride string) []CheckResult {
+	var results []CheckResult
+
+	name := "AWS Config - Profile"
+	configFile, credentialsFile := sharedConfigFiles()
+	profile, source := activeProfile(profileOverride)
+
+	var found []string
+	for _, path := range []string{configFile, credentialsFile} {
+		if fileExists(path) {
+			found = append(found, path)
+		}
+	}
+	logger.Debug("inspecting shared config", "profile", profile, "source", source, "files", found)
+
+	if len(found) == 0 {
+		status := "pass"
+		fix := ""
+		if source != "default" {
+			status = "fail"
+			fix = fmt.Sprintf("Create the profile with 'aws configure --profile %s' or unset %s", profile, source)
+		}
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  status,
+			Message: fmt.Sprintf("No shared config files found (%s, %s); only environment and instance credentials can be used", configFile, credentialsFile),
+			Fix:     fix,
+		})
+		return results
+	}
+
+	shared, err := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		o.ConfigFiles = []string{configFile}
+		o.CredentialsFiles = []string{credentialsFile}
+	})
+	if err != nil {
+		var notExist config.SharedConfigProfileNotExistError
+		if !errors.As(err, &notExist) {
+			results = append(results, CheckResult{
+				Name:    name,
+				Status:  "fail",
+				Message: fmt.Sprintf("Failed to load profile %q: %v", profile, err),
+				Fix:     fmt.Sprintf("Check %s for syntax errors", strings.Join(found, " and ")),
+			})
+			return results
+		}
+
+		available := definedProfiles(configFile, credentialsFile)
+
+		status := "warn"
+		message := fmt.Sprintf("No [default] profile in %s", strings.Join(found, " or "))
+		if source != "default" {
+			status = "fail"
+			message = fmt.Sprintf("Profile %q (from %s) is not defined in %s", profile, source, strings.Join(found, " or "))
+		}
+		fix := "Run 'aws configure' to create a profile"
+		if len(available) > 0 && source == "--profile" {
+			fix = fmt.Sprintf("Pass --profile with one of: %s", strings.Join(available, ", "))
+		} else if len(available) > 0 {
+			fix = fmt.Sprintf("export AWS_PROFILE=<name> with one of: %s", strings.Join(available, ", "))
+		}
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  status,
+			Message: message,
+			Fix:     fix,
+		})
+		return results
+	}
+
+	if shared.Region == "" {
+		status := "pass"
+		fix := ""
+		if os.Getenv("AWS_REGION") == "" && os.Getenv("AWS_DEFAULT_REGION") == "" {
+			status = "warn"
+			fix = fmt.Sprintf("Add a 'region = us-east-1' line to the %s profile in %s, or export AWS_REGION", profile, configFile)
+		}
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  status,
+			Message: fmt.Sprintf("Using profile %q (from %s), which does not set a region", profile, source),
+			Fix:     fix,
+		})
+		return results
+	}
+
+	results = append(results, CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("Using profile %q (from %s) with region %s", profile, source, shared.Region),
+	})
+
+	return results
+}
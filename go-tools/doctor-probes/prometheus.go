@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func statusValue(status string) string {
+	switch status {
+	case "pass":
+		return "1"
+	case "warn":
+		return "0.5"
+	default:
+		return "0"
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writePrometheus renders results in the Prometheus text exposition format.
+func writePrometheus(w io.Writer, results []CheckResult) error {
+	var b strings.Builder
+
+	labels := make([]string, len(results))
+	for i, result := range results {
+		name := strings.TrimPrefix(result.Name, result.Region+" / ")
+		labels[i] = fmt.Sprintf(`name="%s",region="%s"`, labelEscaper.Replace(name), labelEscaper.Replace(result.Region))
+	}
+
+	b.WriteString("# HELP bcce_probe_status Probe status (1=pass, 0.5=warn, 0=fail).\n")
+	b.WriteString("# TYPE bcce_probe_status gauge\n")
+	for i, result := range results {
+		fmt.Fprintf(&b, "bcce_probe_status{%s} %s\n", labels[i], statusValue(result.Status))
+	}
+
+	b.WriteString("# HELP bcce_probe_duration_seconds Probe duration in seconds.\n")
+	b.WriteString("# TYPE bcce_probe_duration_seconds histogram\n")
+	for i, result := range results {
+		seconds := float64(result.DurationMs) / 1000
+		for _, bucket := range durationBuckets {
+			count := 0
+			if seconds <= bucket {
+				count = 1
+			}
+			fmt.Fprintf(&b, "bcce_probe_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels[i], formatFloat(bucket), count)
+		}
+		fmt.Fprintf(&b, "bcce_probe_duration_seconds_bucket{%s,le=\"+Inf\"} 1\n", labels[i])
+		fmt.Fprintf(&b, "bcce_probe_duration_seconds_sum{%s} %s\n", labels[i], formatFloat(seconds))
+		fmt.Fprintf(&b, "bcce_probe_duration_seconds_count{%s} 1\n", labels[i])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
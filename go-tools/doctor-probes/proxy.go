@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+var proxyEnvVars = []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"}
+
+func proxyEnvSet() bool {
+	for _, name := range proxyEnvVars {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// connectViaProxy opens a CONNECT tunnel to target through the given proxy
+func connectViaProxy(ctx context.Context, proxyURL *url.URL, target string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+		if proxyURL.Scheme == "https" {
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "443")
+		}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return fmt.Errorf("failed to reach proxy %s: %w", proxyAddr, err)
+	}
+	if proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("failed to send CONNECT: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("failed to read CONNECT response: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy refused CONNECT: %s", resp.Status)
+	}
+	return nil
+}
+
+func runProxyChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	// Determine which proxy Go's HTTP client would use for the Bedrock endpoint
+	name := "Proxy - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	bedrockHost := cfg.endpointHost("bedrock-runtime", region)
+	bedrockAddr := cfg.endpointAddr("bedrock-runtime", region)
+	req := &http.Request{URL: &url.URL{Scheme: "https", Host: bedrockHost}}
+	proxyURL, err := http.ProxyFromEnvironment(req)
+	logger.Debug("resolved proxy from environment", "host", bedrockHost, "proxy", proxyURL.Redacted(), "error", err)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Invalid proxy configuration: %v", err),
+			Fix:     "Set HTTPS_PROXY to a valid URL such as http://proxy.example.com:8080",
+		})
+		return results
+	}
+
+	if proxyURL == nil {
+		if proxyEnvSet() {
+			results = append(results, CheckResult{
+				Name:    name,
+				Status:  "warn",
+				Message: fmt.Sprintf("A proxy is configured but NO_PROXY excludes %s, so it will be reached directly", bedrockHost),
+				Fix:     "Remove the Bedrock host from NO_PROXY if outbound traffic must go through the proxy",
+			})
+		} else {
+			results = append(results, CheckResult{
+				Name:    name,
+				Status:  "pass",
+				Message: fmt.Sprintf("No proxy configured; %s will be reached directly", bedrockHost),
+			})
+		}
+		return results
+	}
+
+	start := time.Now()
+	err = connectViaProxy(ctx, proxyURL, bedrockAddr, cfg.Timeout)
+	durationMs := time.Since(start).Milliseconds()
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("CONNECT to %s via %s failed: %v", bedrockAddr, proxyURL.Redacted(), err),
+			Fix:        "Check HTTPS_PROXY, proxy credentials, and that the proxy allows CONNECT to *.amazonaws.com:443",
+			DurationMs: durationMs,
+			reason:     reasonProxy,
+		})
+	} else {
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("Tunneled to %s via %s", bedrockAddr, proxyURL.Redacted()),
+			DurationMs: durationMs,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// regionQuorum applies --require-regions: the run passes when at least
+// required regions have no failing check. It returns the result reporting
+// which regions met the requirement, and the results that still count toward
+// the exit code. Failures in the other regions don't, as long as enough
+// regions are healthy; account- and host-wide checks always count.
+func regionQuorum(results []CheckResult, regions []string, required int) (CheckResult, []CheckResult) {
+	// Region-scoped results carry their region as a name prefix; merged and
+	// global ones don't, and belong to no single region
+	regionOf := func(result CheckResult) string {
+		for _, region := range regions {
+			if strings.HasPrefix(result.Name, region+" / ") {
+				return region
+			}
+		}
+		return ""
+	}
+	failed := map[string]bool{}
+	for _, result := range results {
+		if region := regionOf(result); region != "" && result.Status == "fail" {
+			failed[region] = true
+		}
+	}
+	var healthy, unhealthy []string
+	for _, region := range regions {
+		if failed[region] {
+			unhealthy = append(unhealthy, region)
+		} else {
+			healthy = append(healthy, region)
+		}
+	}
+
+	quorum := CheckResult{Name: "Region Quorum", Category: "overall"}
+	message := fmt.Sprintf("%d of %d regions healthy, %d required by --require-regions", len(healthy), len(regions), required)
+	if len(healthy) > 0 {
+		message += "; healthy: " + strings.Join(healthy, ", ")
+	}
+	if len(healthy) < required {
+		quorum.Status = "fail"
+		quorum.Message = message + "; failing: " + strings.Join(unhealthy, ", ")
+		quorum.Fix = fmt.Sprintf("Fix the failing checks in at least %d more region(s), or lower --require-regions if the deployment fails over", required-len(healthy))
+		return quorum, results
+	}
+
+	quorum.Status = "pass"
+	quorum.Message = message
+	if len(unhealthy) > 0 {
+		quorum.Message += fmt.Sprintf("; failures in %s do not affect the exit code", strings.Join(unhealthy, ", "))
+	}
+	var counted []CheckResult
+	for _, result := range results {
+		if region := regionOf(result); region == "" || !failed[region] {
+			counted = append(counted, result)
+		}
+	}
+	return quorum, counted
+}
+
+// quorumOverall is the Overall line for a --require-regions run. It is built
+// from the results regionQuorum counts toward the exit code, so failures in
+// the regions it excuses don't contradict a passing exit code.
+func quorumOverall(results []CheckResult, regions []string, required int) CheckResult {
+	_, counted := regionQuorum(results, regions, required)
+	overall := overallResult(newSummary(counted, regions))
+	if excused := len(results) - len(counted); excused > 0 {
+		overall.Message += fmt.Sprintf(", not counting %d from regions --require-regions excuses", excused)
+	}
+	return overall
+}
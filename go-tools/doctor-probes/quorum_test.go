@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegionQuorum(t *testing.T) {
+	regions := []string{"us-east-1", "us-west-2", "eu-west-1"}
+	results := []CheckResult{
+		{Name: "us-east-1 / DNS - Bedrock Runtime", Status: "pass"},
+		{Name: "us-west-2 / DNS - Bedrock Runtime", Status: "warn"},
+		{Name: "eu-west-1 / DNS - Bedrock Runtime", Status: "fail"},
+		{Name: "eu-west-1 / TCP - Bedrock Runtime", Status: "fail"},
+		{Name: "Credentials", Status: "pass"},
+	}
+
+	quorum, counted := regionQuorum(results, regions, 2)
+	if quorum.Status != "pass" || !strings.Contains(quorum.Message, "2 of 3 regions healthy, 2 required") || !strings.Contains(quorum.Message, "healthy: us-east-1, us-west-2") || !strings.Contains(quorum.Message, "failures in eu-west-1") {
+		t.Errorf("quorum of 2 = %s %q, want it met naming the healthy regions", quorum.Status, quorum.Message)
+	}
+	if code := exitCode(counted); code != exitWarn || len(counted) != 3 {
+		t.Errorf("counted %d results with exit %d, want eu-west-1 left out and the warning kept", len(counted), code)
+	}
+
+	quorum, counted = regionQuorum(results, regions, 3)
+	if quorum.Status != "fail" || !strings.Contains(quorum.Message, "failing: eu-west-1") || len(counted) != len(results) {
+		t.Errorf("quorum of 3 = %s %q, want it missed", quorum.Status, quorum.Message)
+	}
+
+	// A failing global check can't be excused by a healthy region
+	results = append(results, CheckResult{Name: "Credentials - Expiry", Status: "fail"})
+	if _, counted := regionQuorum(results, regions, 1); exitCode(counted) != exitFail {
+		t.Errorf("a global failure was excused by --require-regions")
+	}
+}
+
+func TestQuorumOverallMatchesExitCode(t *testing.T) {
+	regions := []string{"us-east-1", "eu-west-1"}
+	results := []CheckResult{
+		{Name: "us-east-1 / TCP - Bedrock Runtime", Status: "pass"},
+		{Name: "eu-west-1 / TCP - Bedrock Runtime", Status: "fail"},
+		{Name: "Credentials", Status: "pass"},
+	}
+	statusCodes := map[string]int{"pass": exitPass, "warn": exitWarn, "fail": exitFail}
+
+	for _, required := range []int{1, 2} {
+		// The same steps the report takes: the exit code from the counted
+		// results, and the Overall line from the report with the quorum in it
+		quorum, counted := regionQuorum(results, regions, required)
+		code := exitCode(counted)
+		if quorum.Status == "fail" {
+			code = exitFail
+		}
+		overall := quorumOverall(append(slices.Clone(results), quorum), regions, required)
+		if statusCodes[overall.Status] != code {
+			t.Errorf("--require-regions %d: Overall is %s %q but the exit code is %d", required, overall.Status, overall.Message, code)
+		}
+	}
+
+	overall := quorumOverall(results, regions, 1)
+	if overall.Message != "All 2 checks passed, not counting 1 from regions --require-regions excuses" {
+		t.Errorf("quorumOverall() message = %q, want the excused result noted", overall.Message)
+	}
+
+	// The console footer follows the Overall line rather than the excused failure
+	var buf bytes.Buffer
+	writeHuman(&buf, append(slices.Clone(results), overall), false, true, nil)
+	if !strings.Contains(buf.String(), "[PASS] All checks passed") {
+		t.Errorf("console footer disagrees with a passing Overall:\n%s", buf.String())
+	}
+}
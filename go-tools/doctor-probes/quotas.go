@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
+	"github.com/aws/smithy-go"
+)
+
+func quotaIncreaseURL(region, quotaCode string) string {
+	return fmt.Sprintf("https://%s.console.aws.amazon.com/servicequotas/home/services/bedrock/quotas/%s", region, quotaCode)
+}
+
+// listBedrockQuotas returns the Bedrock quotas for the region keyed by quota
+// code; applied values are only returned for quotas that have one
+func listBedrockQuotas(ctx context.Context, client *servicequotas.Client, applied bool) (map[string]types.ServiceQuota, error) {
+	quotas := map[string]types.ServiceQuota{}
+	add := func(page []types.ServiceQuota) {
+		for _, quota := range page {
+			quotas[aws.ToString(quota.QuotaCode)] = quota
+		}
+	}
+
+	if applied {
+		paginator := servicequotas.NewListServiceQuotasPaginator(client, &servicequotas.ListServiceQuotasInput{ServiceCode: aws.String("bedrock")})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			add(page.Quotas)
+		}
+		return quotas, nil
+	}
+
+	paginator := servicequotas.NewListAWSDefaultServiceQuotasPaginator(client, &servicequotas.ListAWSDefaultServiceQuotasInput{ServiceCode: aws.String("bedrock")})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		add(page.Quotas)
+	}
+	return quotas, nil
+}
+
+// runQuotaChecks reports the requests-per-minute quotas for the --quotas model
+// family and warns when one is still at the AWS default, which is often too low
+// for a team sharing one account
+func runQuotaChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	family := cfg.Quotas
+	name := "Quotas - " + family
+
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load AWS config: %v", err),
+			Fix:     "Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+		})
+		return results
+	}
+	client := servicequotas.NewFromConfig(awsCfg)
+
+	logger.Debug("listing Bedrock service quotas", "region", region, "family", family)
+	defaults, err := listBedrockQuotas(ctx, client, false)
+	var applied map[string]types.ServiceQuota
+	if err == nil {
+		applied, err = listBedrockQuotas(ctx, client, true)
+	}
+	var apiErr smithy.APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "AccessDeniedException":
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("Not allowed to read Bedrock quotas in %s: %s", region, apiErr.ErrorMessage()),
+			Fix:     "Allow servicequotas:ListServiceQuotas and servicequotas:ListAWSDefaultServiceQuotas for this principal",
+		})
+		return results
+	case err != nil:
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Could not list Bedrock quotas in %s: %v", region, err),
+			Fix:     awsErrorFix(err, "Check credentials and network access to Service Quotas"),
+		})
+		return results
+	}
+
+	needle := strings.ToLower(family)
+	for code, quota := range defaults {
+		quotaName := aws.ToString(quota.QuotaName)
+		lower := strings.ToLower(quotaName)
+		if !strings.Contains(lower, "requests per minute") || !strings.Contains(lower, needle) {
+			continue
+		}
+
+		defaultValue := aws.ToFloat64(quota.Value)
+		value := defaultValue
+		if current, ok := applied[code]; ok && current.Value != nil {
+			value = aws.ToFloat64(current.Value)
+		}
+
+		if value <= defaultValue {
+			fix := "Request an increase at " + quotaIncreaseURL(region, code)
+			if !quota.Adjustable {
+				fix = "This quota is not adjustable; spread load across regions with a cross-region inference profile"
+			}
+			results = append(results, CheckResult{
+				Name:    "Quotas - " + quotaName,
+				Status:  "warn",
+				Message: fmt.Sprintf("%s is at the AWS default of %g in %s; expect ThrottlingException under shared load", quotaName, value, region),
+				Fix:     fix,
+				reason:  reasonQuota,
+			})
+			continue
+		}
+		results = append(results, CheckResult{
+			Name:    "Quotas - " + quotaName,
+			Status:  "pass",
+			Message: fmt.Sprintf("%s is %g in %s (default %g)", quotaName, value, region, defaultValue),
+		})
+	}
+
+	if len(results) == 0 {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("No Bedrock requests-per-minute quota in %s matches %q", region, family),
+			Fix:     "Use part of the quota name as shown in the Service Quotas console, e.g. --quotas \"Claude 3.5 Sonnet\"",
+		})
+	}
+	// Map order is random; keep reports stable between runs
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	return results
+}
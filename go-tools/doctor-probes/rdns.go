@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Matches region names embedded in AWS PTR records, e.g. ec2-1-2-3-4.us-west-2.compute.amazonaws.com
+var ptrRegionPattern = regexp.MustCompile(`\b([a-z]{2}(?:-gov|-iso[a-z]?)?-(?:north|south|east|west|central|northeast|northwest|southeast|southwest)-\d)\b`)
+
+// ptrRegionHint extracts the region a PTR record appears to belong to, if any
+func ptrRegionHint(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	// us-east-1 predates regional naming and uses compute-1
+	if strings.Contains(name, ".compute-1.amazonaws.com") {
+		return "us-east-1"
+	}
+	if match := ptrRegionPattern.FindStringSubmatch(name); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// runReverseDNSChecks compares region hints in the PTR records of the resolved
+// Bedrock addresses with the configured region. PTR naming is not guaranteed,
+// so a mismatch is only ever a warning.
+func runReverseDNSChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	name := "Reverse DNS - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	bedrockHost := cfg.endpointHost("bedrock-runtime", region)
+	start := time.Now()
+	ips, _, err := checkDNS(ctx, cfg, bedrockHost)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("Failed to resolve %s: %v", bedrockHost, err),
+			Fix:        "Check internet connectivity and DNS settings",
+			DurationMs: time.Since(start).Milliseconds(),
+		})
+		return results
+	}
+
+	var mismatched, hinted []string
+	for _, ip := range ips {
+		if ip.IsPrivate() {
+			continue
+		}
+
+		lookupCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+		names, err := cfg.resolver().LookupAddr(lookupCtx, ip.String())
+		cancel()
+		logger.Debug("reverse lookup", "ip", ip.String(), "names", names, "error", err)
+		if err != nil {
+			continue
+		}
+		for _, ptr := range names {
+			hint := ptrRegionHint(ptr)
+			if hint == "" {
+				continue
+			}
+			hinted = append(hinted, ip.String())
+			if hint != region {
+				mismatched = append(mismatched, fmt.Sprintf("%s (%s -> %s)", ip, strings.TrimSuffix(ptr, "."), hint))
+			}
+			break
+		}
+	}
+	durationMs := time.Since(start).Milliseconds()
+
+	switch {
+	case len(mismatched) > 0:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("PTR records for %s suggest a region other than %s: %s (heuristic; PTR names may not reflect where traffic is served)", bedrockHost, region, strings.Join(mismatched, ", ")),
+			Fix:        "Check whether a VPN or proxy is routing traffic out of another region, and that AWS_REGION matches where you expect requests to go",
+			DurationMs: durationMs,
+		})
+	case len(hinted) == 0:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("No region hints found in PTR records for %s; nothing to compare", bedrockHost),
+			DurationMs: durationMs,
+		})
+	default:
+		results = append(results, CheckResult{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("PTR records for %s match %s", bedrockHost, region),
+			DurationMs: durationMs,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// secretPattern matches one kind of credential; the first keep characters
+// stay visible so a redacted value can still be told apart from others
+type secretPattern struct {
+	re   *regexp.Regexp
+	keep int
+	// Optionally rejects matches that only look like the secret
+	valid func(string) bool
+}
+
+var secretPatterns = []secretPattern{
+	// Access key IDs: AKIA long-term, ASIA temporary, and the other IAM unique ID prefixes
+	{re: regexp.MustCompile(`\b(?:AKIA|ASIA|AIDA|AROA|ABIA|ACCA|AGPA|ANPA|ANVA|APKA)[A-Z0-9]{16}\b`), keep: 4},
+	// Bedrock API keys, short-term and long-term
+	{re: regexp.MustCompile(`\bbedrock-api-key-[A-Za-z0-9+/=_-]{16,}`), keep: len("bedrock-api-key-")},
+	{re: regexp.MustCompile(`\bABSK[A-Za-z0-9+/=]{16,}`), keep: 4},
+	// JWTs such as OIDC and SSO access tokens
+	{re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{4,}\.[A-Za-z0-9_-]{4,}\.[A-Za-z0-9_-]*`), keep: 4},
+	// Session tokens and other long base64 blobs
+	{re: regexp.MustCompile(`[A-Za-z0-9+/]{100,}={0,2}`), keep: 4},
+	// Secret access keys are 40 base64 characters; Git hashes are excluded by requiring mixed case
+	{re: regexp.MustCompile(`\b[A-Za-z0-9+/]{40}\b`), keep: 4, valid: mixedCase},
+}
+
+// mixedCase reports whether s has upper- and lowercase letters and a digit
+func mixedCase(s string) bool {
+	return strings.IndexFunc(s, unicode.IsUpper) >= 0 &&
+		strings.IndexFunc(s, unicode.IsLower) >= 0 &&
+		strings.IndexFunc(s, unicode.IsDigit) >= 0
+}
+
+// redact masks every secret in s, keeping only a short prefix such as "ASIA…"
+func redact(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.re.ReplaceAllStringFunc(s, func(match string) string {
+			if pattern.valid != nil && !pattern.valid(match) {
+				return match
+			}
+			return match[:pattern.keep] + "…"
+		})
+	}
+	return s
+}
+
+// redactResult masks secrets in the text of a result before it is reported
+func redactResult(result *CheckResult) {
+	result.Message = redact(result.Message)
+	result.Fix = redact(result.Fix)
+}
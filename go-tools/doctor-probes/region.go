@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+)
+
+// IMDS is link-local, so anything slower than this means we're not on EC2
+const imdsRegionTimeout = time.Second
+
+// regionSources lists the places resolveRegion looks, in order, for the Fix
+// shown when none yield a region
+func regionSources() []string {
+	configFile, _ := sharedConfigFiles()
+	return []string{"AWS_REGION", "AWS_DEFAULT_REGION", "the active profile in " + configFile, "EC2 instance metadata"}
+}
+
+// Commercial, GovCloud (us-gov-west-1), ISO and China (cn-north-1) region identifiers
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso|-isob)?-(central|(north|south)?(east|west)|north|south)-\d+$`)
+
+// Published regions, used to suggest a correction for a mistyped name
+var knownRegions = []string{
+	"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+	"us-gov-east-1", "us-gov-west-1",
+	"ca-central-1", "ca-west-1", "mx-central-1", "sa-east-1",
+	"eu-central-1", "eu-central-2", "eu-north-1", "eu-south-1", "eu-south-2",
+	"eu-west-1", "eu-west-2", "eu-west-3",
+	"il-central-1", "me-central-1", "me-south-1", "af-south-1",
+	"ap-east-1", "ap-east-2", "ap-south-1", "ap-south-2",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4", "ap-southeast-5", "ap-southeast-7",
+	"cn-north-1", "cn-northwest-1",
+}
+
+// Regions where Bedrock is offered. Keep in sync with
+// https://docs.aws.amazon.com/general/latest/gr/bedrock.html when regions launch.
+var bedrockRegions = []string{
+	// Commercial
+	"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+	"ca-central-1", "sa-east-1",
+	"eu-central-1", "eu-central-2", "eu-north-1", "eu-south-1", "eu-south-2",
+	"eu-west-1", "eu-west-2", "eu-west-3",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-south-1", "ap-south-2",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4",
+	"il-central-1", "me-central-1",
+	// GovCloud
+	"us-gov-east-1", "us-gov-west-1",
+}
+
+// validRegion reports whether a name is a known region or has the shape of one,
+// so regions launched after this build are still accepted
+func validRegion(region string) bool {
+	return slices.Contains(knownRegions, region) || regionPattern.MatchString(region)
+}
+
+// suggestRegion returns the known region with the smallest edit distance to region
+func suggestRegion(region string) string {
+	best, bestDistance := "", -1
+	for _, known := range knownRegions {
+		if distance := editDistance(strings.ToLower(region), known); bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = known, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// runBedrockRegionChecks fails early for a valid region that simply has no
+// Bedrock endpoints, instead of leaving DNS and TCP to fail confusingly
+func runBedrockRegionChecks(_ context.Context, _ *Config, region string) []CheckResult {
+	if slices.Contains(bedrockRegions, region) {
+		return []CheckResult{{
+			Name:    "Region - Bedrock Availability",
+			Status:  "pass",
+			Message: fmt.Sprintf("Bedrock is available in %s", region),
+		}}
+	}
+	return []CheckResult{{
+		Name:    "Region - Bedrock Availability",
+		Status:  "fail",
+		Message: fmt.Sprintf("Bedrock is not available in %s", region),
+		Fix:     fmt.Sprintf("Use one of the supported regions: %s", strings.Join(bedrockRegions, ", ")),
+		reason:  reasonRegion,
+	}}
+}
+
+// resolveRegion finds the region the AWS SDK would use when --regions is not
+// given, returning the region and a description of where it came from
+func resolveRegion(ctx context.Context, profileOverride string) (string, string, error) {
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(name); region != "" {
+			return region, name, nil
+		}
+	}
+
+	profile, _ := activeProfile(profileOverride)
+	configFile, credentialsFile := sharedConfigFiles()
+	shared, err := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		o.ConfigFiles = []string{configFile}
+		o.CredentialsFiles = []string{credentialsFile}
+	})
+	logger.Debug("loaded shared config profile for region", "profile", profile, "config_file", configFile, "credentials_file", credentialsFile, "region", shared.Region, "error", err)
+	if err == nil && shared.Region != "" {
+		return shared.Region, fmt.Sprintf("profile %s in %s", profile, regionFile(profile, configFile, credentialsFile)), nil
+	}
+
+	if region, err := instanceRegion(ctx); err == nil {
+		return region, "EC2 instance metadata", nil
+	}
+
+	return "", "", errors.New("no region found")
+}
+
+// instanceRegion reads the region the EC2 instance runs in from instance
+// metadata (placement/region)
+func instanceRegion(ctx context.Context) (string, error) {
+	if function, ok := lambdaFunction(); ok {
+		return "", fmt.Errorf("running in Lambda function %s, which has no instance metadata", function)
+	}
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		return "", errors.New("instance metadata lookups are disabled (AWS_EC2_METADATA_DISABLED=true)")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, imdsRegionTimeout)
+	defer cancel()
+	output, err := imds.New(imds.Options{}).GetMetadata(ctx, &imds.GetMetadataInput{Path: "placement/region"})
+	logger.Debug("queried instance metadata for region", "error", err)
+	if err != nil {
+		return "", fmt.Errorf("instance metadata is not reachable; not running on EC2? (%v)", err)
+	}
+	defer output.Content.Close()
+	data, err := io.ReadAll(output.Content)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the region from instance metadata: %v", err)
+	}
+	region := strings.TrimSpace(string(data))
+	if region == "" {
+		return "", errors.New("instance metadata returned an empty region")
+	}
+	return region, nil
+}
+
+// instanceRegionCheck reports the region from --region-from-instance next to
+// the one the environment or profile configures, warning when they differ
+func instanceRegionCheck(ctx context.Context, region, profileOverride string) CheckResult {
+	check := CheckResult{
+		Name:     "AWS_REGION",
+		Status:   "pass",
+		Category: "config",
+		Region:   region,
+	}
+	configured, source, err := resolveRegion(ctx, profileOverride)
+	switch {
+	case err != nil || source == "EC2 instance metadata":
+		check.Message = fmt.Sprintf("Using %s from EC2 instance metadata; no region is configured otherwise", region)
+	case configured == region:
+		check.Message = fmt.Sprintf("Using %s from EC2 instance metadata, matching %s", region, source)
+	default:
+		check.Status = "warn"
+		check.Message = fmt.Sprintf("Using %s from EC2 instance metadata, but %s sets %s", region, source, configured)
+		check.Fix = fmt.Sprintf("Tools on this instance will call Bedrock in %s; set it to %s if that is not intended", configured, region)
+		check.reason = reasonRegion
+	}
+	return check
+}
+
+// regionFile names the shared file that supplied the profile's region. The
+// SDK lets the credentials file override the config file, so it is read first
+func regionFile(profile, configFile, credentialsFile string) string {
+	if _, ok := profileValue(credentialsFile, false, profile, "region"); ok {
+		return credentialsFile
+	}
+	return configFile
+}
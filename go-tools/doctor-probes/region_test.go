@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidRegion(t *testing.T) {
+	tests := []struct {
+		region string
+		want   bool
+	}{
+		{"us-east-1", true},
+		{"eu-central-2", true},
+		{"us-gov-west-1", true},
+		{"cn-northwest-1", true},
+		{"us-iso-east-1", true},
+		{"xx-southwest-9", true},
+		{"eu-wets-1", false},
+		{"us-east1", false},
+		{"US-EAST-1", false},
+		{"useast-1", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := validRegion(tt.region); got != tt.want {
+			t.Errorf("validRegion(%q) = %v, want %v", tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestRegion(t *testing.T) {
+	tests := map[string]string{
+		"us-east1":      "us-east-1",
+		"US-WEST-2":     "us-west-2",
+		"eu-west-01":    "eu-west-1",
+		"us-gov-west1":  "us-gov-west-1",
+		"cn-northwest1": "cn-northwest-1",
+	}
+
+	for region, want := range tests {
+		if got := suggestRegion(region); got != want {
+			t.Errorf("suggestRegion(%q) = %q, want %q", region, got, want)
+		}
+	}
+}
+
+func TestResolveRegionFromSharedFiles(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	credentialsFile := filepath.Join(dir, "credentials")
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(configFile, "[default]\nregion = us-west-2\n\n[profile dev]\nregion = eu-west-1\ns3 =\n  region = ignored\n\n[sso-session dev]\nregion = ap-south-1\n")
+	writeFile(credentialsFile, "[ops]\naws_access_key_id = x\naws_secret_access_key = y\nregion = ca-central-1\n")
+
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_DEFAULT_PROFILE", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	tests := []struct {
+		profile, override, region, source string
+	}{
+		{"", "", "us-west-2", "profile default in " + configFile},
+		{"dev", "", "eu-west-1", "profile dev in " + configFile},
+		{"ops", "", "ca-central-1", "profile ops in " + credentialsFile},
+		{"dev", "ops", "ca-central-1", "profile ops in " + credentialsFile},
+	}
+	for _, tt := range tests {
+		t.Setenv("AWS_PROFILE", tt.profile)
+		region, source, err := resolveRegion(context.Background(), tt.override)
+		if err != nil || region != tt.region || source != tt.source {
+			t.Errorf("profile %q, --profile %q: resolveRegion() = %q, %q, %v; want %q, %q", tt.profile, tt.override, region, source, err, tt.region, tt.source)
+		}
+	}
+
+	if got := definedProfiles(configFile, credentialsFile); !slices.Equal(got, []string{"default", "dev", "ops"}) {
+		t.Errorf("definedProfiles() = %v, want [default dev ops]", got)
+	}
+
+	if got := regionSources()[2]; !strings.Contains(got, configFile) {
+		t.Errorf("regionSources() profile entry = %q, want it to name %s", got, configFile)
+	}
+}
+
+func TestInstanceRegionCheck(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-west-2")
+	if check := instanceRegionCheck(context.Background(), "us-west-2", ""); check.Status != "pass" || !strings.Contains(check.Message, "matching AWS_REGION") {
+		t.Errorf("matching regions = %s %q, want a pass naming AWS_REGION", check.Status, check.Message)
+	}
+	check := instanceRegionCheck(context.Background(), "eu-west-1", "")
+	if check.Status != "warn" || !strings.Contains(check.Message, "AWS_REGION sets us-west-2") {
+		t.Errorf("mismatched regions = %s %q, want a warning naming both regions", check.Status, check.Message)
+	}
+}
+
+func TestInstanceRegionOutsideEC2(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	if _, err := instanceRegion(context.Background()); err == nil || !strings.Contains(err.Error(), "AWS_EC2_METADATA_DISABLED") {
+		t.Errorf("instanceRegion() error = %v, want the metadata-disabled reason", err)
+	}
+}
+
+func TestInstanceRegionFromMetadata(t *testing.T) {
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/api/token":
+			w.Write([]byte("token"))
+		case "/latest/meta-data/placement/region":
+			w.Write([]byte("eu-west-1"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer imds.Close()
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", imds.URL)
+
+	if region, err := instanceRegion(context.Background()); err != nil || region != "eu-west-1" {
+		t.Errorf("instanceRegion() = %q, %v, want eu-west-1", region, err)
+	}
+}
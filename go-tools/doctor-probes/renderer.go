@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Renderer writes a finished run in one output format
+type Renderer interface {
+	Render(ProbeOutput, io.Writer) error
+}
+
+// Values accepted by --format, in the order shown in help text
+var outputFormats = []string{"human", "json", "json-array", "ndjson", "prometheus", "junit", "yaml", "oneline", "fix-script"}
+
+// renderOptions are the output flags that apply across formats
+type renderOptions struct {
+	// Drop passing checks from the listing; summaries still count every check
+	Quiet bool
+	// Use [PASS]/[WARN]/[FAIL] instead of emoji in the human report
+	Plain bool
+	// Indent JSON; other formats ignore it
+	Pretty bool
+	// Catalog for the human report's fixed strings; nil is English
+	Messages messages
+}
+
+// newRenderer returns the renderer for a --format value
+func newRenderer(format string, opts renderOptions) (Renderer, error) {
+	quiet := opts.Quiet
+	switch format {
+	case "human":
+		return humanRenderer{quiet: quiet, plain: opts.Plain, msgs: opts.Messages}, nil
+	case "json":
+		return jsonRenderer{quiet: quiet, pretty: opts.Pretty}, nil
+	case "json-array":
+		return jsonArrayRenderer{quiet: quiet, pretty: opts.Pretty}, nil
+	case "ndjson":
+		return ndjsonRenderer{quiet: quiet}, nil
+	case "prometheus":
+		return prometheusRenderer{}, nil
+	case "junit":
+		return junitRenderer{}, nil
+	case "yaml":
+		return yamlRenderer{quiet: quiet}, nil
+	case "oneline":
+		return onelineRenderer{plain: opts.Plain}, nil
+	case "fix-script":
+		return fixScriptRenderer{}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(outputFormats, ", "))
+}
+
+type humanRenderer struct {
+	quiet, plain bool
+	msgs         messages
+}
+
+func (r humanRenderer) Render(output ProbeOutput, w io.Writer) error {
+	writeHuman(w, output.Checks, r.quiet, r.plain, r.msgs)
+	if summary := output.Summary; summary.ElapsedMs > 0 && !r.quiet {
+		fmt.Fprintln(w, completedLine(summary, r.msgs))
+	}
+	if cachedAt := output.Summary.CachedAt; cachedAt != nil && !r.quiet {
+		fmt.Fprintln(w, r.msgs.text("report.cached", cachedAt.Local().Format(time.TimeOnly), time.Since(*cachedAt).Round(time.Second)))
+	}
+	if output.Environment != nil && !r.quiet {
+		fmt.Fprintln(w, environmentLine(output.Environment, r.msgs))
+	}
+	return nil
+}
+
+// completedLine reports the run's wall-clock time and slowest check for performance triage
+func completedLine(summary *Summary, msgs messages) string {
+	line := msgs.text("report.completed", summary.Total, roundDuration(summary.ElapsedMs))
+	if summary.Slowest != nil {
+		line += msgs.text("report.slowest", summary.Slowest.Name, roundDuration(summary.Slowest.DurationMs))
+	}
+	return line
+}
+
+// roundDuration keeps millisecond precision below a second and tenths above it
+func roundDuration(ms int64) time.Duration {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Second {
+		return d
+	}
+	return d.Round(100 * time.Millisecond)
+}
+
+type jsonRenderer struct {
+	quiet, pretty bool
+}
+
+func (r jsonRenderer) Render(output ProbeOutput, w io.Writer) error {
+	if r.quiet {
+		output.Checks = nonPassing(output.Checks)
+	}
+	encoder := json.NewEncoder(w)
+	if r.pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(output)
+}
+
+// jsonArrayRenderer writes only the checks, as a top-level array, for log
+// pipelines that cannot read a nested key
+type jsonArrayRenderer struct {
+	quiet, pretty bool
+}
+
+func (r jsonArrayRenderer) Render(output ProbeOutput, w io.Writer) error {
+	checks := output.Checks
+	if r.quiet {
+		checks = nonPassing(checks)
+	}
+	if checks == nil {
+		checks = []CheckResult{}
+	}
+	encoder := json.NewEncoder(w)
+	if r.pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(checks)
+}
+
+// ndjsonRenderer writes one check per line followed by a {"summary": ...} line
+type ndjsonRenderer struct {
+	quiet bool
+}
+
+type ndjsonSummary struct {
+	Summary *Summary `json:"summary"`
+}
+
+func (r ndjsonRenderer) Render(output ProbeOutput, w io.Writer) error {
+	emit := newNDJSONStream(w, r.quiet)
+	for _, check := range output.Checks {
+		if err := emit(check); err != nil {
+			return err
+		}
+	}
+	return writeNDJSONSummary(w, output.Summary)
+}
+
+// newNDJSONStream returns a function that writes each result as its own line
+// as soon as it is called, so consumers see checks in completion order
+func newNDJSONStream(w io.Writer, quiet bool) func(CheckResult) error {
+	encoder := json.NewEncoder(w)
+	return func(check CheckResult) error {
+		if quiet && check.Status == "pass" {
+			return nil
+		}
+		return encoder.Encode(check)
+	}
+}
+
+func writeNDJSONSummary(w io.Writer, summary *Summary) error {
+	return json.NewEncoder(w).Encode(ndjsonSummary{Summary: summary})
+}
+
+type yamlRenderer struct {
+	quiet bool
+}
+
+func (r yamlRenderer) Render(output ProbeOutput, w io.Writer) error {
+	if r.quiet {
+		output.Checks = nonPassing(output.Checks)
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(output); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+type prometheusRenderer struct{}
+
+func (prometheusRenderer) Render(output ProbeOutput, w io.Writer) error {
+	return writePrometheus(w, output.Checks)
+}
+
+type junitRenderer struct{}
+
+func (junitRenderer) Render(output ProbeOutput, w io.Writer) error {
+	return writeJUnit(w, output.Checks)
+}
+
+// dnsResolved counts the per-endpoint DNS results that resolved, across regions
+func dnsResolved(results []CheckResult) (resolved, total int) {
+	for _, result := range results {
+		if result.check != "dns" || strings.HasSuffix(result.Name, dnsRollupName) {
+			continue
+		}
+		total++
+		if len(result.Addresses) > 0 {
+			resolved++
+		}
+	}
+	return resolved, total
+}
+
+// onelineRenderer prints only the summary counts, for shell prompts and status bars
+type onelineRenderer struct {
+	plain bool
+}
+
+func (r onelineRenderer) Render(output ProbeOutput, w io.Writer) error {
+	summary := output.Summary
+	counts := fmt.Sprintf("%d✓ %d⚠ %d✗", summary.Pass, summary.Warn, summary.Fail)
+	if r.plain {
+		counts = fmt.Sprintf("%d ok %d warn %d fail", summary.Pass, summary.Warn, summary.Fail)
+	}
+	var details []string
+	if len(summary.Regions) > 0 {
+		details = append(details, strings.Join(summary.Regions, ","))
+	}
+	if summary.ElapsedMs > 0 {
+		details = append(details, roundDuration(summary.ElapsedMs).String())
+	}
+	// Partial DNS is worth a glance even when the counts look mostly green
+	if resolved, total := dnsResolved(output.Checks); resolved < total {
+		details = append(details, fmt.Sprintf("DNS %d/%d", resolved, total))
+	}
+	line := "BCCE: " + counts
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
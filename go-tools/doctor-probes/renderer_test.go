@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewRendererUnknownFormat(t *testing.T) {
+	if _, err := newRenderer("xml", renderOptions{Plain: true}); err == nil || !strings.Contains(err.Error(), "human, json") {
+		t.Fatalf("newRenderer(xml) error = %v, want list of formats", err)
+	}
+}
+
+func TestRenderersQuiet(t *testing.T) {
+	results := []CheckResult{
+		{Name: "DNS - Bedrock Runtime", Status: "pass", Message: "Resolved"},
+		{Name: "TCP - Bedrock Runtime", Status: "fail", Message: "Timed out"},
+	}
+	output := ProbeOutput{Checks: results, Summary: newSummary(results, []string{"us-east-1"})}
+
+	for _, format := range []string{"json", "ndjson", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			renderer, err := newRenderer(format, renderOptions{Quiet: true, Plain: true, Pretty: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := renderer.Render(output, &buf); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			if strings.Contains(got, "DNS - Bedrock Runtime") {
+				t.Errorf("quiet %s output lists a passing check:\n%s", format, got)
+			}
+			if !strings.Contains(got, "TCP - Bedrock Runtime") || !strings.Contains(got, "total") {
+				t.Errorf("quiet %s output is missing the failure or summary:\n%s", format, got)
+			}
+		})
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	results := []CheckResult{
+		{Name: "us-east-1 / DNS - Bedrock Runtime", Status: "pass", Message: "Resolved", Region: "us-east-1", DurationMs: 12},
+		{Name: "us-east-1 / TCP - Bedrock Runtime", Status: "fail", Message: "Timed out", Fix: "Allow TCP 443", Region: "us-east-1"},
+	}
+	want := ProbeOutput{Checks: results, Summary: newSummary(results, []string{"us-east-1"})}
+	want.Summary.GeneratedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := (yamlRenderer{}).Render(want, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(strings.SplitN(buf.String(), "- name: us-east-1 / TCP", 2)[0], "fix:") {
+		t.Errorf("empty fix was not omitted:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(buf.String(), "checks:\n") || strings.Index(buf.String(), "status:") > strings.Index(buf.String(), "message:") {
+		t.Errorf("YAML field order does not match JSON:\n%s", buf.String())
+	}
+
+	var got ProbeOutput
+	if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestJSONPretty(t *testing.T) {
+	results := []CheckResult{{Name: "DNS - Bedrock Runtime", Status: "pass", Message: "Resolved"}}
+	output := ProbeOutput{Checks: results, Summary: newSummary(results, nil)}
+
+	for _, pretty := range []bool{false, true} {
+		renderer, err := newRenderer("json", renderOptions{Pretty: pretty})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := renderer.Render(output, &buf); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Count(buf.String(), "\n")
+		if pretty && !strings.Contains(buf.String(), "\n  \"checks\": [") {
+			t.Errorf("pretty JSON is not indented:\n%s", buf.String())
+		}
+		if !pretty && lines != 1 {
+			t.Errorf("compact JSON spans %d lines, want 1", lines)
+		}
+	}
+}
+
+func TestCompletedLine(t *testing.T) {
+	results := []CheckResult{
+		{Name: "DNS - Bedrock Runtime", Status: "pass", DurationMs: 40},
+		{Name: "TLS - Bedrock Runtime", Status: "pass", DurationMs: 800},
+		{Name: "TCP - Bedrock Runtime", Status: "pass", DurationMs: 120},
+	}
+	summary := newSummary(results, []string{"us-east-1"})
+	if summary.Slowest == nil || summary.Slowest.Name != "TLS - Bedrock Runtime" || summary.Slowest.DurationMs != 800 {
+		t.Fatalf("slowest = %+v, want the TLS check at 800ms", summary.Slowest)
+	}
+
+	summary.ElapsedMs = 1234
+	want := "Completed 3 checks in 1.2s (slowest: TLS - Bedrock Runtime, 800ms)"
+	if got := completedLine(summary, nil); got != want {
+		t.Errorf("completedLine = %q, want %q", got, want)
+	}
+}
+
+func TestJSONArrayEmpty(t *testing.T) {
+	renderer, _ := newRenderer("json-array", renderOptions{Quiet: true})
+	var buf bytes.Buffer
+	output := ProbeOutput{Checks: []CheckResult{{Name: "DNS", Status: "pass"}}}
+	if err := renderer.Render(output, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("quiet json-array output for a passing run = %s, want []", got)
+	}
+}
+
+func TestOnelineRenderer(t *testing.T) {
+	results := []CheckResult{
+		{Name: "DNS - Bedrock Runtime", Status: "pass"},
+		{Name: "TCP - Bedrock Runtime", Status: "pass"},
+		{Name: "TLS - Bedrock Runtime", Status: "warn"},
+	}
+	summary := newSummary(results, []string{"us-east-1"})
+	summary.ElapsedMs = 340
+	for _, tt := range []struct {
+		plain bool
+		want  string
+	}{
+		{false, "BCCE: 2✓ 1⚠ 0✗ (us-east-1, 340ms)\n"},
+		{true, "BCCE: 2 ok 1 warn 0 fail (us-east-1, 340ms)\n"},
+	} {
+		var buf bytes.Buffer
+		if err := (onelineRenderer{plain: tt.plain}).Render(ProbeOutput{Checks: results, Summary: summary}, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("plain=%v: got %q, want %q", tt.plain, buf.String(), tt.want)
+		}
+	}
+}
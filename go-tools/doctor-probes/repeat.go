@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// checkRuns accumulates one check's results across --repeat runs
+type checkRuns struct {
+	first      CheckResult
+	runs       int
+	passed     int
+	warned     bool
+	lastFail   CheckResult
+	durations  []int64
+	latencySum int64
+	latencies  int
+}
+
+// aggregateRuns folds the results of repeated runs into one result per check
+// name, in first-seen order. A run counts as a success unless it failed; the
+// check fails when its success rate is below minSuccessRate.
+func aggregateRuns(runs [][]CheckResult, minSuccessRate float64) []CheckResult {
+	var order []string
+	byName := map[string]*checkRuns{}
+	for _, run := range runs {
+		for _, result := range run {
+			agg, ok := byName[result.Name]
+			if !ok {
+				agg = &checkRuns{first: result}
+				byName[result.Name] = agg
+				order = append(order, result.Name)
+			}
+			agg.runs++
+			switch result.Status {
+			case "fail":
+				agg.lastFail = result
+			case "warn":
+				agg.warned = true
+				agg.passed++
+			default:
+				agg.passed++
+			}
+			if result.DurationMs > 0 {
+				agg.durations = append(agg.durations, result.DurationMs)
+			}
+			if result.LatencyMs > 0 {
+				agg.latencySum += result.LatencyMs
+				agg.latencies++
+			}
+		}
+	}
+
+	results := make([]CheckResult, 0, len(order))
+	for _, name := range order {
+		results = append(results, byName[name].result(minSuccessRate))
+	}
+	return results
+}
+
+func (a *checkRuns) result(minSuccessRate float64) CheckResult {
+	result := CheckResult{
+		Name:     a.first.Name,
+		Status:   "pass",
+		Category: a.first.Category,
+		Region:   a.first.Region,
+	}
+	rate := float64(a.passed) / float64(a.runs)
+	result.Message = fmt.Sprintf("%d/%d passed", a.passed, a.runs)
+
+	if len(a.durations) > 0 {
+		lowest, highest, sum := int64(math.MaxInt64), int64(0), int64(0)
+		for _, ms := range a.durations {
+			lowest, highest, sum = min(lowest, ms), max(highest, ms), sum+ms
+		}
+		result.DurationMs = sum / int64(len(a.durations))
+		result.Message += fmt.Sprintf("; latency min %dms, avg %dms, max %dms", lowest, result.DurationMs, highest)
+	}
+	if a.latencies > 0 {
+		result.LatencyMs = a.latencySum / int64(a.latencies)
+	}
+
+	switch {
+	case rate < minSuccessRate:
+		result.Status = "fail"
+		result.Message += fmt.Sprintf(", below --min-success-rate %g; last failure: %s", minSuccessRate, a.lastFail.Message)
+		result.Fix = a.lastFail.Fix
+		result.FixURL = a.lastFail.FixURL
+		result.ErrorKind = a.lastFail.ErrorKind
+	case a.runs > a.passed:
+		result.Message += fmt.Sprintf("; last failure: %s", a.lastFail.Message)
+	case a.warned:
+		result.Status = "warn"
+	}
+	return result
+}
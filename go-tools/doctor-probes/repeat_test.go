@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAggregateRuns(t *testing.T) {
+	var runs [][]CheckResult
+	for i := range 10 {
+		dns := CheckResult{Name: "DNS", Status: "pass", Message: "resolved", DurationMs: int64(10 + i), Category: "network"}
+		if i == 3 {
+			dns = CheckResult{Name: "DNS", Status: "fail", Message: "timeout", Fix: "check resolver", DurationMs: 100}
+		}
+		tcp := CheckResult{Name: "TCP", Status: "pass", DurationMs: 5}
+		if i%2 == 0 {
+			tcp.Status = "warn"
+		}
+		runs = append(runs, []CheckResult{dns, tcp, {Name: "Region", Status: "pass"}})
+	}
+
+	tests := []struct {
+		minSuccessRate float64
+		dnsStatus      string
+	}{
+		{1, "fail"},
+		{0.9, "pass"},
+	}
+	for _, tt := range tests {
+		results := aggregateRuns(runs, tt.minSuccessRate)
+		if len(results) != 3 || results[0].Name != "DNS" || results[1].Name != "TCP" || results[2].Name != "Region" {
+			t.Fatalf("aggregateRuns() = %+v, want DNS, TCP, Region", results)
+		}
+
+		dns := results[0]
+		if dns.Status != tt.dnsStatus || !strings.HasPrefix(dns.Message, "9/10 passed; latency min 10ms, avg 23ms, max 100ms") {
+			t.Errorf("min %g: DNS = %s %q", tt.minSuccessRate, dns.Status, dns.Message)
+		}
+		if !strings.Contains(dns.Message, "last failure: timeout") || dns.Category != "network" {
+			t.Errorf("min %g: DNS = %+v, want the last failure and category kept", tt.minSuccessRate, dns)
+		}
+		if (dns.Fix == "check resolver") != (tt.dnsStatus == "fail") {
+			t.Errorf("min %g: DNS fix = %q", tt.minSuccessRate, dns.Fix)
+		}
+
+		if tcp := results[1]; tcp.Status != "warn" || !strings.HasPrefix(tcp.Message, "10/10 passed") {
+			t.Errorf("TCP = %s %q, want warn with 10/10 passed", tcp.Status, tcp.Message)
+		}
+		if region := results[2]; region.Status != "pass" || region.Message != "10/10 passed" {
+			t.Errorf("Region = %s %q, want pass with 10/10 passed", region.Status, region.Message)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Read by the system resolver on Linux and macOS
+const resolvConfPath = "/etc/resolv.conf"
+
+// Search lists longer than this multiply failed lookups for little benefit
+const resolvSearchWarn = 3
+
+// resolvConf is the part of resolv.conf that decides how names are expanded
+type resolvConf struct {
+	Search []string
+	Ndots  int
+}
+
+// parseResolvConf follows resolv.conf(5): the last search or domain line
+// wins, and ndots defaults to 1 and is capped at 15
+func parseResolvConf(data string) resolvConf {
+	conf := resolvConf{Ndots: 1}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		switch fields[0] {
+		case "search":
+			conf.Search = fields[1:]
+		case "domain":
+			conf.Search = fields[1:min(2, len(fields))]
+		case "options":
+			for _, option := range fields[1:] {
+				if value, ok := strings.CutPrefix(option, "ndots:"); ok {
+					if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+						conf.Ndots = min(n, 15)
+					}
+				}
+			}
+		}
+	}
+	return conf
+}
+
+// runResolvConfChecks warns when the system resolver's search list would make
+// every Bedrock lookup try other domains before the real hostname
+func runResolvConfChecks(_ context.Context, cfg *Config, region string) []CheckResult {
+	name := "DNS - Search Domains"
+	if cfg.Resolver != "" {
+		return []CheckResult{{Name: name, Status: "pass", Message: fmt.Sprintf("Using --resolver %s; the system search list doesn't apply", cfg.Resolver)}}
+	}
+	data, err := os.ReadFile(resolvConfPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []CheckResult{{Name: name, Status: "pass", Message: fmt.Sprintf("No %s on this platform; search domains don't apply", resolvConfPath)}}
+	}
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("Failed to read %s: %v", resolvConfPath, err),
+			Fix:     fmt.Sprintf("Make %s readable so the system resolver can use it", resolvConfPath),
+		}}
+	}
+	return []CheckResult{checkResolvConf(name, parseResolvConf(string(data)), cfg.endpointHost("bedrock-runtime", region))}
+}
+
+// checkResolvConf reports how conf expands host
+func checkResolvConf(name string, conf resolvConf, host string) CheckResult {
+	fix := fmt.Sprintf("Trim the search list or lower ndots in %s (in Kubernetes, set dnsConfig options ndots:2 on the pod), or use fully-qualified hostnames with a trailing dot", resolvConfPath)
+	dots := strings.Count(host, ".")
+	// Names with fewer dots than ndots try every search domain first
+	if len(conf.Search) > 0 && dots < conf.Ndots {
+		return CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("ndots:%d makes %s (%d dots) try %d search domains first (%s), adding up to %d failed lookups", conf.Ndots, host, dots, len(conf.Search), strings.Join(conf.Search, " "), len(conf.Search)),
+			Fix:     fix,
+		}
+	}
+	if len(conf.Search) > resolvSearchWarn {
+		return CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("%d search domains in %s (%s); short or mistyped names will fail slowly", len(conf.Search), resolvConfPath, strings.Join(conf.Search, " ")),
+			Fix:     fix,
+		}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("%d search domains with ndots:%d; %s is looked up directly", len(conf.Search), conf.Ndots, host),
+	}
+}
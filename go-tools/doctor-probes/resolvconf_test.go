@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseResolvConf(t *testing.T) {
+	got := parseResolvConf(`# generated by kubelet
+nameserver 10.96.0.10
+domain example.com
+search default.svc.cluster.local svc.cluster.local cluster.local ec2.internal
+options ndots:5 timeout:2
+`)
+	want := resolvConf{Search: []string{"default.svc.cluster.local", "svc.cluster.local", "cluster.local", "ec2.internal"}, Ndots: 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseResolvConf = %+v, want %+v", got, want)
+	}
+	if got := parseResolvConf("nameserver 1.1.1.1\n"); got.Ndots != 1 || got.Search != nil {
+		t.Errorf("defaults = %+v, want ndots 1 and no search list", got)
+	}
+}
+
+func TestCheckResolvConf(t *testing.T) {
+	host := "bedrock-runtime.us-east-1.amazonaws.com"
+	tests := []struct {
+		name string
+		conf resolvConf
+		want string
+	}{
+		{"no search list", resolvConf{Ndots: 5}, "pass"},
+		{"short list", resolvConf{Search: []string{"corp.example"}, Ndots: 1}, "pass"},
+		{"kubernetes ndots", resolvConf{Search: []string{"svc.cluster.local", "cluster.local"}, Ndots: 5}, "warn"},
+		{"long list", resolvConf{Search: []string{"a.example", "b.example", "c.example", "d.example"}, Ndots: 1}, "warn"},
+	}
+	for _, tt := range tests {
+		if got := checkResolvConf("DNS - Search Domains", tt.conf, host); got.Status != tt.want {
+			t.Errorf("%s: status = %s (%s), want %s", tt.name, got.Status, got.Message, tt.want)
+		}
+	}
+}
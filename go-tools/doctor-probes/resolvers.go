@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Public resolvers the configured one is compared against
+var publicResolvers = []string{"8.8.8.8:53", "1.1.1.1:53"}
+
+// resolverAnswer is one resolver's view of a hostname
+type resolverAnswer struct {
+	Resolver string
+	IPs      []net.IP
+	Err      error
+}
+
+// private reports whether every answered address is private, as with a VPC endpoint
+func (a resolverAnswer) private() bool {
+	return len(a.IPs) > 0 && !slices.ContainsFunc(a.IPs, func(ip net.IP) bool { return !ip.IsPrivate() })
+}
+
+func (a resolverAnswer) String() string {
+	if a.Err != nil {
+		return fmt.Sprintf("%s failed (%v)", a.Resolver, a.Err)
+	}
+	addresses := make([]string, len(a.IPs))
+	for i, ip := range a.IPs {
+		addresses[i] = ip.String()
+	}
+	return fmt.Sprintf("%s: %s", a.Resolver, strings.Join(addresses, ", "))
+}
+
+// runResolverComparison resolves Bedrock Runtime through the system (or
+// --resolver) resolver and the public resolvers at once. It never fails:
+// the DNS check already reports a lookup that does not work.
+func runResolverComparison(ctx context.Context, cfg *Config, region string) []CheckResult {
+	name := "DNS - Resolver Comparison"
+	host := cfg.endpointHost("bedrock-runtime", region)
+
+	servers := append([]string{""}, publicResolvers...)
+	if cfg.Resolver != "" {
+		servers[0] = cfg.Resolver
+	}
+	answers := make([]resolverAnswer, len(servers))
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serverCfg := *cfg
+			serverCfg.Resolver = server
+			ips, err := lookupIP(ctx, &serverCfg, host)
+			answers[i] = resolverAnswer{Resolver: serverCfg.resolverName(), IPs: ips, Err: err}
+		}()
+	}
+	wg.Wait()
+	return []CheckResult{compareResolverAnswers(name, host, answers[0], answers[1:], time.Since(start).Milliseconds())}
+}
+
+// compareResolverAnswers interprets the configured resolver's answer against the public ones
+func compareResolverAnswers(name, host string, local resolverAnswer, public []resolverAnswer, durationMs int64) CheckResult {
+	var views []string
+	for _, answer := range append([]resolverAnswer{local}, public...) {
+		views = append(views, answer.String())
+	}
+	detail := strings.Join(views, "; ")
+	result := CheckResult{Name: name, Status: "pass", DurationMs: durationMs}
+
+	publicOK := slices.ContainsFunc(public, func(a resolverAnswer) bool { return a.Err == nil })
+	switch {
+	case local.Err != nil && publicOK:
+		result.Status = "warn"
+		result.Message = fmt.Sprintf("The %s resolver cannot resolve %s but public resolvers can (%s)", local.Resolver, host, detail)
+		result.Fix = "The local or VPC DNS setup is broken: check the resolver in /etc/resolv.conf, the VPC's DHCP options and any Route 53 Resolver rules or private hosted zones for amazonaws.com"
+		result.ErrorKind = errorKind(local.Err)
+	case local.Err != nil:
+		result.Status = "warn"
+		result.Message = fmt.Sprintf("No resolver could resolve %s (%s)", host, detail)
+		result.Fix = "Check internet connectivity; public resolvers may also be blocked by an egress firewall"
+		result.ErrorKind = errorKind(local.Err)
+	case !publicOK:
+		result.Message = fmt.Sprintf("Only the %s resolver answered for %s; public resolvers are unreachable, which is expected where outbound DNS is restricted (%s)", local.Resolver, host, detail)
+	case local.private() && !slices.ContainsFunc(public, resolverAnswer.private):
+		result.Message = fmt.Sprintf("Split-horizon DNS: the %s resolver returns private addresses for %s, public resolvers return public ones, so traffic goes through a VPC endpoint (%s)", local.Resolver, host, detail)
+	default:
+		// AWS rotates endpoint addresses, so differing public answers are expected
+		result.Message = fmt.Sprintf("All resolvers answered for %s consistently (%s)", host, detail)
+	}
+	if local.Err == nil {
+		for _, ip := range local.IPs {
+			result.Addresses = append(result.Addresses, ip.String())
+		}
+	}
+	return result
+}
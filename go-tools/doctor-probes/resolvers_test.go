@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestCompareResolverAnswers(t *testing.T) {
+	host := "bedrock-runtime.us-east-1.amazonaws.com"
+	public := resolverAnswer{Resolver: "8.8.8.8:53", IPs: []net.IP{net.ParseIP("52.94.1.10")}}
+	failed := func(resolver string) resolverAnswer {
+		return resolverAnswer{Resolver: resolver, Err: errors.New("i/o timeout")}
+	}
+	tests := []struct {
+		name    string
+		local   resolverAnswer
+		public  []resolverAnswer
+		status  string
+		message string
+	}{
+		{"consistent", resolverAnswer{Resolver: "system", IPs: []net.IP{net.ParseIP("52.94.2.20")}}, []resolverAnswer{public}, "pass", "consistently"},
+		{"split horizon", resolverAnswer{Resolver: "system", IPs: []net.IP{net.ParseIP("10.0.1.15")}}, []resolverAnswer{public}, "pass", "Split-horizon"},
+		{"local broken", failed("system"), []resolverAnswer{public, failed("1.1.1.1:53")}, "warn", "but public resolvers can"},
+		{"all broken", failed("system"), []resolverAnswer{failed("8.8.8.8:53")}, "warn", "No resolver"},
+		{"public blocked", resolverAnswer{Resolver: "system", IPs: []net.IP{net.ParseIP("52.94.2.20")}}, []resolverAnswer{failed("8.8.8.8:53")}, "pass", "public resolvers are unreachable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareResolverAnswers("DNS - Resolver Comparison", host, tt.local, tt.public, 0)
+			if got.Status != tt.status || !strings.Contains(got.Message, tt.message) {
+				t.Errorf("got %s %q, want %s containing %q", got.Status, got.Message, tt.status, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// runCacheEntry is the last completed run, replayed by --cache-ttl
+type runCacheEntry struct {
+	Key         string        `json:"key"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Checks      []CheckResult `json:"checks"`
+}
+
+func runCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bcce", "doctor-last-run.json"), nil
+}
+
+// runCacheKey identifies the settings a run used, so a cached result is only
+// replayed for the same regions, checks and options from the same build
+func runCacheKey(cfg *Config) string {
+	keyed := *cfg
+	keyed.CacheTTL = 0
+	// Jitter only changes when and in what order checks run, not their results
+	keyed.StartupJitter = 0
+	data, _ := json.Marshal(struct {
+		Version string
+		Config  Config
+	}{version, keyed})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadRunCache returns the cached checks for key if they are younger than ttl
+func loadRunCache(key string, ttl time.Duration) ([]CheckResult, time.Time, bool) {
+	path, err := runCachePath()
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var entry runCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		logger.Debug("ignoring unreadable run cache", "path", path, "error", err)
+		return nil, time.Time{}, false
+	}
+	if entry.Key != key {
+		logger.Debug("run cache is for different settings", "path", path)
+		return nil, time.Time{}, false
+	}
+	if age := time.Since(entry.GeneratedAt); age < 0 || age > ttl {
+		logger.Debug("run cache expired", "path", path, "age", age, "ttl", ttl)
+		return nil, time.Time{}, false
+	}
+	return entry.Checks, entry.GeneratedAt, true
+}
+
+// saveRunCache records a completed run; failures only cost the next run a re-probe
+func saveRunCache(key string, results []CheckResult) {
+	path, err := runCachePath()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(runCacheEntry{Key: key, GeneratedAt: time.Now().UTC(), Checks: results})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		logger.Debug("failed to create run cache directory", "path", path, "error", err)
+		return
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		logger.Debug("failed to write run cache", "path", path, "error", err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := newConfig()
+	cfg.Regions = []string{"us-east-1"}
+	cfg.Checks = []string{"dns"}
+	key := runCacheKey(cfg)
+
+	if _, _, ok := loadRunCache(key, time.Minute); ok {
+		t.Fatal("loadRunCache hit before anything was saved")
+	}
+
+	saved := []CheckResult{{Name: "DNS - Bedrock Runtime", Status: "pass", Message: "Resolved"}}
+	saveRunCache(key, saved)
+
+	got, at, ok := loadRunCache(key, time.Minute)
+	if !ok || !reflect.DeepEqual(got, saved) {
+		t.Fatalf("loadRunCache = %+v, %v, want the saved checks", got, ok)
+	}
+	if time.Since(at) > time.Minute {
+		t.Errorf("cached at %s, want a recent time", at)
+	}
+
+	if _, _, ok := loadRunCache(key, time.Nanosecond); ok {
+		t.Error("loadRunCache returned results older than the TTL")
+	}
+
+	other := *cfg
+	other.Regions = []string{"us-west-2"}
+	if _, _, ok := loadRunCache(runCacheKey(&other), time.Minute); ok {
+		t.Error("loadRunCache replayed results for different settings")
+	}
+
+	ttl := *cfg
+	ttl.CacheTTL = time.Hour
+	if runCacheKey(&ttl) != key {
+		t.Error("changing only the TTL should not invalidate the cache")
+	}
+}
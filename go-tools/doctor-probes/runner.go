@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Upper bound on the default worker count when --concurrency is not set
+const maxDefaultConcurrency = 16
+
+type probeJob struct {
+	index  int
+	region string
+	check  string
+}
+
+type jobResult struct {
+	job     probeJob
+	results []CheckResult
+}
+
+func buildJobs(cfg *Config) []probeJob {
+	var jobs []probeJob
+	for _, region := range cfg.Regions {
+		for _, check := range cfg.Checks {
+			if slices.Contains(globalChecks, check) {
+				continue
+			}
+			jobs = append(jobs, probeJob{index: len(jobs), region: region, check: check})
+		}
+	}
+
+	// Account- and host-wide checks only need to run once
+	for _, check := range cfg.Checks {
+		if slices.Contains(globalChecks, check) {
+			jobs = append(jobs, probeJob{index: len(jobs), region: cfg.Regions[0], check: check})
+		}
+	}
+
+	return jobs
+}
+
+// runJob runs one check category; every probe derives its timeout from ctx so
+// canceling it aborts work that is already in flight
+func runJob(ctx context.Context, cfg *Config, job probeJob) []CheckResult {
+	check, ok := lookupCheck(job.check)
+	if !ok {
+		return nil
+	}
+
+	// Probes read cfg.Timeout, so hand each category its own effective timeout
+	jobCfg := *cfg
+	jobCfg.Timeout = cfg.timeoutFor(job.check)
+	var results []CheckResult
+	if check.Latency && cfg.Samples > 1 {
+		results = runSamples(ctx, cfg.Samples, func() []CheckResult { return check.Run(ctx, &jobCfg, job.region) })
+	} else {
+		results = check.Run(ctx, &jobCfg, job.region)
+	}
+	for i := range results {
+		// Plugins may report their own category
+		if results[i].Category == "" {
+			results[i].Category = check.Group
+		}
+	}
+	if check.Latency && cfg.MaxLatency > 0 {
+		enforceMaxLatency(results, cfg.MaxLatency)
+	}
+	return results
+}
+
+// enforceMaxLatency fails results that completed but took longer than max
+func enforceMaxLatency(results []CheckResult, max time.Duration) {
+	for i := range results {
+		took := time.Duration(results[i].DurationMs) * time.Millisecond
+		if results[i].Status == "fail" || took <= max {
+			continue
+		}
+		results[i].Status = "fail"
+		results[i].Message = fmt.Sprintf("%s, but took %s, above --max-latency %s", results[i].Message, took, max)
+		results[i].Fix = "Find the slow hop with --verbose, or raise --max-latency if the deploy's SLA allows it"
+	}
+}
+
+// sleepJitter waits a random duration up to max, returning early if ctx is canceled
+func sleepJitter(ctx context.Context, max time.Duration) {
+	if max <= 0 {
+		return
+	}
+	delay := rand.N(max)
+	logger.Debug("sleeping for startup jitter", "delay", delay, "max", max)
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
+
+// runSDKCheck bounds an AWS SDK-backed check by the probe timeout and records its duration
+func runSDKCheck(ctx context.Context, cfg *Config, check func(ctx context.Context) []CheckResult) []CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	results := check(ctx)
+	for i := range results {
+		results[i].DurationMs = time.Since(start).Milliseconds()
+	}
+	return results
+}
+
+// errRunDeadline is the cancellation cause once --deadline has passed
+var errRunDeadline = errors.New("--deadline exceeded")
+
+// runChecks executes every enabled check across all regions using a bounded
+// worker pool and returns the results in a stable order. Canceling ctx aborts
+// in-flight checks and stops any that have not started yet. With --fail-fast
+// the first failure does the same and runChecks returns without waiting for
+// the remaining checks. Once --deadline passes, runChecks stops waiting too,
+// and reports every check without a result as skipped. A non-nil emit is
+// called with each result as soon as its check completes, before results are
+// put in order or merged across regions.
+func runChecks(ctx context.Context, cfg *Config, prefixRegion bool, emit func(CheckResult)) []CheckResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Only closed when the deadline passes, so a Ctrl-C still waits for workers as before
+	var deadline <-chan struct{}
+	if cfg.Deadline > 0 {
+		var cancelDeadline context.CancelFunc
+		ctx, cancelDeadline = context.WithTimeoutCause(ctx, cfg.Deadline, errRunDeadline)
+		defer cancelDeadline()
+		deadline = ctx.Done()
+	}
+
+	jobs := buildJobs(cfg)
+	resetIPRanges()
+	// Results are put back in job order below, so only the dispatch order changes
+	if cfg.StartupJitter > 0 {
+		rand.Shuffle(len(jobs), func(i, j int) { jobs[i], jobs[j] = jobs[j], jobs[i] })
+	}
+
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = min(len(jobs), maxDefaultConcurrency)
+	}
+
+	jobCh := make(chan probeJob)
+	// Buffered so workers never block once runChecks stops reading
+	resultCh := make(chan jobResult, len(jobs))
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobCh {
+				if ctx.Err() != nil {
+					logger.Debug("skipping canceled check", "check", job.check, "region", job.region)
+					continue
+				}
+
+				logger.Debug("running check", "check", job.check, "region", job.region)
+				results := runJob(ctx, cfg, job)
+				// A check cut short by cancellation did not complete, so it has no result to report
+				if ctx.Err() != nil {
+					logger.Debug("dropping canceled check", "check", job.check, "region", job.region)
+					continue
+				}
+				for i := range results {
+					finishResult(cfg, job, prefixRegion, &results[i])
+				}
+				resultCh <- jobResult{job: job, results: results}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobCh)
+		for _, job := range jobs {
+			select {
+			case jobCh <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	collector := newCollector(jobs)
+	collect := func(result jobResult) {
+		for _, check := range result.results {
+			collector.Add(check)
+			if emit != nil {
+				emit(check)
+			}
+		}
+	}
+reading:
+	for {
+		select {
+		case result, ok := <-resultCh:
+			if !ok {
+				break reading
+			}
+			collect(result)
+			if cfg.FailFast && exitCode(result.results) == exitFail {
+				cancel()
+				break reading
+			}
+		case <-deadline:
+			// Checks that ignore ctx may still be running; keep only what already finished
+			for drained := false; !drained; {
+				select {
+				case result, ok := <-resultCh:
+					if ok {
+						collect(result)
+					}
+					drained = !ok
+				default:
+					drained = true
+				}
+			}
+			for _, job := range collector.missing() {
+				collect(jobResult{job: job, results: []CheckResult{deadlineResult(cfg, job, prefixRegion)}})
+			}
+			break reading
+		}
+	}
+	collected := collector.byJob()
+	if prefixRegion {
+		collected = mergeClientResults(collected, len(cfg.Regions))
+	}
+
+	var results []CheckResult
+	for _, result := range collected {
+		for _, check := range result.results {
+			results = append(results, check)
+			if cfg.FailFast && check.Status == "fail" {
+				return results
+			}
+		}
+	}
+	return results
+}
+
+// finishResult fills in what every result of job carries, in the order the
+// report depends on: severity before codes and fix URLs, redaction before the
+// name gets its region prefix
+func finishResult(cfg *Config, job probeJob, prefixRegion bool, result *CheckResult) {
+	result.Region = job.region
+	result.check = job.check
+	cfg.applySeverity(result)
+	assignCode(result)
+	cfg.applyFixOverride(result)
+	if cfg.IncludeFixURLs {
+		addFixURL(result)
+	}
+	if !cfg.NoRedact {
+		redactResult(result)
+	}
+	// Prefix names so results from different regions stay distinguishable
+	if prefixRegion && !slices.Contains(globalChecks, job.check) {
+		result.Name = fmt.Sprintf("%s / %s", job.region, result.Name)
+	}
+}
+
+// deadlineResult stands in for a check that --deadline cut off
+func deadlineResult(cfg *Config, job probeJob, prefixRegion bool) CheckResult {
+	check, _ := lookupCheck(job.check)
+	result := CheckResult{
+		Name:     check.Name,
+		Status:   "warn",
+		Message:  fmt.Sprintf("Skipped: --deadline %s exceeded before this check finished", cfg.Deadline),
+		Fix:      "Raise --deadline, or lower --timeout so slow regions can't use up the run",
+		Category: check.Group,
+	}
+	finishResult(cfg, job, prefixRegion, &result)
+	return result
+}
+
+// mergeClientResults collapses client-scoped checks whose results came out the
+// same in every region into one entry without a region prefix. A check that
+// differs anywhere, or did not complete in every region, stays per region.
+func mergeClientResults(collected []jobResult, regions int) []jobResult {
+	byCheck := map[string][]int{}
+	for i, result := range collected {
+		if check, _ := lookupCheck(result.job.check); check.Scope == ScopeClient {
+			byCheck[result.job.check] = append(byCheck[result.job.check], i)
+		}
+	}
+
+	drop := map[int]bool{}
+	for _, indexes := range byCheck {
+		if len(indexes) != regions {
+			continue
+		}
+		merged := withoutRegion(collected[indexes[0]])
+		identical := true
+		for _, i := range indexes[1:] {
+			if !sameResults(merged, withoutRegion(collected[i])) {
+				identical = false
+				break
+			}
+		}
+		if !identical {
+			continue
+		}
+		collected[indexes[0]].results = merged
+		for _, i := range indexes[1:] {
+			drop[i] = true
+		}
+	}
+
+	kept := collected[:0]
+	for i, result := range collected {
+		if !drop[i] {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// withoutRegion returns a job's results with the region prefix and field removed
+func withoutRegion(result jobResult) []CheckResult {
+	results := make([]CheckResult, len(result.results))
+	for i, check := range result.results {
+		check.Name = strings.TrimPrefix(check.Name, result.job.region+" / ")
+		check.Region = ""
+		results[i] = check
+	}
+	return results
+}
+
+// sameResults compares results ignoring timings, which always differ between runs
+func sameResults(a, b []CheckResult) bool {
+	return slices.EqualFunc(a, b, func(x, y CheckResult) bool {
+		x.DurationMs, y.DurationMs = 0, 0
+		x.LatencyMs, y.LatencyMs = 0, 0
+		x.Latency, y.Latency = nil, nil
+		return reflect.DeepEqual(x, y)
+	})
+}
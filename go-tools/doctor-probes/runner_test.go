@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func proxyJob(index int, region, message string) jobResult {
+	return jobResult{
+		job: probeJob{index: index, region: region, check: "proxy"},
+		results: []CheckResult{{
+			Name:       region + " / Proxy - Configuration",
+			Status:     "fail",
+			Message:    message,
+			Region:     region,
+			DurationMs: int64(index),
+		}},
+	}
+}
+
+func TestMergeClientResults(t *testing.T) {
+	dns := jobResult{
+		job:     probeJob{index: 0, region: "us-east-1", check: "dns"},
+		results: []CheckResult{{Name: "us-east-1 / DNS - Bedrock Runtime", Status: "pass", Region: "us-east-1"}},
+	}
+	dnsWest := jobResult{
+		job:     probeJob{index: 2, region: "us-west-2", check: "dns"},
+		results: []CheckResult{{Name: "us-west-2 / DNS - Bedrock Runtime", Status: "pass", Region: "us-west-2"}},
+	}
+
+	merged := mergeClientResults([]jobResult{
+		dns,
+		proxyJob(1, "us-east-1", "Invalid proxy configuration"),
+		dnsWest,
+		proxyJob(3, "us-west-2", "Invalid proxy configuration"),
+	}, 2)
+	if len(merged) != 3 {
+		t.Fatalf("got %d job results, want 3 (identical proxy results merged)", len(merged))
+	}
+	proxy := merged[1].results[0]
+	if proxy.Name != "Proxy - Configuration" || proxy.Region != "" {
+		t.Errorf("merged result = %q in region %q, want an unprefixed name and no region", proxy.Name, proxy.Region)
+	}
+	if merged[0].results[0].Region != "us-east-1" || merged[2].results[0].Region != "us-west-2" {
+		t.Error("regional checks must stay per region")
+	}
+
+	kept := mergeClientResults([]jobResult{
+		proxyJob(0, "us-east-1", "Invalid proxy configuration"),
+		proxyJob(1, "us-west-2", "Something else"),
+	}, 2)
+	if len(kept) != 2 {
+		t.Errorf("got %d job results, want 2 when the regions differ", len(kept))
+	}
+
+	partial := mergeClientResults([]jobResult{proxyJob(0, "us-east-1", "Invalid proxy configuration")}, 2)
+	if len(partial) != 1 || partial[0].results[0].Region != "us-east-1" {
+		t.Error("a check that did not complete in every region must not be merged")
+	}
+}
+
+func TestEnforceMaxLatency(t *testing.T) {
+	results := []CheckResult{
+		{Name: "fast", Status: "pass", Message: "Connected", DurationMs: 50},
+		{Name: "slow", Status: "warn", Message: "Resolved slowly", DurationMs: 900},
+		{Name: "failed", Status: "fail", Message: "Refused", DurationMs: 900},
+	}
+	enforceMaxLatency(results, 500*time.Millisecond)
+
+	if results[0].Status != "pass" {
+		t.Errorf("fast result = %s, want pass", results[0].Status)
+	}
+	if results[1].Status != "fail" || !strings.Contains(results[1].Message, "above --max-latency 500ms") {
+		t.Errorf("slow result = %s %q, want a max-latency failure", results[1].Status, results[1].Message)
+	}
+	if results[2].Message != "Refused" {
+		t.Errorf("an existing failure was rewritten: %q", results[2].Message)
+	}
+}
+
+func TestRunChecksOrderWithJitter(t *testing.T) {
+	regions := []string{"us-east-1", "us-west-2", "eu-west-1", "eu-central-1", "ap-northeast-1", "ap-southeast-2"}
+	cfg := &Config{Regions: regions, Checks: []string{"region"}, Timeout: time.Second, StartupJitter: time.Hour, Concurrency: 1}
+
+	// Shuffled dispatch must still report results in region order
+	for range 5 {
+		var got []string
+		for _, result := range runChecks(context.Background(), cfg, true, nil) {
+			got = append(got, result.Region)
+		}
+		if !slices.Equal(got, regions) {
+			t.Fatalf("runChecks() regions = %v, want %v", got, regions)
+		}
+	}
+}
+
+func TestSleepJitterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	sleepJitter(ctx, time.Hour)
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("sleepJitter() with a canceled context took %s", took)
+	}
+}
+
+func TestRunChecksDeadline(t *testing.T) {
+	// The slow check ignores ctx, like a probe stuck in a syscall
+	started := make(chan struct{}, 2)
+	slow := fakeCheck("slow", "network", ScopeRegional, 0)
+	slow.Run = func(context.Context, *Config, string) []CheckResult {
+		started <- struct{}{}
+		time.Sleep(2 * time.Second)
+		return []CheckResult{{Name: "Slow", Status: "pass", Message: "ok"}}
+	}
+	withChecks(t, fakeCheck("fast", "network", ScopeRegional, 0, CheckResult{Name: "Fast", Status: "pass", Message: "ok"}), slow)
+	cfg := newConfig()
+	cfg.Regions = []string{"us-east-1", "us-west-2"}
+	cfg.Checks = []string{"fast", "slow"}
+	cfg.Deadline = 100 * time.Millisecond
+
+	start := time.Now()
+	results := runChecks(context.Background(), cfg, true, nil)
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("runChecks took %s, want it to stop at the %s deadline", took, cfg.Deadline)
+	}
+	var got []string
+	for _, result := range results {
+		got = append(got, result.Name+" "+result.Status)
+		if result.check == "slow" && !strings.Contains(result.Message, "--deadline 100ms exceeded") {
+			t.Errorf("skipped result message = %q", result.Message)
+		}
+	}
+	want := []string{"us-east-1 / Fast pass", "us-east-1 / slow warn", "us-west-2 / Fast pass", "us-west-2 / slow warn"}
+	if !slices.Equal(got, want) {
+		t.Errorf("runChecks() = %v, want %v", got, want)
+	}
+	// Both stragglers have looked up the registry, so withChecks can restore it
+	<-started
+	<-started
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// LatencyStats summarizes a probe's duration across --samples runs
+type LatencyStats struct {
+	Samples int   `json:"samples" yaml:"samples"`
+	P50Ms   int64 `json:"p50_ms" yaml:"p50_ms"`
+	P95Ms   int64 `json:"p95_ms" yaml:"p95_ms"`
+	MaxMs   int64 `json:"max_ms" yaml:"max_ms"`
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// runSamples runs a latency-bearing check samples times back to back and
+// folds the runs into one result per name. A sample that failed is reported
+// rather than averaged away; percentiles only cover samples that completed.
+func runSamples(ctx context.Context, samples int, run func() []CheckResult) []CheckResult {
+	var order []string
+	byName := map[string][]CheckResult{}
+	for i := 0; i < samples; i++ {
+		if i > 0 && ctx.Err() != nil {
+			break
+		}
+		logger.Debug("running latency sample", "sample", i+1, "samples", samples)
+		for _, result := range run() {
+			if _, ok := byName[result.Name]; !ok {
+				order = append(order, result.Name)
+			}
+			byName[result.Name] = append(byName[result.Name], result)
+		}
+	}
+
+	results := make([]CheckResult, 0, len(order))
+	for _, name := range order {
+		results = append(results, foldSamples(byName[name]))
+	}
+	return results
+}
+
+// foldSamples reports the first failed sample, else the first warning, else
+// the first sample, with the latency percentiles of every completed sample
+func foldSamples(runs []CheckResult) CheckResult {
+	result := runs[0]
+	failed := 0
+	var durations []int64
+	for _, run := range runs {
+		switch {
+		case run.Status == "fail":
+			if failed == 0 {
+				result = run
+			}
+			failed++
+			continue
+		case run.Status == "warn" && result.Status == "pass":
+			result = run
+		}
+		durations = append(durations, run.DurationMs)
+	}
+
+	if failed > 0 && failed < len(runs) {
+		result.Message += fmt.Sprintf(" (failed %d of %d samples)", failed, len(runs))
+	}
+	slices.Sort(durations)
+	// Results like the DNS rollup carry no timing of their own
+	if len(durations) == 0 || durations[len(durations)-1] == 0 {
+		return result
+	}
+	result.Latency = &LatencyStats{
+		Samples: len(durations),
+		P50Ms:   percentile(durations, 50),
+		P95Ms:   percentile(durations, 95),
+		MaxMs:   durations[len(durations)-1],
+	}
+	// --max-latency and the timing columns read the typical sample
+	result.DurationMs = result.Latency.P50Ms
+	result.Message += fmt.Sprintf("; latency p50 %s, p95 %s, max %s over %d samples",
+		time.Duration(result.Latency.P50Ms)*time.Millisecond,
+		time.Duration(result.Latency.P95Ms)*time.Millisecond,
+		time.Duration(result.Latency.MaxMs)*time.Millisecond,
+		result.Latency.Samples)
+	return result
+}
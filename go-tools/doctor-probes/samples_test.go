@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	for _, tt := range []struct {
+		p    float64
+		want int64
+	}{{50, 50}, {95, 100}, {10, 10}, {0, 10}} {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(p%g) = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile([]int64{7}, 95); got != 7 {
+		t.Errorf("percentile of one sample = %d, want 7", got)
+	}
+}
+
+func TestRunSamples(t *testing.T) {
+	durations := []int64{30, 10, 0, 20, 40}
+	sample := 0
+	results := runSamples(context.Background(), len(durations), func() []CheckResult {
+		ms := durations[sample]
+		sample++
+		tcp := CheckResult{Name: "TCP - Bedrock Runtime", Status: "pass", Message: "Connected", DurationMs: ms}
+		if ms == 0 {
+			tcp = CheckResult{Name: "TCP - Bedrock Runtime", Status: "fail", Message: "Timed out", Fix: "Check the firewall"}
+		}
+		return []CheckResult{tcp, {Name: "DNS - Bedrock Endpoints", Status: "pass", Message: "1 of 1 resolved"}}
+	})
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want one per name", len(results))
+	}
+
+	tcp := results[0]
+	if tcp.Status != "fail" || tcp.Fix != "Check the firewall" || !strings.HasPrefix(tcp.Message, "Timed out (failed 1 of 5 samples)") {
+		t.Errorf("TCP = %s %q, want the failed sample reported", tcp.Status, tcp.Message)
+	}
+	if want := (LatencyStats{Samples: 4, P50Ms: 20, P95Ms: 40, MaxMs: 40}); tcp.Latency == nil || *tcp.Latency != want {
+		t.Errorf("latency = %+v, want %+v", tcp.Latency, want)
+	}
+	if tcp.DurationMs != 20 || !strings.Contains(tcp.Message, "p50 20ms, p95 40ms, max 40ms over 4 samples") {
+		t.Errorf("TCP duration %dms, message %q, want the percentiles", tcp.DurationMs, tcp.Message)
+	}
+
+	if rollup := results[1]; rollup.Latency != nil || rollup.Message != "1 of 1 resolved" {
+		t.Errorf("a result without timings = %+v, want it unchanged", rollup)
+	}
+}
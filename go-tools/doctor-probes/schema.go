@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// outputSchema is the JSON Schema of the json, json-array and ndjson reports;
+// the schema command prints it for consumers to vendor
+//
+//go:embed schema.json
+var outputSchema []byte
+
+// jsonSchemaFormats are the formats --validate-output can check
+var jsonSchemaFormats = []string{"json", "json-array", "ndjson"}
+
+// schemaNode is one decoded schema object
+type schemaNode = map[string]any
+
+func loadOutputSchema() schemaNode {
+	var schema schemaNode
+	if err := json.Unmarshal(outputSchema, &schema); err != nil {
+		panic(fmt.Sprintf("embedded schema.json is invalid: %v", err))
+	}
+	return schema
+}
+
+// validateReport checks a rendered report in format against the schema
+func validateReport(format string, report []byte) error {
+	root := loadOutputSchema()
+	check := func(doc []byte, node schemaNode) error {
+		decoder := json.NewDecoder(bytes.NewReader(doc))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		if problems := validateValue(root, node, value, "$"); len(problems) > 0 {
+			return errors.New(strings.Join(problems, "; "))
+		}
+		return nil
+	}
+	defs, _ := root["$defs"].(schemaNode)
+	checkResult, _ := defs["CheckResult"].(schemaNode)
+
+	switch format {
+	case "json":
+		return check(report, root)
+	case "json-array":
+		return check(report, schemaNode{"type": "array", "items": checkResult})
+	case "ndjson":
+		summaryLine := schemaNode{
+			"type":                 "object",
+			"properties":           schemaNode{"summary": schemaNode{"$ref": "#/$defs/Summary"}},
+			"required":             []any{"summary"},
+			"additionalProperties": false,
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(report))
+		scanner.Buffer(nil, 1<<20)
+		for n := 1; scanner.Scan(); n++ {
+			node := checkResult
+			if bytes.HasPrefix(scanner.Bytes(), []byte(`{"summary"`)) {
+				node = summaryLine
+			}
+			if err := check(scanner.Bytes(), node); err != nil {
+				return fmt.Errorf("line %d: %w", n, err)
+			}
+		}
+		return scanner.Err()
+	}
+	return fmt.Errorf("format %s has no schema", format)
+}
+
+// validateValue checks value against the subset of JSON Schema that
+// schema.json uses: type, enum, properties, required, additionalProperties,
+// items and local $refs. It returns one problem per mismatch.
+func validateValue(root, node schemaNode, value any, at string) []string {
+	if ref, ok := node["$ref"].(string); ok {
+		target, ok := resolveSchemaRef(root, ref)
+		if !ok {
+			return []string{fmt.Sprintf("%s: schema reference %s does not resolve", at, ref)}
+		}
+		return validateValue(root, target, value, at)
+	}
+	if enum, ok := node["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return []string{fmt.Sprintf("%s: %v is not one of %v", at, value, enum)}
+	}
+	if types := schemaTypes(node["type"]); types != nil && !slices.Contains(types, jsonType(value)) {
+		return []string{fmt.Sprintf("%s: is %s, want %s", at, jsonType(value), strings.Join(types, " or "))}
+	}
+
+	var problems []string
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := node["properties"].(schemaNode)
+		required, _ := node["required"].([]any)
+		for _, name := range required {
+			if _, ok := value[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required field %q", at, name))
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := properties[name].(schemaNode); ok {
+				problems = append(problems, validateValue(root, property, value[name], at+"."+name)...)
+				continue
+			}
+			switch additional := node["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					problems = append(problems, fmt.Sprintf("%s: unexpected field %q", at, name))
+				}
+			case schemaNode:
+				problems = append(problems, validateValue(root, additional, value[name], at+"."+name)...)
+			}
+		}
+	case []any:
+		if items, ok := node["items"].(schemaNode); ok {
+			for i, item := range value {
+				problems = append(problems, validateValue(root, items, item, fmt.Sprintf("%s[%d]", at, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+// resolveSchemaRef follows a #/$defs/Name reference
+func resolveSchemaRef(root schemaNode, ref string) (schemaNode, bool) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, false
+	}
+	defs, _ := root["$defs"].(schemaNode)
+	target, ok := defs[name].(schemaNode)
+	return target, ok
+}
+
+func schemaTypes(raw any) []string {
+	switch raw := raw.(type) {
+	case string:
+		return []string{raw}
+	case []any:
+		var types []string
+		for _, t := range raw {
+			types = append(types, t.(string))
+		}
+		return types
+	}
+	return nil
+}
+
+// jsonType names a value decoded with UseNumber in JSON Schema terms
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
+
+// writeSchema prints the embedded schema for the schema command
+func writeSchema(w io.Writer) {
+	w.Write(outputSchema)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGoldenReportsMatchSchema(t *testing.T) {
+	for _, format := range jsonSchemaFormats {
+		report, err := os.ReadFile(filepath.Join("testdata", "golden", format+".golden"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := validateReport(format, report); err != nil {
+			t.Errorf("%s golden report: %v", format, err)
+		}
+	}
+}
+
+// Every JSON field of the report types must be in the schema and vice versa,
+// so a new field can't ship without a schema change
+func TestSchemaCoversOutputTypes(t *testing.T) {
+	root := loadOutputSchema()
+	defs := root["$defs"].(schemaNode)
+	types := map[string]any{"": ProbeOutput{}, "CheckResult": CheckResult{}, "Summary": Summary{}, "SlowestCheck": SlowestCheck{}, "Environment": Environment{}, "LatencyStats": LatencyStats{}}
+	for def, value := range types {
+		node := root
+		if def != "" {
+			node = defs[def].(schemaNode)
+		}
+		var inSchema []string
+		for name := range node["properties"].(schemaNode) {
+			inSchema = append(inSchema, name)
+		}
+		var inStruct []string
+		typ := reflect.TypeOf(value)
+		for i := range typ.NumField() {
+			if tag, ok := typ.Field(i).Tag.Lookup("json"); ok {
+				inStruct = append(inStruct, strings.Split(tag, ",")[0])
+			}
+		}
+		slices.Sort(inSchema)
+		slices.Sort(inStruct)
+		if !slices.Equal(inSchema, inStruct) {
+			t.Errorf("%s: schema has %v, struct has %v", typ.Name(), inSchema, inStruct)
+		}
+	}
+}
+
+func TestValidateReportRejectsDrift(t *testing.T) {
+	tests := []struct {
+		format, report, problem string
+	}{
+		{"json", `{"checks":[{"name":"DNS","status":"pass","message":"ok","latency_p99":3}]}`, `unexpected field "latency_p99"`},
+		{"json", `{"checks":[{"name":"DNS","status":"ok","message":"ok"}]}`, "is not one of"},
+		{"json", `{"checks":[{"name":"DNS","status":"pass","message":"ok","duration_ms":"3"}]}`, "want integer"},
+		{"json", `{"checks":[{"name":"DNS","status":"pass"}]}`, `missing required field "message"`},
+		{"json-array", `[{"name":"DNS","status":"pass","message":"ok"},{}]`, "$[1]"},
+		{"ndjson", "{\"name\":\"DNS\",\"status\":\"pass\",\"message\":\"ok\"}\n{\"summary\":{\"total\":1}}\n", "line 2"},
+	}
+	for _, tt := range tests {
+		err := validateReport(tt.format, []byte(tt.report))
+		if err == nil || !strings.Contains(err.Error(), tt.problem) {
+			t.Errorf("validateReport(%s, %s) = %v; want an error mentioning %q", tt.format, tt.report, err, tt.problem)
+		}
+	}
+	if err := validateReport("json", []byte(`{"checks":null}`)); err != nil {
+		t.Errorf("validateReport of an empty run = %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+)
+
+// sdkEndpoint is where the SDK sends one service's authenticated calls
+type sdkEndpoint struct {
+	Service string
+	// Prefix is the hostname prefix the network checks probe for the service
+	Prefix string
+	URL    string
+	// Why the SDK picked URL over the regional default, if it did
+	Source string
+}
+
+// endpointURLSource names the setting behind a service's base endpoint, in
+// the order the SDK checks them, or the FIPS or dual-stack setting that
+// changed the regional hostname
+func endpointURLSource(envSuffix string, base *string, overridden, dualStack, fips bool) string {
+	switch {
+	case overridden:
+		return "--endpoint-url or the config file's endpoints"
+	case os.Getenv("AWS_ENDPOINT_URL_"+envSuffix) != "":
+		return "AWS_ENDPOINT_URL_" + envSuffix
+	case os.Getenv("AWS_ENDPOINT_URL") != "":
+		return "AWS_ENDPOINT_URL"
+	case base != nil:
+		return "endpoint_url in the shared config profile"
+	case dualStack:
+		return "AWS_USE_DUALSTACK_ENDPOINT or use_dualstack_endpoint"
+	case fips:
+		return "AWS_USE_FIPS_ENDPOINT or use_fips_endpoint"
+	}
+	return ""
+}
+
+// resolveSDKEndpoints resolves Bedrock Runtime and Bedrock the way the
+// authenticated checks' clients do, including AWS_ENDPOINT_URL_* and the
+// FIPS and dual-stack settings
+func resolveSDKEndpoints(ctx context.Context, cfg *Config, awsCfg aws.Config) ([]sdkEndpoint, error) {
+	runtimeOptions := newRuntimeClient(cfg, awsCfg).Options()
+	runtimeEndpoint, err := runtimeOptions.EndpointResolverV2.ResolveEndpoint(ctx, bedrockruntime.EndpointParameters{
+		Region:       aws.String(runtimeOptions.Region),
+		UseFIPS:      aws.Bool(runtimeOptions.EndpointOptions.UseFIPSEndpoint == aws.FIPSEndpointStateEnabled),
+		UseDualStack: aws.Bool(runtimeOptions.EndpointOptions.UseDualStackEndpoint == aws.DualStackEndpointStateEnabled),
+		Endpoint:     runtimeOptions.BaseEndpoint,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("resolving Bedrock Runtime: %w", err)
+	}
+	controlOptions := newControlClient(cfg, awsCfg).Options()
+	controlEndpoint, err := controlOptions.EndpointResolverV2.ResolveEndpoint(ctx, bedrock.EndpointParameters{
+		Region:       aws.String(controlOptions.Region),
+		UseFIPS:      aws.Bool(controlOptions.EndpointOptions.UseFIPSEndpoint == aws.FIPSEndpointStateEnabled),
+		UseDualStack: aws.Bool(controlOptions.EndpointOptions.UseDualStackEndpoint == aws.DualStackEndpointStateEnabled),
+		Endpoint:     controlOptions.BaseEndpoint,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("resolving Bedrock: %w", err)
+	}
+
+	_, _, runtimeOverridden := cfg.endpointOverride("bedrock-runtime")
+	_, _, controlOverridden := cfg.endpointOverride("bedrock")
+	return []sdkEndpoint{
+		{Service: "Bedrock Runtime", Prefix: "bedrock-runtime", URL: runtimeEndpoint.URI.String(), Source: endpointURLSource("BEDROCK_RUNTIME", runtimeOptions.BaseEndpoint, runtimeOverridden,
+			runtimeOptions.EndpointOptions.UseDualStackEndpoint == aws.DualStackEndpointStateEnabled, runtimeOptions.EndpointOptions.UseFIPSEndpoint == aws.FIPSEndpointStateEnabled)},
+		{Service: "Bedrock", Prefix: "bedrock", URL: controlEndpoint.URI.String(), Source: endpointURLSource("BEDROCK", controlOptions.BaseEndpoint, controlOverridden,
+			controlOptions.EndpointOptions.UseDualStackEndpoint == aws.DualStackEndpointStateEnabled, controlOptions.EndpointOptions.UseFIPSEndpoint == aws.FIPSEndpointStateEnabled)},
+	}, nil
+}
+
+// retrySummary describes the SDK retry settings, which AWS_RETRY_MODE and
+// AWS_MAX_ATTEMPTS can change
+func retrySummary(awsCfg aws.Config) string {
+	mode := awsCfg.RetryMode
+	if mode == "" {
+		mode = aws.RetryModeStandard
+	}
+	attempts := awsCfg.RetryMaxAttempts
+	if attempts == 0 {
+		attempts = 3
+	}
+	return fmt.Sprintf("retry mode %s, %d attempts", mode, attempts)
+}
+
+// runSDKEndpointChecks reports the endpoints the SDK resolves for the
+// authenticated checks and warns where they differ from the hosts the network
+// checks probe, so an AWS_ENDPOINT_URL_* override can't hide behind a passing
+// DNS or TLS check
+func runSDKEndpointChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	name := "SDK Endpoint"
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load AWS config: %v", err),
+			Fix:     "Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+		}}
+	}
+	endpoints, err := resolveSDKEndpoints(ctx, cfg, awsCfg)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("The SDK cannot resolve an endpoint in %s: %v", region, err),
+			Fix:     "Check AWS_USE_FIPS_ENDPOINT and AWS_USE_DUALSTACK_ENDPOINT; not every region has FIPS or dual-stack Bedrock endpoints",
+		}}
+	}
+
+	var results []CheckResult
+	for _, endpoint := range endpoints {
+		result := sdkEndpointResult(name+" - "+endpoint.Service, endpoint, cfg.endpointHost(endpoint.Prefix, region))
+		if result.Status == "pass" {
+			result.Message += " (" + retrySummary(awsCfg) + ")"
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// sdkEndpointResult compares the SDK's endpoint with the host the network checks probe
+func sdkEndpointResult(name string, endpoint sdkEndpoint, probed string) CheckResult {
+	host := endpoint.URL
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+
+	via := ""
+	if endpoint.Source != "" {
+		via = " (from " + endpoint.Source + ")"
+	}
+	if !strings.EqualFold(host, probed) {
+		fix := fmt.Sprintf("Unset %s if the override is left over, or pass --endpoint-url so the network checks probe the same host", endpoint.Source)
+		if strings.HasPrefix(endpoint.Source, "AWS_USE_") {
+			fix = fmt.Sprintf("Turn off %s, or pass --fips or --endpoint-url to match, so the network checks probe the same host", endpoint.Source)
+		}
+		return CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("Authenticated calls go to %s%s, but the network checks probe %s", endpoint.URL, via, probed),
+			Fix:     fix,
+		}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("The SDK resolves %s%s, the host the network checks probe", endpoint.URL, via),
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSDKEndpointChecks(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	t.Setenv("AWS_USE_FIPS_ENDPOINT", "")
+	t.Setenv("AWS_USE_DUALSTACK_ENDPOINT", "")
+
+	t.Setenv("AWS_ENDPOINT_URL_BEDROCK_RUNTIME", "")
+	for _, result := range runSDKEndpointChecks(context.Background(), newConfig(), "us-east-1") {
+		if result.Status != "pass" {
+			t.Errorf("%s = %s: %s; want pass with no overrides", result.Name, result.Status, result.Message)
+		}
+	}
+
+	t.Setenv("AWS_ENDPOINT_URL_BEDROCK_RUNTIME", "https://gateway.example.com")
+	results := runSDKEndpointChecks(context.Background(), newConfig(), "us-east-1")
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].Status != "warn" || !strings.Contains(results[0].Message, "AWS_ENDPOINT_URL_BEDROCK_RUNTIME") {
+		t.Errorf("runtime = %s: %s; want a warning naming the override", results[0].Status, results[0].Message)
+	}
+	if results[1].Status != "pass" {
+		t.Errorf("control plane = %s: %s; want pass", results[1].Status, results[1].Message)
+	}
+
+	cfg := newConfig()
+	cfg.EndpointURL = "https://gateway.example.com"
+	if result := runSDKEndpointChecks(context.Background(), cfg, "us-east-1")[0]; result.Status != "pass" {
+		t.Errorf("with --endpoint-url = %s: %s; want pass", result.Status, result.Message)
+	}
+
+	t.Setenv("AWS_ENDPOINT_URL_BEDROCK_RUNTIME", "")
+	t.Setenv("AWS_USE_DUALSTACK_ENDPOINT", "true")
+	if result := runSDKEndpointChecks(context.Background(), newConfig(), "us-east-1")[0]; result.Status != "warn" || !strings.Contains(result.Message, "api.aws") {
+		t.Errorf("dual-stack = %s: %s; want a warning naming the dual-stack host", result.Status, result.Message)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SDK behavior toggles the report shows, and the values each accepts; nil
+// means any value
+var sdkToggles = []struct {
+	name   string
+	values []string
+}{
+	{"AWS_SDK_LOAD_CONFIG", nil},
+	{"AWS_EC2_METADATA_DISABLED", []string{"true", "false"}},
+	{"AWS_STS_REGIONAL_ENDPOINTS", []string{"regional", "legacy"}},
+	{"AWS_USE_FIPS_ENDPOINT", []string{"true", "false"}},
+	{"AWS_USE_DUALSTACK_ENDPOINT", []string{"true", "false"}},
+	{"AWS_RETRY_MODE", []string{"standard", "adaptive"}},
+	{"AWS_MAX_ATTEMPTS", nil},
+}
+
+// runSDKEnvChecks reports the environment toggles that change how the AWS SDKs
+// load config and reach AWS, and warns on values or combinations known to
+// break Bedrock calls. It only reads the environment.
+func runSDKEnvChecks(env *Environment) []CheckResult {
+	name := "SDK Env"
+	var set []string
+	var results []CheckResult
+	for _, toggle := range sdkToggles {
+		value := os.Getenv(toggle.name)
+		if value == "" {
+			continue
+		}
+		set = append(set, toggle.name+"="+value)
+		if toggle.values != nil && !containsFold(toggle.values, value) {
+			results = append(results, CheckResult{
+				Name:    name + " - " + toggle.name,
+				Status:  "warn",
+				Message: fmt.Sprintf("%s=%q is not one of %s; the SDKs reject or ignore it", toggle.name, value, strings.Join(toggle.values, ", ")),
+				Fix:     fmt.Sprintf("Set %s to %s, or unset it", toggle.name, strings.Join(toggle.values, " or ")),
+			})
+		}
+	}
+	if value := os.Getenv("AWS_MAX_ATTEMPTS"); value != "" {
+		if attempts, err := strconv.Atoi(value); err != nil || attempts < 1 {
+			results = append(results, CheckResult{
+				Name:    name + " - AWS_MAX_ATTEMPTS",
+				Status:  "warn",
+				Message: fmt.Sprintf("AWS_MAX_ATTEMPTS=%q is not a positive number; the Go SDK fails to load its config", value),
+				Fix:     "Set AWS_MAX_ATTEMPTS to a whole number such as 3, or unset it",
+			})
+		}
+	}
+
+	// The instance role is only reachable through IMDS; ECS and Lambda use their own endpoints
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") && (env.Platform == "ec2" || env.Platform == "eks") &&
+		os.Getenv("AWS_ACCESS_KEY_ID") == "" && os.Getenv("AWS_PROFILE") == "" && os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE") == "" {
+		results = append(results, CheckResult{
+			Name:    name + " - AWS_EC2_METADATA_DISABLED",
+			Status:  "warn",
+			Message: fmt.Sprintf("IMDS is disabled on this %s host and no other credentials are set, so the instance role can't be used", strings.ToUpper(env.Platform)),
+			Fix:     "Unset AWS_EC2_METADATA_DISABLED to use the instance role, or provide credentials through AWS_PROFILE or AWS_ACCESS_KEY_ID",
+		})
+	}
+	if strings.EqualFold(os.Getenv("AWS_STS_REGIONAL_ENDPOINTS"), "legacy") {
+		results = append(results, CheckResult{
+			Name:    name + " - AWS_STS_REGIONAL_ENDPOINTS",
+			Status:  "warn",
+			Message: "AWS_STS_REGIONAL_ENDPOINTS=legacy sends older SDKs' STS calls to sts.amazonaws.com in us-east-1, which a VPC endpoint or egress allowlist for your region won't cover",
+			Fix:     "Set AWS_STS_REGIONAL_ENDPOINTS=regional",
+		})
+	}
+
+	summary := CheckResult{Name: name, Status: "pass", Message: "No SDK toggles are set; the SDK defaults apply"}
+	if len(set) > 0 {
+		summary.Message = "Set: " + strings.Join(set, ", ")
+	}
+	return append([]CheckResult{summary}, results...)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSDKEnvChecks(t *testing.T) {
+	for _, toggle := range sdkToggles {
+		t.Setenv(toggle.name, "")
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		platform string
+		want     []string
+	}{
+		{"defaults", nil, "", []string{"pass"}},
+		{"valid values", map[string]string{"AWS_STS_REGIONAL_ENDPOINTS": "regional", "AWS_MAX_ATTEMPTS": "5"}, "", []string{"pass"}},
+		{"invalid retry mode", map[string]string{"AWS_RETRY_MODE": "fast"}, "", []string{"pass", "warn"}},
+		{"invalid max attempts", map[string]string{"AWS_MAX_ATTEMPTS": "many"}, "", []string{"pass", "warn"}},
+		{"legacy STS", map[string]string{"AWS_STS_REGIONAL_ENDPOINTS": "legacy"}, "", []string{"pass", "warn"}},
+		{"IMDS disabled on EC2", map[string]string{"AWS_EC2_METADATA_DISABLED": "true"}, "ec2", []string{"pass", "warn"}},
+		{"IMDS disabled with keys", map[string]string{"AWS_EC2_METADATA_DISABLED": "true", "AWS_ACCESS_KEY_ID": "AKIDEXAMPLE"}, "ec2", []string{"pass"}},
+		{"IMDS disabled off EC2", map[string]string{"AWS_EC2_METADATA_DISABLED": "true"}, "", []string{"pass"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			results := runSDKEnvChecks(&Environment{Platform: tt.platform})
+			var got []string
+			for _, result := range results {
+				got = append(got, result.Status)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("statuses = %v, want %v (%v)", got, tt.want, results)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// How long in-flight requests get to finish once shutdown starts
+const serveShutdownTimeout = 5 * time.Second
+
+// cachedProbe runs the checks at most once per ttl. Concurrent requests wait
+// for the same run instead of starting their own.
+type cachedProbe struct {
+	probe func(ctx context.Context) []CheckResult
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	results []CheckResult
+	ranAt   time.Time
+}
+
+func (c *cachedProbe) get(ctx context.Context) []CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.results != nil && time.Since(c.ranAt) < c.ttl {
+		return c.results
+	}
+	results := c.probe(ctx)
+	// Don't cache a run that was cut short by a disconnecting client
+	if ctx.Err() != nil {
+		return results
+	}
+	c.results, c.ranAt = results, time.Now()
+	return results
+}
+
+// serveHealth exposes the checks over HTTP until ctx is canceled: /healthz
+// returns 200 when every check passes and 503 otherwise, and /metrics serves
+// the Prometheus output
+func serveHealth(ctx context.Context, addr string, ttl time.Duration, regions []string, probe func(ctx context.Context) []CheckResult) error {
+	cache := &cachedProbe{probe: probe, ttl: ttl}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		results := cache.get(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if exitCode(results) != exitPass {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(newProbeOutput(results, regions))
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		results := cache.get(r.Context())
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writePrometheus(w, sortResults(results))
+	})
+
+	server := &http.Server{
+		Addr:        addr,
+		Handler:     mux,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	logger.Info("serving health checks", "address", listener.Addr().String(), "cache", ttl)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
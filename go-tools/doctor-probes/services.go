@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Service families --services selects from, in the order they are probed
+var serviceFamilies = []string{"runtime", "control", "agent", "agent-runtime", "data-automation"}
+
+// Most BCCE deploys only call Bedrock Runtime and the control plane
+var defaultServices = []string{"runtime", "control"}
+
+// parseServices validates a comma-separated --services list
+func parseServices(value string) ([]string, error) {
+	var services []string
+	for _, family := range strings.Split(value, ",") {
+		family = strings.ToLower(strings.TrimSpace(family))
+		if family == "" {
+			continue
+		}
+		if !slices.Contains(serviceFamilies, family) {
+			return nil, fmt.Errorf("unknown service %q (expected %s)", family, strings.Join(serviceFamilies, ", "))
+		}
+		if !slices.Contains(services, family) {
+			services = append(services, family)
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("expected at least one of %s", strings.Join(serviceFamilies, ", "))
+	}
+	return services, nil
+}
+
+// services returns the selected service families, defaulting to defaultServices
+func (c *Config) services() []string {
+	if len(c.Services) > 0 {
+		return c.Services
+	}
+	return defaultServices
+}
+
+// selectedServices returns the endpoints the DNS and TCP checks probe: those
+// in the selected families, less the agent endpoints under --no-agent
+func (c *Config) selectedServices() []bedrockService {
+	families := c.services()
+	var services []bedrockService
+	for _, service := range bedrockServices {
+		if !slices.Contains(families, service.Family) || (service.Agent && c.NoAgent) {
+			continue
+		}
+		services = append(services, service)
+	}
+	return services
+}
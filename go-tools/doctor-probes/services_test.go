@@ -0,0 +1,46 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseServices(t *testing.T) {
+	got, err := parseServices(" Runtime,agent-runtime,runtime, data-automation")
+	if want := []string{"runtime", "agent-runtime", "data-automation"}; err != nil || !slices.Equal(got, want) {
+		t.Errorf("parseServices() = %v, %v, want %v", got, err, want)
+	}
+	for _, bad := range []string{"", " , ", "runtime,agents"} {
+		if _, err := parseServices(bad); err == nil {
+			t.Errorf("parseServices(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestSelectedServices(t *testing.T) {
+	prefixes := func(cfg *Config) string {
+		var names []string
+		for _, service := range cfg.selectedServices() {
+			names = append(names, service.Prefix)
+		}
+		return strings.Join(names, ",")
+	}
+
+	cfg := newConfig()
+	if got := prefixes(cfg); got != "bedrock-runtime,bedrock" {
+		t.Errorf("default services = %s, want runtime and control", got)
+	}
+	cfg.Services = []string{"data-automation", "agent"}
+	if got := prefixes(cfg); got != "bedrock-agent,bedrock-data-automation,bedrock-data-automation-runtime" {
+		t.Errorf("selected services = %s, want them in probe order", got)
+	}
+	cfg.NoAgent = true
+	if got := prefixes(cfg); got != "bedrock-data-automation,bedrock-data-automation-runtime" {
+		t.Errorf("services with --no-agent = %s, want the agent endpoint dropped", got)
+	}
+
+	if _, err := parseConfig([]byte("services: [runtime, bedrock]\n"), "doctor.yaml"); err == nil || !strings.Contains(err.Error(), `unknown service "bedrock"`) {
+		t.Errorf("parseConfig() = %v, want an unknown service error", err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// severityRule returns the status the config's severity map assigns to a
+// result that did not pass. Keys are a check category such as "tcp", or a
+// glob matched against the result name without its region prefix, such as
+// "dns - bedrock agents"; an exact category wins over a glob, and globs are
+// tried in sorted order so overlapping ones resolve the same way every run.
+func (c *Config) severityRule(result CheckResult) (string, bool) {
+	if status, ok := c.Severity[result.check]; ok {
+		return status, true
+	}
+	name := strings.ToLower(strings.TrimPrefix(result.Name, result.Region+" / "))
+	patterns := make([]string, 0, len(c.Severity))
+	for pattern := range c.Severity {
+		patterns = append(patterns, pattern)
+	}
+	slices.Sort(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return c.Severity[pattern], true
+		}
+	}
+	return "", false
+}
+
+// applySeverity replaces a non-passing result's status with the one the
+// config assigns it, noting the original so the report shows the override
+func (c *Config) applySeverity(result *CheckResult) {
+	if result.Status == "pass" {
+		return
+	}
+	status, ok := c.severityRule(*result)
+	if !ok || status == result.Status {
+		return
+	}
+	result.OverriddenFrom = result.Status
+	result.Status = status
+	result.Message = fmt.Sprintf("%s (reported as %s by the config's severity for this check; was %s)", result.Message, status, result.OverriddenFrom)
+}
+
+// validSeverity checks a severity map entry from the config file
+func validSeverity(pattern, status string) error {
+	if _, ok := statusRank[status]; !ok {
+		return fmt.Errorf("severity for %q must be pass, warn or fail, not %q", pattern, status)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("severity pattern %q: %v", pattern, err)
+	}
+	return nil
+}
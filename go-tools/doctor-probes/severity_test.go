@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplySeverity(t *testing.T) {
+	cfg := newConfig()
+	cfg.Severity = map[string]string{
+		"tcp":                  "warn",
+		"dns - bedrock agent*": "pass",
+		"clock - *":            "fail",
+	}
+	tests := []struct {
+		result       CheckResult
+		wantStatus   string
+		wantOverride string
+	}{
+		{CheckResult{Name: "us-east-1 / TCP - Bedrock Runtime", Region: "us-east-1", Status: "fail", check: "tcp"}, "warn", "fail"},
+		{CheckResult{Name: "DNS - Bedrock Agents", Region: "us-east-1", Status: "warn", check: "dns"}, "pass", "warn"},
+		{CheckResult{Name: "DNS - Bedrock Runtime", Region: "us-east-1", Status: "fail", check: "dns"}, "fail", ""},
+		{CheckResult{Name: "Clock - Skew", Status: "warn", check: "clock"}, "fail", "warn"},
+		{CheckResult{Name: "Clock - Skew", Status: "pass", check: "clock"}, "pass", ""},
+		{CheckResult{Name: "TCP - Bedrock Runtime", Status: "warn", check: "tcp"}, "warn", ""},
+	}
+	for _, tt := range tests {
+		result := tt.result
+		cfg.applySeverity(&result)
+		if result.Status != tt.wantStatus || result.OverriddenFrom != tt.wantOverride {
+			t.Errorf("%s %s = %s (overridden from %q); want %s (from %q)", tt.result.Name, tt.result.Status, result.Status, result.OverriddenFrom, tt.wantStatus, tt.wantOverride)
+		}
+		if tt.wantOverride != "" && !strings.Contains(result.Message, "was "+tt.wantOverride) {
+			t.Errorf("%s message %q does not mention the override", tt.result.Name, result.Message)
+		}
+	}
+}
+
+func TestValidSeverity(t *testing.T) {
+	if err := validSeverity("tcp", "warn"); err != nil {
+		t.Errorf("validSeverity(tcp, warn) = %v", err)
+	}
+	if err := validSeverity("tcp", "info"); err == nil {
+		t.Error("validSeverity accepted status info")
+	}
+	if err := validSeverity("dns [", "warn"); err == nil {
+		t.Error("validSeverity accepted a malformed glob")
+	}
+}
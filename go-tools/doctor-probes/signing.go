@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/smithy-go"
+)
+
+// The region label of an AWS hostname, including VPC endpoint names such as
+// vpce-0abc.bedrock-runtime.us-east-1.vpce.amazonaws.com
+var endpointRegionPattern = regexp.MustCompile(`\.([a-z]{2}(?:-[a-z]+)+-\d+)\.(?:vpce\.)?amazonaws\.com(?:\.cn)?$`)
+
+// AWS names the region a request was signed for when it is the wrong one
+var scopedRegionPattern = regexp.MustCompile(`scoped to a valid region, not '([a-z0-9-]+)'`)
+
+// endpointRegion returns the region a Bedrock hostname serves, if it names one
+func endpointRegion(host string) (string, bool) {
+	match := endpointRegionPattern.FindStringSubmatch(strings.ToLower(host))
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// signingMismatch explains a signature error caused by signing a request for a
+// different region than the endpoint it was sent to. The SDK signs for the
+// configured region even when --endpoint-url points somewhere else, which only
+// shows up as an opaque SignatureDoesNotMatch.
+func signingMismatch(err error, signingRegion, host string) (string, bool) {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return "", false
+	}
+	scoped := scopedRegionPattern.FindStringSubmatch(apiErr.ErrorMessage())
+	switch apiErr.ErrorCode() {
+	case "InvalidSignatureException", "SignatureDoesNotMatch", "IncompleteSignatureException":
+	default:
+		if scoped == nil {
+			return "", false
+		}
+	}
+	if scoped != nil {
+		signingRegion = scoped[1]
+	}
+	target, ok := endpointRegion(host)
+	if !ok || target == signingRegion {
+		// A signature error without a region mismatch is a bad secret key, not this
+		if scoped == nil {
+			return "", false
+		}
+		return fmt.Sprintf("The request was signed for %s but %s rejected that region", signingRegion, host), true
+	}
+	return fmt.Sprintf("The request was signed for %s but sent to the %s endpoint %s", signingRegion, target, host), true
+}
+
+// signingMismatchFix says how to bring the signing region and the endpoint back in line
+func signingMismatchFix(host string) string {
+	return fmt.Sprintf("Set AWS_REGION (or --regions) to the region %s serves, or point --endpoint-url at an endpoint in the signing region", host)
+}
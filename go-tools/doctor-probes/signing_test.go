@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestEndpointRegion(t *testing.T) {
+	tests := []struct {
+		host, want string
+	}{
+		{"bedrock-runtime.us-east-1.amazonaws.com", "us-east-1"},
+		{"bedrock-runtime-fips.us-gov-west-1.amazonaws.com", "us-gov-west-1"},
+		{"vpce-0abc123-xyz.bedrock-runtime.eu-central-1.vpce.amazonaws.com", "eu-central-1"},
+		{"bedrock-runtime.cn-north-1.amazonaws.com.cn", "cn-north-1"},
+		{"localhost", ""},
+		{"bedrock.internal.example.com", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := endpointRegion(tt.host); got != tt.want {
+			t.Errorf("endpointRegion(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestSigningMismatch(t *testing.T) {
+	signature := &smithy.GenericAPIError{Code: "InvalidSignatureException", Message: "The request signature we calculated does not match the signature you provided."}
+	scoped := &smithy.GenericAPIError{Code: "InvalidSignatureException", Message: "Credential should be scoped to a valid region, not 'us-west-2'."}
+	tests := []struct {
+		name          string
+		err           error
+		signingRegion string
+		host          string
+		want          string
+	}{
+		{"different endpoint region", signature, "us-west-2", "bedrock-runtime.us-east-1.amazonaws.com", "The request was signed for us-west-2 but sent to the us-east-1 endpoint bedrock-runtime.us-east-1.amazonaws.com"},
+		{"region named by the error", scoped, "us-east-1", "vpce-0abc.bedrock-runtime.eu-west-1.vpce.amazonaws.com", "The request was signed for us-west-2 but sent to the eu-west-1 endpoint vpce-0abc.bedrock-runtime.eu-west-1.vpce.amazonaws.com"},
+		{"custom endpoint", scoped, "us-east-1", "bedrock.internal.example.com", "The request was signed for us-west-2 but bedrock.internal.example.com rejected that region"},
+		// A signature error with matching regions means a wrong secret key
+		{"same region", signature, "us-east-1", "bedrock-runtime.us-east-1.amazonaws.com", ""},
+		{"other error", &smithy.GenericAPIError{Code: "AccessDeniedException"}, "us-west-2", "bedrock-runtime.us-east-1.amazonaws.com", ""},
+		{"not an API error", errors.New("connection reset"), "us-west-2", "bedrock-runtime.us-east-1.amazonaws.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := signingMismatch(tt.err, tt.signingRegion, tt.host)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("signingMismatch() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go"
+)
+
+const defaultSmokeModel = "amazon.titan-text-lite-v1"
+
+// smokeModel returns the model for the smoke test: config/flag, then BCCE_SMOKE_MODEL, then the default
+func (c *Config) smokeModel() string {
+	if c.SmokeModel != "" {
+		return c.SmokeModel
+	}
+	if model := os.Getenv("BCCE_SMOKE_MODEL"); model != "" {
+		return model
+	}
+	return defaultSmokeModel
+}
+
+// smokeRequestBody builds a minimal one-token request in the model family's native format
+func smokeRequestBody(modelID string) ([]byte, error) {
+	var body any
+	switch {
+	case strings.Contains(modelID, "anthropic."):
+		body = map[string]any{
+			"anthropic_version": "bedrock-2023-05-31",
+			"max_tokens":        1,
+			"messages":          []map[string]string{{"role": "user", "content": "ping"}},
+		}
+	case strings.Contains(modelID, "amazon.titan"):
+		body = map[string]any{
+			"inputText":            "ping",
+			"textGenerationConfig": map[string]int{"maxTokenCount": 1},
+		}
+	case strings.Contains(modelID, "meta."):
+		body = map[string]any{"prompt": "ping", "max_gen_len": 1}
+	default:
+		body = map[string]any{"prompt": "ping", "max_tokens": 1}
+	}
+	return json.Marshal(body)
+}
+
+func modelAccessURL(region string) string {
+	return fmt.Sprintf("https://%s.console.aws.amazon.com/bedrock/home?region=%s#/modelaccess", region, region)
+}
+
+func runSmokeTest(ctx context.Context, cfg *Config, region string) []CheckResult {
+	var results []CheckResult
+
+	modelID := cfg.smokeModel()
+	name := "Smoke Test - InvokeModel"
+
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load AWS config: %v", err),
+			Fix:     "Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+		})
+		return results
+	}
+
+	body, err := smokeRequestBody(modelID)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to build request body for %s: %v", modelID, err),
+		})
+		return results
+	}
+
+	logger.Debug("invoking model", "model", modelID, "region", region)
+	_, err = newRuntimeClient(cfg, awsCfg).InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(modelID),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        body,
+	})
+	if err != nil {
+		results = append(results, smokeErrorResult(cfg, awsCfg, region, name, "InvokeModel", modelID, err))
+		return results
+	}
+
+	results = append(results, CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("Invoked %s successfully", modelID),
+	})
+
+	return results
+}
+
+// smokeErrorResult explains why operation failed for modelID during a smoke test
+func smokeErrorResult(cfg *Config, awsCfg aws.Config, region, name, operation, modelID string, err error) CheckResult {
+	status := "fail"
+	fix := awsErrorFix(err, "Check credentials, network access to Bedrock, and that the model ID is correct")
+	reason := reasonNone
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ThrottlingException", "ServiceQuotaExceededException":
+			status = "warn"
+			fix = "Requests are being throttled; retry later or request a quota increase"
+			reason = reasonQuota
+		case "AccessDeniedException":
+			fix = fmt.Sprintf("Request access to %s at %s and allow bedrock:%s for this principal", modelID, modelAccessURL(region), operation)
+			reason = reasonModelAccess
+		case "ValidationException", "ResourceNotFoundException":
+			fix = fmt.Sprintf("Check that %s is a valid model ID available in %s (set --smoke-model or BCCE_SMOKE_MODEL)", modelID, region)
+		}
+	}
+	message := fmt.Sprintf("%s %s failed: %v", operation, modelID, err)
+	host := cfg.endpointHost("bedrock-runtime", region)
+	if mismatch, ok := signingMismatch(err, awsCfg.Region, host); ok {
+		message = fmt.Sprintf("%s %s failed: %s", operation, modelID, mismatch)
+		fix = signingMismatchFix(host)
+		reason = reasonRegion
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  status,
+		Message: message,
+		Fix:     fix,
+		reason:  reason,
+	}
+}
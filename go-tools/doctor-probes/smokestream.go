@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+// A first chunk slower than this for a one-token request usually means
+// something between here and Bedrock is buffering the stream
+const defaultSmokeStreamWarn = 5 * time.Second
+
+// errNoFirstChunk is returned when the stream opened but no chunk arrived in time
+var errNoFirstChunk = errors.New("no chunk arrived before the probe timeout")
+
+// runSmokeStreamTest invokes the smoke-test model through
+// InvokeModelWithResponseStream, the path BCCE uses, and times the first
+// chunk. A stream that opens but never delivers is the signature of a proxy
+// that buffers responses or can't pass HTTP/2 event streams.
+func runSmokeStreamTest(ctx context.Context, cfg *Config, region string) []CheckResult {
+	modelID := cfg.smokeModel()
+	name := "Smoke Test - InvokeModelWithResponseStream"
+
+	awsCfg, err := cfg.loadAWSConfig(ctx, region)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load AWS config: %v", err),
+			Fix:     "Check ~/.aws/config and ~/.aws/credentials for syntax errors",
+		}}
+	}
+	body, err := smokeRequestBody(modelID)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to build request body for %s: %v", modelID, err),
+		}}
+	}
+
+	logger.Debug("invoking model with a response stream", "model", modelID, "region", region)
+	start := time.Now()
+	output, err := newRuntimeClient(cfg, awsCfg).InvokeModelWithResponseStream(ctx, &bedrockruntime.InvokeModelWithResponseStreamInput{
+		ModelId:     aws.String(modelID),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        body,
+	})
+	if err != nil {
+		return []CheckResult{smokeErrorResult(cfg, awsCfg, region, name, "InvokeModelWithResponseStream", modelID, err)}
+	}
+	stream := output.GetStream()
+	defer stream.Close()
+	opened := time.Since(start)
+
+	err = awaitFirstChunk(ctx, stream.Events(), stream.Err)
+	firstChunk := time.Since(start)
+	logger.Debug("response stream finished", "model", modelID, "opened", opened, "first_chunk", firstChunk, "error", err)
+	if err != nil && !errors.Is(err, errNoFirstChunk) {
+		return []CheckResult{smokeErrorResult(cfg, awsCfg, region, name, "InvokeModelWithResponseStream", modelID, err)}
+	}
+	return []CheckResult{smokeStreamResult(name, modelID, opened, firstChunk, err, cfg.SmokeStreamWarn)}
+}
+
+// awaitFirstChunk waits for the first chunk on events. streamErr reports why
+// the stream ended when it closes without one.
+func awaitFirstChunk(ctx context.Context, events <-chan types.ResponseStream, streamErr func() error) error {
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				if err := streamErr(); err != nil {
+					return err
+				}
+				return errors.New("the stream ended without a chunk")
+			}
+			if _, ok := event.(*types.ResponseStreamMemberChunk); ok {
+				return nil
+			}
+		case <-ctx.Done():
+			return errNoFirstChunk
+		}
+	}
+}
+
+// smokeStreamResult reports the time to first chunk, warning above warnAfter
+func smokeStreamResult(name, modelID string, opened, firstChunk time.Duration, err error, warnAfter time.Duration) CheckResult {
+	opened, firstChunk = opened.Round(time.Millisecond), firstChunk.Round(time.Millisecond)
+	if err != nil {
+		return CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("The response stream for %s opened after %s, but %v", modelID, opened, err),
+			Fix:       "A proxy or firewall is likely buffering the response: allow HTTP/2 and streamed responses to bedrock-runtime, or exempt it from TLS inspection",
+			ErrorKind: ErrorKindTimeout,
+			LatencyMs: firstChunk.Milliseconds(),
+		}
+	}
+	message := fmt.Sprintf("Streamed %s: the stream opened after %s and the first chunk arrived after %s", modelID, opened, firstChunk)
+	if warnAfter > 0 && firstChunk > warnAfter {
+		return CheckResult{
+			Name:      name,
+			Status:    "warn",
+			Message:   fmt.Sprintf("%s, above %s", message, warnAfter),
+			Fix:       "Check with --http2 whether a TLS-inspecting proxy is holding back streamed responses, or raise --smoke-stream-warn if the model is just slow to start",
+			LatencyMs: firstChunk.Milliseconds(),
+		}
+	}
+	return CheckResult{
+		Name:      name,
+		Status:    "pass",
+		Message:   message,
+		LatencyMs: firstChunk.Milliseconds(),
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+func TestAwaitFirstChunk(t *testing.T) {
+	noErr := func() error { return nil }
+
+	events := make(chan types.ResponseStream, 2)
+	events <- &types.UnknownUnionMember{Tag: "metadata"}
+	events <- &types.ResponseStreamMemberChunk{}
+	if err := awaitFirstChunk(context.Background(), events, noErr); err != nil {
+		t.Errorf("awaitFirstChunk() = %v, want the chunk after the unknown event", err)
+	}
+
+	closed := make(chan types.ResponseStream)
+	close(closed)
+	streamErr := errors.New("ModelStreamErrorException: model crashed")
+	if err := awaitFirstChunk(context.Background(), closed, func() error { return streamErr }); !errors.Is(err, streamErr) {
+		t.Errorf("awaitFirstChunk(closed) = %v, want the stream's error", err)
+	}
+	if err := awaitFirstChunk(context.Background(), closed, noErr); err == nil || errors.Is(err, errNoFirstChunk) {
+		t.Errorf("awaitFirstChunk(closed, no error) = %v, want an early end", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := awaitFirstChunk(ctx, make(chan types.ResponseStream), noErr); !errors.Is(err, errNoFirstChunk) {
+		t.Errorf("awaitFirstChunk(silent) = %v, want errNoFirstChunk", err)
+	}
+}
+
+func TestSmokeStreamResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		firstChunk time.Duration
+		err        error
+		status     string
+		message    string
+	}{
+		{"fast", 800 * time.Millisecond, nil, "pass", "first chunk arrived after 800ms"},
+		{"slow", 7 * time.Second, nil, "warn", "above 5s"},
+		{"buffered", 10 * time.Second, errNoFirstChunk, "fail", "but no chunk arrived"},
+	}
+	for _, tt := range tests {
+		got := smokeStreamResult("Smoke Test - InvokeModelWithResponseStream", defaultSmokeModel, 300*time.Millisecond, tt.firstChunk, tt.err, defaultSmokeStreamWarn)
+		if got.Status != tt.status || !strings.Contains(got.Message, tt.message) {
+			t.Errorf("%s: got %s %q, want %s containing %q", tt.name, got.Status, got.Message, tt.status, tt.message)
+		}
+		if got.LatencyMs != tt.firstChunk.Milliseconds() {
+			t.Errorf("%s: latency = %dms, want the time to first chunk", tt.name, got.LatencyMs)
+		}
+	}
+	if got := smokeStreamResult("Smoke Test", defaultSmokeModel, time.Second, time.Minute, nil, 0); got.Status != "pass" {
+		t.Errorf("a zero threshold warned: %+v", got)
+	}
+}
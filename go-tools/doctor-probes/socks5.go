@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// parseSOCKS5 accepts host:port or socks5://[user:password@]host:port
+func parseSOCKS5(value string) (address string, user *url.Userinfo, err error) {
+	address = value
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		if u.Scheme != "socks5" {
+			return "", nil, fmt.Errorf("scheme must be socks5, not %s", u.Scheme)
+		}
+		address, user = u.Host, u.User
+		if user != nil {
+			// RFC 1929 caps both at 255 bytes and needs a username
+			password, _ := user.Password()
+			if user.Username() == "" || len(user.Username()) > 255 || len(password) > 255 {
+				return "", nil, fmt.Errorf("expected a username and password of 1 to 255 bytes")
+			}
+		}
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", nil, fmt.Errorf("expected host:port: %v", err)
+	}
+	if n, err := strconv.Atoi(port); host == "" || err != nil || n < 1 || n > 65535 {
+		return "", nil, fmt.Errorf("expected host:port with a port from 1 to 65535")
+	}
+	return address, user, nil
+}
+
+// socks5Dialer connects through a SOCKS5 proxy with golang.org/x/net/proxy,
+// which always hands the proxy the hostname it is given. Without remoteDNS
+// hostnames are resolved here first, so the proxy is only given addresses.
+type socks5Dialer struct {
+	proxy     string
+	remoteDNS bool
+	dialer    proxy.ContextDialer
+	resolver  *net.Resolver
+}
+
+// socks5Dialer returns the --socks5 dialer, or nil when no proxy is set
+func (c *Config) socks5Dialer() *socks5Dialer {
+	if c.SOCKS5 == "" {
+		return nil
+	}
+	// Validated when the flag or config file was read
+	address, user, _ := parseSOCKS5(c.SOCKS5)
+	var auth *proxy.Auth
+	if user != nil {
+		password, _ := user.Password()
+		auth = &proxy.Auth{User: user.Username(), Password: password}
+	}
+	// proxy.SOCKS5 never fails, and its dialer honors the context
+	dialer, _ := proxy.SOCKS5("tcp", address, auth, c.dialer())
+	return &socks5Dialer{proxy: address, remoteDNS: c.SOCKS5RemoteDNS, dialer: dialer.(proxy.ContextDialer), resolver: c.resolver()}
+}
+
+// dialFunc returns the DialContext the TCP, TLS and HTTPS probes connect
+// with: through --socks5 when it is set, and directly otherwise
+func (c *Config) dialFunc() func(ctx context.Context, network, address string) (net.Conn, error) {
+	if d := c.socks5Dialer(); d != nil {
+		return d.DialContext
+	}
+	return c.dialer().DialContext
+}
+
+// viaProxy is appended to probe messages so a result reached through
+// --socks5 isn't read as direct connectivity
+func (c *Config) viaProxy() string {
+	d := c.socks5Dialer()
+	if d == nil {
+		return ""
+	}
+	if d.remoteDNS {
+		return fmt.Sprintf(" via SOCKS5 proxy %s (proxy-side DNS)", d.proxy)
+	}
+	return " via SOCKS5 proxy " + d.proxy
+}
+
+// probeTCPViaSOCKS5 is probeTCPAddress behind --socks5: one CONNECT through
+// the proxy. The proxy picks the address when it resolves the name, so the
+// per-address view of a direct probe doesn't apply.
+func probeTCPViaSOCKS5(ctx context.Context, cfg *Config, name, address string) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := cfg.dialFunc()(ctx, "tcp", address)
+	durationMs := time.Since(start).Milliseconds()
+	if err != nil {
+		return CheckResult{
+			Name:       name,
+			Status:     "fail",
+			Message:    fmt.Sprintf("Failed to connect to %s%s: %v", address, cfg.viaProxy(), err),
+			Fix:        socks5Fix(err, cfg.socks5Dialer().proxy, address),
+			ErrorKind:  newProbeError(ErrorKindTCP, err).Kind,
+			DurationMs: durationMs,
+		}
+	}
+	conn.Close()
+	return CheckResult{
+		Name:       name,
+		Status:     "pass",
+		Message:    fmt.Sprintf("Connected to %s%s", address, cfg.viaProxy()),
+		DurationMs: durationMs,
+	}
+}
+
+func (d *socks5Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if !d.remoteDNS {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) == nil {
+			ips, err := d.resolver.LookupIP(ctx, "ip", host)
+			if err != nil {
+				return nil, err
+			}
+			address = net.JoinHostPort(ips[0].String(), port)
+		}
+	}
+	conn, err := d.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("connected through SOCKS5 proxy", "proxy", d.proxy, "address", address)
+	return conn, nil
+}
+
+// socks5Fix says what to check after a failed connection through proxyAddr.
+// x/net/proxy reports a refused CONNECT or rejected credentials only in the
+// text of an error wrapped in a "socks connect" net.OpError, so it is matched
+// on that.
+func socks5Fix(err error, proxyAddr, address string) string {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && strings.HasPrefix(opErr.Op, "socks") && opErr.Err != nil {
+		switch message := opErr.Err.Error(); {
+		case strings.HasPrefix(message, "unknown error "):
+			return fmt.Sprintf("The proxy is reachable but could not connect onward; check its egress rules for %s and, with --socks5-remote-dns, its DNS", address)
+		case strings.Contains(message, "authentication"):
+			return fmt.Sprintf("The SOCKS5 proxy %s rejected the credentials; pass socks5://user:password@%s with ones it accepts", proxyAddr, proxyAddr)
+		}
+	}
+	return fmt.Sprintf("Check that the SOCKS5 proxy %s is running and reachable from here", proxyAddr)
+}
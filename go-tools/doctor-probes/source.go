@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net"
+)
+
+// interfaceSourceIP picks the address to bind for --interface, preferring a
+// routable IPv4 address since that is what most VPN tunnels carry
+func interfaceSourceIP(name string) (string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	var fallback net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+		if fallback == nil {
+			fallback = ipNet.IP
+		}
+	}
+	if fallback == nil {
+		return "", fmt.Errorf("interface %s has no usable address", name)
+	}
+	return fallback.String(), nil
+}
+
+// sameFamily keeps the addresses a socket bound to source can reach
+func sameFamily(ips []net.IP, source net.IP) []net.IP {
+	var kept []net.IP
+	for _, ip := range ips {
+		if (ip.To4() != nil) == (source.To4() != nil) {
+			kept = append(kept, ip)
+		}
+	}
+	return kept
+}
+
+// localIP returns the IP of a connection's local address for reporting
+func localIP(addr net.Addr) string {
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.IP.String()
+	}
+	return addr.String()
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestSameFamily(t *testing.T) {
+	ips := []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.2")}
+	if got := sameFamily(ips, net.ParseIP("10.0.0.5")); len(got) != 2 {
+		t.Errorf("IPv4 source kept %v, want the two IPv4 addresses", got)
+	}
+	if got := sameFamily(ips, net.ParseIP("fd00::5")); len(got) != 1 || !got[0].Equal(ips[1]) {
+		t.Errorf("IPv6 source kept %v, want %v", got, ips[1])
+	}
+}
+
+func TestProbeTCPAddressReportsSource(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	cfg := newConfig()
+	cfg.SourceIP = "127.0.0.1"
+	result := probeTCPAddress(context.Background(), cfg, "TCP - test", listener.Addr().String())
+	if result.Status != "pass" || !strings.Contains(result.Message, "from 127.0.0.1") {
+		t.Errorf("result = %s %q, want a pass naming the source address", result.Status, result.Message)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+)
+
+// Tokens closer than this to expiry will lapse during a typical session
+const ssoExpiryWarn = 15 * time.Minute
+
+// ssoTokenKey returns the key the SSO token cache file is named after: the
+// sso-session name, or the start URL for legacy profiles. It follows
+// source_profile so a role assumed from an SSO profile is covered too.
+func ssoTokenKey(shared *config.SharedConfig) (profile, key string) {
+	for ; shared != nil; shared = shared.Source {
+		switch {
+		case shared.SSOSession != nil && shared.SSOSession.Name != "":
+			return shared.Profile, shared.SSOSession.Name
+		case shared.SSOSessionName != "":
+			return shared.Profile, shared.SSOSessionName
+		case shared.SSOStartURL != "":
+			return shared.Profile, shared.SSOStartURL
+		}
+	}
+	return "", ""
+}
+
+// checkSSOToken reads the cached token at path and reports whether it is still valid at now
+func checkSSOToken(name, profile, path string, now time.Time) CheckResult {
+	fix := fmt.Sprintf("aws sso login --profile %s", profile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Profile %s uses IAM Identity Center but has no cached SSO token (%s)", profile, path),
+			Fix:       fix,
+			ErrorKind: ErrorKindAuth,
+		}
+	}
+	if err != nil {
+		return CheckResult{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to read the SSO token cache %s: %v", path, err),
+			Fix:     fmt.Sprintf("Check the permissions on %s, then %s", path, fix),
+		}
+	}
+
+	var token struct {
+		ExpiresAt string `json:"expiresAt"`
+	}
+	if err := json.Unmarshal(data, &token); err != nil || token.ExpiresAt == "" {
+		return CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("SSO token cache %s has no readable expiresAt", path),
+			Fix:       fix,
+			ErrorKind: ErrorKindAuth,
+		}
+	}
+	expiresAt, err := time.Parse(time.RFC3339, token.ExpiresAt)
+	if err != nil {
+		return CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("SSO token cache %s has an invalid expiresAt %q", path, token.ExpiresAt),
+			Fix:       fix,
+			ErrorKind: ErrorKindAuth,
+		}
+	}
+
+	remaining := expiresAt.Sub(now)
+	switch {
+	case remaining <= 0:
+		return CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("SSO session for profile %s expired %s ago", profile, (-remaining).Round(time.Minute)),
+			Fix:       fix,
+			ErrorKind: ErrorKindAuth,
+		}
+	case remaining < ssoExpiryWarn:
+		return CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("SSO session for profile %s expires in %s", profile, remaining.Round(time.Second)),
+			Fix:     fix,
+		}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: fmt.Sprintf("SSO session for profile %s is valid for another %s", profile, remaining.Round(time.Minute)),
+	}
+}
+
+// runSSOChecks finds whether the active profile signs in through IAM Identity
+// Center and, if so, checks that its cached SSO token has not expired
+func runSSOChecks(ctx context.Context, cfg *Config) []CheckResult {
+	name := "Credentials - SSO Session"
+	configFile, credentialsFile := sharedConfigFiles()
+	profile, source := activeProfile(cfg.Profile)
+
+	shared, err := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		o.ConfigFiles = []string{configFile}
+		o.CredentialsFiles = []string{credentialsFile}
+	})
+	logger.Debug("loaded shared config profile for SSO", "profile", profile, "source", source, "error", err)
+	if err != nil {
+		var notExist config.SharedConfigProfileNotExistError
+		if errors.As(err, &notExist) {
+			return []CheckResult{{Name: name, Status: "pass", Message: fmt.Sprintf("Profile %s is not defined, so no SSO session is used", profile)}}
+		}
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Failed to load profile %q: %v", profile, err),
+			Fix:     fmt.Sprintf("Check %s for syntax errors", configFile),
+		}}
+	}
+
+	ssoProfile, key := ssoTokenKey(&shared)
+	if key == "" {
+		return []CheckResult{{Name: name, Status: "pass", Message: fmt.Sprintf("Profile %s does not use IAM Identity Center", profile)}}
+	}
+	path, err := ssocreds.StandardCachedTokenFilepath(key)
+	if err != nil {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "fail",
+			Message: fmt.Sprintf("Could not locate the SSO token cache: %v", err),
+			Fix:     "Set HOME so the AWS SDK can find ~/.aws/sso/cache",
+		}}
+	}
+	logger.Debug("reading SSO token cache", "profile", ssoProfile, "path", path)
+	return []CheckResult{checkSSOToken(name, ssoProfile, path, time.Now())}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+)
+
+func TestCheckSSOToken(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name, path, status, message string
+	}{
+		{"missing", filepath.Join(dir, "missing.json"), "fail", "no cached SSO token"},
+		{"expired", write("expired.json", `{"accessToken":"x","expiresAt":"2026-01-02T11:00:00Z"}`), "fail", "expired 1h0m0s ago"},
+		{"expiring", write("expiring.json", `{"accessToken":"x","expiresAt":"2026-01-02T12:05:00Z"}`), "warn", "expires in 5m0s"},
+		{"valid", write("valid.json", `{"accessToken":"x","expiresAt":"2026-01-02T20:00:00Z"}`), "pass", "valid for another 8h0m0s"},
+		{"no expiry", write("noexpiry.json", `{"accessToken":"x"}`), "fail", "no readable expiresAt"},
+		{"bad expiry", write("bad.json", `{"expiresAt":"tomorrow"}`), "fail", "invalid expiresAt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkSSOToken("SSO", "dev", tt.path, now)
+			if got.Status != tt.status || !strings.Contains(got.Message, tt.message) {
+				t.Errorf("checkSSOToken() = %s %q, want %s containing %q", got.Status, got.Message, tt.status, tt.message)
+			}
+			if got.Status != "pass" && !strings.Contains(got.Fix, "aws sso login --profile dev") {
+				t.Errorf("Fix = %q, want aws sso login --profile dev", got.Fix)
+			}
+		})
+	}
+}
+
+func TestRunSSOChecks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	configFile := filepath.Join(home, "config")
+	config := "[profile dev]\nsso_session = corp\nsso_account_id = 111122223333\nsso_role_name = Dev\n\n" +
+		"[sso-session corp]\nsso_region = us-east-1\nsso_start_url = https://corp.awsapps.com/start\n\n" +
+		"[profile legacy]\nsso_start_url = https://old.awsapps.com/start\nsso_region = us-east-1\nsso_account_id = 111122223333\nsso_role_name = Dev\n\n" +
+		"[profile admin]\nrole_arn = arn:aws:iam::111122223333:role/Admin\nsource_profile = dev\n\n" +
+		"[profile keys]\nregion = us-east-1\n"
+	if err := os.WriteFile(configFile, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, "credentials"))
+
+	path, err := ssocreds.StandardCachedTokenFilepath("corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	if err := os.WriteFile(path, []byte(`{"accessToken":"x","expiresAt":"`+expiresAt+`"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"dev":    "pass",
+		"admin":  "pass",
+		"legacy": "fail",
+		"keys":   "pass",
+	}
+	for profile, want := range tests {
+		results := runSSOChecks(context.Background(), &Config{Profile: profile})
+		if len(results) != 1 || results[0].Status != want {
+			t.Errorf("profile %s: runSSOChecks() = %+v, want one %s result", profile, results, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+	"time"
+)
+
+// Issuer names of common TLS-inspecting proxies, matched case-insensitively
+// against the issuer CN and O of every presented certificate. Override with
+// tls_interception_patterns in the config file.
+var defaultInterceptionPatterns = []string{
+	"Zscaler",
+	"Netskope",
+	"Palo Alto",
+	"Fortinet",
+	"Forcepoint",
+	"Blue Coat",
+	"Cisco Umbrella",
+	"Sophos",
+}
+
+func (c *Config) interceptionPatterns() []string {
+	if len(c.TLSInterceptionPatterns) > 0 {
+		return c.TLSInterceptionPatterns
+	}
+	return defaultInterceptionPatterns
+}
+
+// presentedChain repeats a handshake without verification so the certificates
+// of a failed handshake can be inspected; nothing is sent over the connection
+func presentedChain(ctx context.Context, address, serverName string, timeout time.Duration) []*x509.Certificate {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true}}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		logger.Debug("could not fetch presented chain", "address", address, "error", err)
+		return nil
+	}
+	defer conn.Close()
+	return conn.(*tls.Conn).ConnectionState().PeerCertificates
+}
+
+// interceptionVendor returns the matching pattern and the issuer it matched
+func interceptionVendor(chain []*x509.Certificate, patterns []string) (vendor, issuer string, ok bool) {
+	for _, cert := range chain {
+		names := append([]string{cert.Issuer.CommonName}, cert.Issuer.Organization...)
+		for _, name := range names {
+			for _, pattern := range patterns {
+				if pattern != "" && strings.Contains(strings.ToLower(name), strings.ToLower(pattern)) {
+					return pattern, cert.Issuer.String(), true
+				}
+			}
+		}
+	}
+	return "", "", false
+}
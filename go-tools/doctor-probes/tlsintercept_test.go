@@ -0,0 +1,39 @@
+package main
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+)
+
+func TestInterceptionVendor(t *testing.T) {
+	chain := func(cn string, org ...string) []*x509.Certificate {
+		return []*x509.Certificate{
+			{Issuer: pkix.Name{CommonName: "Amazon RSA 2048 M02", Organization: []string{"Amazon"}}},
+			{Issuer: pkix.Name{CommonName: cn, Organization: org}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		chain    []*x509.Certificate
+		patterns []string
+		want     string
+	}{
+		{"common name", chain("Zscaler Root CA"), defaultInterceptionPatterns, "Zscaler"},
+		{"organization", chain("Intermediate CA", "Palo Alto Networks"), defaultInterceptionPatterns, "Palo Alto"},
+		{"case-insensitive", chain("NETSKOPE CA"), defaultInterceptionPatterns, "Netskope"},
+		{"not an interceptor", chain("Starfield Services Root Certificate Authority - G2"), defaultInterceptionPatterns, ""},
+		{"custom patterns", chain("Acme Corp Inspection CA"), []string{"acme corp"}, "acme corp"},
+		{"custom patterns replace defaults", chain("Zscaler Root CA"), []string{"acme corp"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vendor, _, ok := interceptionVendor(tt.chain, tt.patterns)
+			if ok != (tt.want != "") || vendor != tt.want {
+				t.Errorf("interceptionVendor() = %q, %v, want %q", vendor, ok, tt.want)
+			}
+		})
+	}
+}
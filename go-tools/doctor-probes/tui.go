@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ANSI sequences for the live view: the alternate screen keeps the user's
+// scrollback intact, and redrawing from home avoids flicker
+const (
+	enterAltScreen = "\033[?1049h\033[?25l"
+	leaveAltScreen = "\033[?25h\033[?1049l"
+	cursorHome     = "\033[H"
+	clearLine      = "\033[K"
+	clearBelow     = "\033[J"
+)
+
+var (
+	spinnerFrames      = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	plainSpinnerFrames = []string{"|", "/", "-", "\\"}
+)
+
+// tuiRow is one check category in one region, or a result no job produced
+// such as the region source
+type tuiRow struct {
+	check, region string
+	label         string
+	done          bool
+	expanded      bool
+	results       []CheckResult
+}
+
+// status is the worst status among the row's results
+func (r tuiRow) status() string {
+	return statusName(exitCode(r.results))
+}
+
+func statusName(code int) string {
+	switch code {
+	case exitFail:
+		return "fail"
+	case exitWarn:
+		return "warn"
+	}
+	return "pass"
+}
+
+// tui is the state of the live view between redraws
+type tui struct {
+	rows    []*tuiRow
+	cursor  int
+	running bool
+	frame   int
+	plain   bool
+	started time.Time
+	elapsed time.Duration
+}
+
+// newTUIRun lays out a row per job before any result arrives
+func newTUIRun(cfg *Config, prefixRegion bool) []*tuiRow {
+	var rows []*tuiRow
+	for _, job := range buildJobs(cfg) {
+		label := checkLabels[job.check]
+		if prefixRegion && !slices.Contains(globalChecks, job.check) {
+			label = fmt.Sprintf("%s / %s", job.region, label)
+		}
+		rows = append(rows, &tuiRow{check: job.check, region: job.region, label: label})
+	}
+	return rows
+}
+
+// add files a streamed result under the row of the job that produced it
+func (t *tui) add(result CheckResult) {
+	for _, row := range t.rows {
+		if row.check != "" && row.check == result.check && row.region == result.Region {
+			row.results = append(row.results, result)
+			return
+		}
+	}
+	t.rows = append(t.rows, &tuiRow{label: result.Name, done: true, results: []CheckResult{result}})
+}
+
+// finish marks every row complete once the run returns, including jobs that
+// produced no result
+func (t *tui) finish(elapsed time.Duration) {
+	t.running = false
+	t.elapsed = elapsed
+	for _, row := range t.rows {
+		row.done = true
+	}
+}
+
+// draw renders the current frame
+func (t *tui) draw(w io.Writer) {
+	var b strings.Builder
+	b.WriteString(cursorHome)
+	title := "🩺 BCCE Doctor Probes"
+	if t.plain {
+		title = "BCCE Doctor Probes"
+	}
+	if t.running {
+		fmt.Fprintf(&b, "%s - running (%s)%s\n\n", title, time.Since(t.started).Round(100*time.Millisecond), clearLine)
+	} else {
+		fmt.Fprintf(&b, "%s - finished in %s%s\n\n", title, t.elapsed.Round(100*time.Millisecond), clearLine)
+	}
+
+	frames := spinnerFrames
+	if t.plain {
+		frames = plainSpinnerFrames
+	}
+	for i, row := range t.rows {
+		pointer := "  "
+		if i == t.cursor {
+			pointer = "> "
+		}
+		switch {
+		case !row.done && len(row.results) == 0:
+			fmt.Fprintf(&b, "%s%s %s%s\n", pointer, frames[t.frame%len(frames)], row.label, clearLine)
+		case len(row.results) == 0:
+			fmt.Fprintf(&b, "%s%s %s: no results%s\n", pointer, statusIcon("pass", t.plain), row.label, clearLine)
+		case len(row.results) == 1:
+			fmt.Fprintf(&b, "%s%s %s: %s%s\n", pointer, statusIcon(row.status(), t.plain), row.results[0].Name, row.results[0].Message, clearLine)
+		default:
+			fmt.Fprintf(&b, "%s%s %s (%d results)%s\n", pointer, statusIcon(row.status(), t.plain), row.label, len(row.results), clearLine)
+		}
+		if row.expanded || (len(row.results) > 1 && row.status() != "pass") {
+			for _, result := range row.results {
+				if len(row.results) > 1 {
+					fmt.Fprintf(&b, "     %s %s: %s%s\n", statusIcon(result.Status, t.plain), result.Name, result.Message, clearLine)
+				}
+				if row.expanded && result.Fix != "" {
+					fmt.Fprintf(&b, "       Fix: %s%s\n", result.Fix, clearLine)
+				}
+				if row.expanded && result.FixURL != "" {
+					fmt.Fprintf(&b, "       Docs: %s%s\n", result.FixURL, clearLine)
+				}
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	if t.running {
+		fmt.Fprintf(&b, "up/down select · enter show fix · q quit%s\n", clearLine)
+	} else {
+		fmt.Fprintf(&b, "up/down select · enter show fix · r re-run · q quit%s\n", clearLine)
+	}
+	b.WriteString(clearBelow)
+	io.WriteString(w, b.String())
+}
+
+// tuiKey is a keypress the view reacts to
+type tuiKey int
+
+const (
+	keyNone tuiKey = iota
+	keyUp
+	keyDown
+	keyToggle
+	keyRerun
+	keyQuit
+)
+
+// readKeys decodes keypresses from a terminal in raw mode, including the
+// escape sequences arrow keys send
+func readKeys(r io.Reader, keys chan<- tuiKey) {
+	in := bufio.NewReader(r)
+	for {
+		b, err := in.ReadByte()
+		if err != nil {
+			close(keys)
+			return
+		}
+		key := keyNone
+		switch b {
+		case 'k':
+			key = keyUp
+		case 'j':
+			key = keyDown
+		case '\r', '\n', ' ':
+			key = keyToggle
+		case 'r':
+			key = keyRerun
+		case 'q', 3:
+			key = keyQuit
+		case 0x1b:
+			// ESC [ A is up and ESC [ B is down
+			if next, _ := in.ReadByte(); next == '[' {
+				switch arrow, _ := in.ReadByte(); arrow {
+				case 'A':
+					key = keyUp
+				case 'B':
+					key = keyDown
+				}
+			}
+		}
+		if key != keyNone {
+			keys <- key
+		}
+	}
+}
+
+// runTUI shows the checks live until the user quits, re-running on request,
+// and returns the last completed run. setEmit routes the probe's streamed
+// results to the view; the terminal is restored before it returns.
+func runTUI(ctx context.Context, cfg *Config, prefixRegion, plain bool, setEmit func(func(CheckResult)), probe func(context.Context) []CheckResult) ([]CheckResult, error) {
+	restore, err := enableRawMode()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	fmt.Print(enterAltScreen)
+	defer fmt.Print(leaveAltScreen)
+
+	keys := make(chan tuiKey)
+	go readKeys(os.Stdin, keys)
+
+	view := &tui{plain: plain}
+	var last []CheckResult
+	for {
+		view.rows = newTUIRun(cfg, prefixRegion)
+		view.cursor = min(view.cursor, max(len(view.rows)-1, 0))
+		view.running = true
+		view.started = time.Now()
+
+		runCtx, cancelRun := context.WithCancel(ctx)
+		streamed := make(chan CheckResult)
+		done := make(chan []CheckResult, 1)
+		setEmit(func(result CheckResult) { streamed <- result })
+		go func() { done <- probe(runCtx) }()
+
+		ticker := time.NewTicker(100 * time.Millisecond)
+		// Quitting cancels the run but keeps draining it until the probe returns
+		quit := false
+		runKeys, interrupted := keys, ctx.Done()
+		stop := func() {
+			quit = true
+			runKeys, interrupted = nil, nil
+			cancelRun()
+		}
+		for view.running {
+			view.draw(os.Stdout)
+			select {
+			case result := <-streamed:
+				view.add(result)
+			case results := <-done:
+				last = results
+				view.finish(time.Since(view.started))
+			case key, ok := <-runKeys:
+				if !ok || key == keyQuit {
+					stop()
+					continue
+				}
+				view.handle(key)
+			case <-ticker.C:
+				view.frame++
+			case <-interrupted:
+				stop()
+			}
+		}
+		cancelRun()
+		ticker.Stop()
+		if quit {
+			return last, nil
+		}
+
+		// Wait for a re-run or quit once everything has finished
+		for !view.running {
+			view.draw(os.Stdout)
+			select {
+			case key, ok := <-keys:
+				if !ok || key == keyQuit {
+					return last, nil
+				}
+				if key == keyRerun {
+					view.running = true
+					continue
+				}
+				view.handle(key)
+			case <-ctx.Done():
+				return last, nil
+			}
+		}
+	}
+}
+
+// handle applies a navigation key
+func (t *tui) handle(key tuiKey) {
+	switch key {
+	case keyUp:
+		t.cursor = max(t.cursor-1, 0)
+	case keyDown:
+		t.cursor = min(t.cursor+1, len(t.rows)-1)
+	case keyToggle:
+		if t.cursor < len(t.rows) {
+			t.rows[t.cursor].expanded = !t.rows[t.cursor].expanded
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReadKeys(t *testing.T) {
+	keys := make(chan tuiKey)
+	go readKeys(strings.NewReader("j\x1b[Ak \rxrq"), keys)
+	var got []tuiKey
+	for key := range keys {
+		got = append(got, key)
+	}
+	want := []tuiKey{keyDown, keyUp, keyUp, keyToggle, keyToggle, keyRerun, keyQuit}
+	if !slices.Equal(got, want) {
+		t.Errorf("readKeys = %v, want %v", got, want)
+	}
+}
+
+func TestTUIRows(t *testing.T) {
+	cfg := newConfig()
+	cfg.Regions = []string{"us-east-1"}
+	cfg.Checks = []string{"dns", "local"}
+	view := &tui{rows: newTUIRun(cfg, false), running: true, plain: true}
+	view.add(CheckResult{Name: "DNS - Bedrock Runtime", Status: "fail", Message: "no such host", Fix: "Check DNS", Region: "us-east-1", check: "dns"})
+	view.add(CheckResult{Name: "AWS_REGION", Status: "pass", Message: "us-east-1 from AWS_REGION"})
+
+	var buf bytes.Buffer
+	view.draw(&buf)
+	got := buf.String()
+	for _, want := range []string{"> [FAIL] DNS - Bedrock Runtime: no such host", "|", "[PASS] AWS_REGION"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("running view missing %q:\n%s", want, got)
+		}
+	}
+
+	view.handle(keyToggle)
+	view.finish(0)
+	buf.Reset()
+	view.draw(&buf)
+	if got := buf.String(); !strings.Contains(got, "Fix: Check DNS") || !strings.Contains(got, "Local Storage: no results") || !strings.Contains(got, "r re-run") {
+		t.Errorf("finished view with the first row expanded:\n%s", got)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package main
+
+import "errors"
+
+// enableRawMode is not implemented on Windows, where --tui falls back to the plain report
+func enableRawMode() (func(), error) {
+	return nil, errors.New("the live view needs a Unix terminal")
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Large enough to need many round trips of window growth, small enough to
+	// finish in well under a second on any usable link
+	throughputPayloadSize = 256 << 10
+	// Below this a link that connected fine is too slow for large prompts
+	minThroughput = 64 << 10
+)
+
+// countingReader counts the bytes the transport has taken from the body
+type countingReader struct {
+	r    io.Reader
+	read atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+// uploadTimes records the trace events of one upload. The transport reports
+// them from its own goroutines, so they are guarded.
+type uploadTimes struct {
+	mu        sync.Mutex
+	connected time.Time
+	wrote     time.Time
+}
+
+func (u *uploadTimes) set(at *time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	*at = time.Now()
+}
+
+func (u *uploadTimes) get() (connected, wrote time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.connected, u.wrote
+}
+
+// upload measures one unauthenticated HTTPS POST of a fixed payload
+type upload struct {
+	connected bool
+	connect   time.Duration
+	transfer  time.Duration
+	sent      int64
+	// early is set when the server answered before taking the whole body
+	early bool
+	err   error
+}
+
+// runThroughputChecks uploads a fixed payload to Bedrock Runtime and measures
+// the throughput. VPNs and overlays with asymmetric routes or broken window
+// scaling let the connect through and then stall once the window has to grow,
+// which reachability checks never see.
+func runThroughputChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	name := "Upload Throughput - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	url := "https://" + cfg.endpointAddr("bedrock-runtime", region) + "/model/bcce-doctor-throughput/invoke"
+	target := cfg.endpointHost("bedrock-runtime", region) + cfg.viaProxy()
+
+	start := time.Now()
+	result := uploadResult(name, target, measureUpload(ctx, cfg, url, nil), minThroughput)
+	result.DurationMs = time.Since(start).Milliseconds()
+	return []CheckResult{result}
+}
+
+// measureUpload POSTs the payload to url; tlsConfig is nil outside tests
+func measureUpload(ctx context.Context, cfg *Config, url string, tlsConfig *tls.Config) upload {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	var times uploadTimes
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn:      func(httptrace.GotConnInfo) { times.set(&times.connected) },
+		WroteRequest: func(httptrace.WroteRequestInfo) { times.set(&times.wrote) },
+	})
+	body := &countingReader{r: bytes.NewReader(make([]byte, throughputPayloadSize))}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return upload{err: err}
+	}
+	req.ContentLength = throughputPayloadSize
+	req.Header.Set("Content-Type", "application/json")
+
+	// A fresh HTTP/1.1 transport, so the payload goes straight onto one new connection
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, DialContext: cfg.dialFunc(), TLSClientConfig: tlsConfig},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	logger.Debug("uploading throughput payload", "url", url, "bytes", throughputPayloadSize)
+	start := time.Now()
+	resp, err := client.Do(req)
+	answered := time.Now()
+	if err == nil {
+		resp.Body.Close()
+	}
+	client.CloseIdleConnections()
+
+	connected, wrote := times.get()
+	measured := upload{sent: body.read.Load(), err: err}
+	if connected.IsZero() {
+		return measured
+	}
+	measured.connected = true
+	measured.connect = connected.Sub(start)
+	measured.transfer = answered.Sub(connected)
+	measured.early = err == nil && (wrote.IsZero() || measured.sent < throughputPayloadSize)
+	logger.Debug("throughput upload finished", "connect", measured.connect, "transfer", measured.transfer, "sent", measured.sent, "early", measured.early, "error", err)
+	return measured
+}
+
+// uploadResult judges an upload against minRate bytes per second
+func uploadResult(name, target string, u upload, minRate int64) CheckResult {
+	payload := byteSize(throughputPayloadSize)
+	fix := "Connections succeed but large transfers stall: check the VPN or overlay for asymmetric routes, a missing MSS clamp or a firewall that strips TCP window scaling, and run --mtu to rule out a Path MTU black hole"
+	switch {
+	case !u.connected:
+		return CheckResult{
+			Name:      name,
+			Status:    "fail",
+			Message:   fmt.Sprintf("Could not connect to %s to measure throughput: %v", target, u.err),
+			Fix:       "Check proxy settings and that outbound HTTPS to AWS is allowed; the TCP and TLS checks show where the connection fails",
+			ErrorKind: newProbeError("", u.err).Kind,
+		}
+	case u.err != nil:
+		return CheckResult{
+			Name:      name,
+			Status:    "warn",
+			Message:   fmt.Sprintf("Connected to %s in %s, but uploading %s stalled after %s: %v", target, u.connect.Round(time.Millisecond), payload, byteSize(u.sent), u.err),
+			Fix:       fix,
+			ErrorKind: newProbeError("", u.err).Kind,
+		}
+	case u.early:
+		return CheckResult{
+			Name:    name,
+			Status:  "pass",
+			Message: fmt.Sprintf("Connected to %s in %s; it answered before taking the whole %s payload, so throughput could not be measured", target, u.connect.Round(time.Millisecond), payload),
+		}
+	}
+
+	rate := int64(float64(u.sent) / max(u.transfer.Seconds(), 0.001))
+	message := fmt.Sprintf("Uploaded %s to %s at %s/s (%s after connecting in %s)", payload, target, byteSize(rate), u.transfer.Round(time.Millisecond), u.connect.Round(time.Millisecond))
+	if rate < minRate {
+		return CheckResult{
+			Name:    name,
+			Status:  "warn",
+			Message: fmt.Sprintf("%s, below %s/s", message, byteSize(minRate)),
+			Fix:     fix,
+		}
+	}
+	return CheckResult{
+		Name:    name,
+		Status:  "pass",
+		Message: message,
+	}
+}
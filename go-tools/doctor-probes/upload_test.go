@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeasureUpload(t *testing.T) {
+	// Like Bedrock, read the whole body before refusing the unsigned request
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	cfg := newConfig()
+	tlsConfig := &tls.Config{RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}
+	u := measureUpload(context.Background(), cfg, server.URL+"/model/bcce-doctor-throughput/invoke", tlsConfig)
+	if u.err != nil || !u.connected || u.early {
+		t.Fatalf("measureUpload() = %+v, want a measured upload", u)
+	}
+	if u.sent != throughputPayloadSize || u.transfer <= 0 {
+		t.Errorf("sent %d bytes in %s, want the whole %d byte payload", u.sent, u.transfer, throughputPayloadSize)
+	}
+}
+
+func TestUploadResult(t *testing.T) {
+	target := "bedrock-runtime.us-east-1.amazonaws.com"
+	tests := []struct {
+		name    string
+		upload  upload
+		status  string
+		message string
+	}{
+		{"fast", upload{connected: true, connect: 30 * time.Millisecond, transfer: 100 * time.Millisecond, sent: throughputPayloadSize}, "pass", "at 2.5 MiB/s"},
+		{"crawling", upload{connected: true, connect: 30 * time.Millisecond, transfer: 8 * time.Second, sent: throughputPayloadSize}, "warn", "below 64.0 KiB/s"},
+		{"stalled", upload{connected: true, connect: 30 * time.Millisecond, sent: 96 << 10, err: errors.New("i/o timeout")}, "warn", "stalled after 96.0 KiB"},
+		{"early answer", upload{connected: true, connect: 30 * time.Millisecond, early: true}, "pass", "could not be measured"},
+		{"no connection", upload{err: errors.New("connection refused")}, "fail", "Could not connect"},
+	}
+	for _, tt := range tests {
+		got := uploadResult("Upload Throughput - Bedrock Runtime", target, tt.upload, minThroughput)
+		if got.Status != tt.status || !strings.Contains(got.Message, tt.message) {
+			t.Errorf("%s: got %s %q, want %s containing %q", tt.name, got.Status, got.Message, tt.status, tt.message)
+		}
+	}
+}
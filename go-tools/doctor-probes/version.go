@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// Build metadata, set by release builds with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..."
+var (
+	version   string
+	commit    string
+	buildDate string
+)
+
+// Without ldflags (e.g. go run or go install), fall back to what the Go toolchain embedded
+func init() {
+	info, ok := debug.ReadBuildInfo()
+	if ok {
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if commit == "" {
+					commit = setting.Value
+				}
+			case "vcs.time":
+				if buildDate == "" {
+					buildDate = setting.Value
+				}
+			}
+		}
+		if version == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		}
+	}
+	if version == "" {
+		version = "dev"
+		if len(commit) >= 12 {
+			version = "dev-" + commit[:12]
+		}
+	}
+}
+
+func writeVersion(w io.Writer) {
+	fmt.Fprintf(w, "doctor-probes %s\n", version)
+	if commit != "" {
+		fmt.Fprintf(w, "commit: %s\n", commit)
+	}
+	if buildDate != "" {
+		fmt.Fprintf(w, "built:  %s\n", buildDate)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The Route 53 Resolver also answers on these link-local addresses in every VPC
+var vpcLinkLocalResolvers = []string{"169.254.169.253", "fd00:ec2::253"}
+
+// ec2VPCCIDR returns the instance's primary VPC IPv4 CIDR; ok is false when not running on EC2
+func ec2VPCCIDR(ctx context.Context) (netip.Prefix, bool, error) {
+	endpoint := imdsEndpoint()
+	tokenHeader := http.Header{}
+	tokenHeader.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	status, token, err := metadataRequest(ctx, http.MethodPut, endpoint+"/latest/api/token", tokenHeader)
+	if err != nil {
+		return netip.Prefix{}, false, nil
+	}
+	if status != http.StatusOK {
+		return netip.Prefix{}, true, fmt.Errorf("IMDSv2 token request returned HTTP %d", status)
+	}
+
+	header := http.Header{}
+	header.Set("X-aws-ec2-metadata-token", token)
+	status, mac, err := metadataRequest(ctx, http.MethodGet, endpoint+"/latest/meta-data/mac", header)
+	if err != nil || status != http.StatusOK {
+		return netip.Prefix{}, true, fmt.Errorf("could not read the primary interface MAC address (HTTP %d): %v", status, err)
+	}
+	url := fmt.Sprintf("%s/latest/meta-data/network/interfaces/macs/%s/vpc-ipv4-cidr-block", endpoint, strings.TrimSpace(mac))
+	status, cidr, err := metadataRequest(ctx, http.MethodGet, url, header)
+	if err != nil || status != http.StatusOK {
+		return netip.Prefix{}, true, fmt.Errorf("could not read the VPC CIDR block (HTTP %d): %v", status, err)
+	}
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return netip.Prefix{}, true, fmt.Errorf("unexpected VPC CIDR block %q", cidr)
+	}
+	return prefix, true, nil
+}
+
+// vpcResolvers returns the addresses the VPC's Route 53 Resolver listens on: the
+// base of the VPC CIDR plus two, and the link-local aliases
+func vpcResolvers(cidr netip.Prefix) []string {
+	addr := cidr.Masked().Addr().Next().Next()
+	return append([]string{addr.String()}, vpcLinkLocalResolvers...)
+}
+
+// resolverHost strips the port from a host:port nameserver address
+func resolverHost(server string) string {
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return server
+	}
+	return host
+}
+
+// lookupVia resolves host against a specific nameserver
+func lookupVia(ctx context.Context, host, server string, timeout time.Duration) ([]netip.Addr, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+	return resolver.LookupNetIP(ctx, "ip", host)
+}
+
+func allPrivate(addrs []netip.Addr) bool {
+	for _, addr := range addrs {
+		if !addr.Unmap().IsPrivate() {
+			return false
+		}
+	}
+	return len(addrs) > 0
+}
+
+// runVPCDNSChecks checks that the Bedrock hostname is resolved by the VPC's
+// Route 53 Resolver when running on EC2, which is what makes PrivateLink
+// endpoints and private hosted zones visible. This is a heuristic: a private
+// resolver may well forward to the VPC resolver, so it only ever warns.
+func runVPCDNSChecks(ctx context.Context, cfg *Config, region string) []CheckResult {
+	name := "VPC DNS - " + cfg.endpointLabel("Bedrock Runtime", "bedrock-runtime")
+	host := cfg.endpointHost("bedrock-runtime", region)
+
+	if _, ok := lambdaFunction(); ok {
+		return lambdaSkipsIMDS(name)
+	}
+	start := time.Now()
+	cidr, onEC2, err := ec2VPCCIDR(ctx)
+	if !onEC2 {
+		return []CheckResult{{
+			Name:    name,
+			Status:  "pass",
+			Message: "Not running on EC2 (instance metadata not reachable); VPC DNS doesn't apply",
+		}}
+	}
+	if err != nil {
+		return []CheckResult{{
+			Name:       name,
+			Status:     "warn",
+			Message:    fmt.Sprintf("Running on EC2 but the VPC could not be determined: %v", err),
+			Fix:        "If running in a container on EC2, raise the instance's HttpPutResponseHopLimit to 2",
+			DurationMs: time.Since(start).Milliseconds(),
+			reason:     reasonVPCUnknown,
+		}}
+	}
+
+	server := cfg.Resolver
+	if server == "" {
+		server = systemNameserver()
+	}
+	current := resolverHost(server)
+	resolvers := vpcResolvers(cidr)
+	vpcServer := net.JoinHostPort(resolvers[0], "53")
+
+	// The VPC resolver's own answer shows whether an interface endpoint is in play
+	vpcAddrs, vpcErr := lookupVia(ctx, host, vpcServer, cfg.Timeout)
+	privateLink := vpcErr == nil && allPrivate(vpcAddrs)
+	durationMs := time.Since(start).Milliseconds()
+
+	currentAddr, parseErr := netip.ParseAddr(current)
+	switch {
+	case slices.Contains(resolvers, current):
+		message := fmt.Sprintf("Using the VPC resolver %s (VPC %s)", current, cidr)
+		if privateLink {
+			message += fmt.Sprintf("; %s resolves to private addresses, so a PrivateLink endpoint is in use", host)
+		}
+		return []CheckResult{{Name: name, Status: "pass", Message: message, DurationMs: durationMs}}
+	case parseErr == nil && currentAddr.IsLoopback():
+		return []CheckResult{{
+			Name:       name,
+			Status:     "pass",
+			Message:    fmt.Sprintf("Using the local stub resolver %s; assuming it forwards to the VPC resolver %s", current, resolvers[0]),
+			DurationMs: durationMs,
+		}}
+	case parseErr == nil && !currentAddr.IsPrivate() && !currentAddr.IsLinkLocalUnicast():
+		fix := fmt.Sprintf("Use the VPC resolver %s (enable DNS resolution and DNS hostnames on the VPC), or forward amazonaws.com to it", resolvers[0])
+		message := fmt.Sprintf("Resolving through the public resolver %s instead of the VPC resolver %s", current, resolvers[0])
+		if privateLink {
+			message += fmt.Sprintf("; the VPC resolver returns private addresses for %s, so traffic is likely bypassing the PrivateLink endpoint", host)
+		} else {
+			message += "; this only matters if the VPC has a PrivateLink endpoint or private hosted zone for Bedrock"
+		}
+		return []CheckResult{{Name: name, Status: "warn", Message: message, Fix: fix, DurationMs: durationMs, reason: reasonVPCEndpoint}}
+	}
+
+	// A private, non-VPC resolver is usually a directory DNS server with conditional forwarders
+	message := fmt.Sprintf("Using the custom resolver %s rather than the VPC resolver %s", current, resolvers[0])
+	if !privateLink {
+		return []CheckResult{{Name: name, Status: "pass", Message: message, DurationMs: durationMs}}
+	}
+	currentAddrs, err := lookupVia(ctx, host, server, cfg.Timeout)
+	if err == nil && !allPrivate(currentAddrs) {
+		return []CheckResult{{
+			Name:       name,
+			Status:     "warn",
+			Message:    message + fmt.Sprintf("; it returns public addresses for %s while the VPC resolver returns the PrivateLink endpoint", host),
+			Fix:        fmt.Sprintf("Add a conditional forwarder for amazonaws.com to %s on the custom resolver", resolvers[0]),
+			DurationMs: time.Since(start).Milliseconds(),
+			reason:     reasonVPCEndpoint,
+		}}
+	}
+	return []CheckResult{{Name: name, Status: "pass", Message: message + "; it returns the same private PrivateLink addresses", DurationMs: time.Since(start).Milliseconds()}}
+}
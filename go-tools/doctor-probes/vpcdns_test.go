@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVPCResolvers(t *testing.T) {
+	got := vpcResolvers(netip.MustParsePrefix("10.20.0.0/16"))
+	want := []string{"10.20.0.2", "169.254.169.253", "fd00:ec2::253"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("vpcResolvers = %v, want %v", got, want)
+	}
+}
+
+func TestVPCDNSWarnsOnPublicResolver(t *testing.T) {
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/api/token":
+			w.Write([]byte("token"))
+		case "/latest/meta-data/mac":
+			w.Write([]byte("0a:00:00:00:00:01"))
+		case "/latest/meta-data/network/interfaces/macs/0a:00:00:00:00:01/vpc-ipv4-cidr-block":
+			w.Write([]byte("10.20.0.0/16"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer imds.Close()
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", imds.URL)
+
+	cfg := newConfig()
+	cfg.Resolver = "8.8.8.8:53"
+	cfg.Timeout = 50 * time.Millisecond
+
+	results := runVPCDNSChecks(context.Background(), cfg, "us-east-1")
+	if len(results) != 1 || results[0].Status != "warn" {
+		t.Fatalf("results = %+v, want one warn", results)
+	}
+	if !strings.Contains(results[0].Fix, "10.20.0.2") {
+		t.Errorf("fix %q does not name the VPC resolver", results[0].Fix)
+	}
+}
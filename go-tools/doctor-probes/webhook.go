@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Long enough for a slow collector, short enough not to hold up a CI job
+const defaultWebhookTimeout = 10 * time.Second
+
+// headerList collects repeated --webhook-header flags
+type headerList []string
+
+func (h *headerList) String() string { return strings.Join(*h, ", ") }
+
+func (h *headerList) Set(value string) error {
+	if _, _, err := parseHeader(value); err != nil {
+		return err
+	}
+	*h = append(*h, value)
+	return nil
+}
+
+// parseHeader splits a "Name: value" header
+func parseHeader(header string) (name, value string, err error) {
+	name, value, ok := strings.Cut(header, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("header %q is not in Name: value form", header)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// validWebhookURL accepts absolute http and https URLs
+func validWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http or https URL")
+	}
+	return nil
+}
+
+// postWebhook sends the report as JSON to --webhook. A delivery failure comes
+// back as a warning to add to the report; nil means it was delivered.
+func postWebhook(ctx context.Context, cfg *Config, output ProbeOutput) *CheckResult {
+	name := "Webhook - " + webhookHost(cfg.Webhook)
+	start := time.Now()
+	err := sendWebhook(ctx, cfg, output)
+	logger.Debug("webhook delivery finished", "url", cfg.Webhook, "error", err)
+	if err == nil {
+		return nil
+	}
+	return &CheckResult{
+		Name:       name,
+		Status:     "warn",
+		Message:    fmt.Sprintf("Failed to deliver the report: %v", err),
+		Fix:        "Check that the webhook URL is reachable from here and accepts a JSON POST with the configured headers",
+		Category:   "config",
+		ErrorKind:  newProbeError("", err).Kind,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+}
+
+func sendWebhook(ctx context.Context, cfg *Config, output ProbeOutput) error {
+	body, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.WebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, header := range cfg.WebhookHeaders {
+		name, value, _ := parseHeader(header)
+		req.Header.Set(name, value)
+	}
+
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}}
+	resp, err := client.Do(req)
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// The client's error quotes the full URL
+		return fmt.Errorf("POST %s: %w", webhookHost(cfg.Webhook), urlErr.Err)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("POST %s returned %s", webhookHost(cfg.Webhook), resp.Status)
+	}
+	return nil
+}
+
+// webhookHost names the webhook without its path or query, which often carry a token
+func webhookHost(raw string) string {
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return raw
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPostWebhook(t *testing.T) {
+	var got ProbeOutput
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding webhook body: %v", err)
+		}
+		if strings.Contains(r.URL.RawQuery, "fail") {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	results := []CheckResult{{Name: "DNS - Bedrock Runtime", Status: "pass"}}
+	output := newProbeOutput(results, []string{"us-east-1"})
+	cfg := newConfig()
+	cfg.Webhook = server.URL + "/report"
+	cfg.WebhookHeaders = []string{"Authorization: Bearer secret"}
+
+	if check := postWebhook(context.Background(), cfg, output); check != nil {
+		t.Fatalf("postWebhook() = %+v, want delivered", check)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the --webhook-header value", auth)
+	}
+	if len(got.Checks) != 1 || got.Summary == nil || got.Summary.Total != 1 {
+		t.Errorf("webhook received %+v, want the report", got)
+	}
+
+	cfg.Webhook = server.URL + "/report?token=fail"
+	check := postWebhook(context.Background(), cfg, output)
+	if check == nil || check.Status != "warn" {
+		t.Fatalf("postWebhook() = %+v, want a warning for a 502", check)
+	}
+	if !strings.Contains(check.Message, "502") || strings.Contains(check.Message, "token") {
+		t.Errorf("message = %q, want the status without the URL's query", check.Message)
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	if name, value, err := parseHeader("X-Api-Key:  abc:def "); err != nil || name != "X-Api-Key" || value != "abc:def" {
+		t.Errorf("parseHeader() = %q, %q, %v", name, value, err)
+	}
+	for _, bad := range []string{"Authorization", ": value", "Bad Name: value"} {
+		if _, _, err := parseHeader(bad); err == nil {
+			t.Errorf("parseHeader(%q) succeeded, want an error", bad)
+		}
+	}
+}